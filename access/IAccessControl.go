@@ -0,0 +1,91 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package access
+
+import "github.com/studyzy/openzeppelin-go/common"
+
+const (
+	// AdminRole 默认的管理员角色，也是其他角色默认的管理角色
+	AdminRole = "ADMIN"
+	// MinterRole 铸币角色
+	MinterRole = "MINTER"
+	// PauserRole 暂停角色
+	PauserRole = "PAUSER"
+	// ComplianceRole 合规角色，可以冻结账户和强制划转资金
+	ComplianceRole = "COMPLIANCE"
+	// ProposerRole 提案角色，可以在时间锁中安排操作
+	ProposerRole = "PROPOSER"
+	// ExecutorRole 执行角色，可以执行时间锁中已经到期的操作
+	ExecutorRole = "EXECUTOR"
+	// CancellerRole 取消角色，可以取消时间锁中尚未执行的操作
+	CancellerRole = "CANCELLER"
+)
+
+/**
+ * @dev External interface of AccessControl declared to support ERC165 detection.
+ */
+type IAccessControl interface {
+	/**
+	 * @dev Returns `true` if `account` has been granted `role`.
+	 */
+	HasRole(role string, account common.Account) (bool, error)
+
+	/**
+	 * @dev Returns the admin role that controls `role`. See {grantRole} and
+	 * {revokeRole}.
+	 *
+	 * To change a role's admin, use {AccessControl-_setRoleAdmin}.
+	 */
+	GetRoleAdmin(role string) (string, error)
+
+	/**
+	 * @dev Grants `role` to `account`.
+	 *
+	 * If `account` had not been already granted `role`, emits a {RoleGranted}
+	 * event.
+	 *
+	 * Requirements:
+	 *
+	 * - the caller must have ``role``'s admin role.
+	 */
+	GrantRole(role string, account common.Account) error
+
+	/**
+	 * @dev Revokes `role` from `account`.
+	 *
+	 * If `account` had been granted `role`, emits a {RoleRevoked} event.
+	 *
+	 * Requirements:
+	 *
+	 * - the caller must have ``role``'s admin role.
+	 */
+	RevokeRole(role string, account common.Account) error
+
+	/**
+	 * @dev Revokes `role` from the calling account.
+	 *
+	 * Roles are often managed via {grantRole} and {revokeRole}: this function's
+	 * purpose is to provide a mechanism for accounts to lose their privileges
+	 * if they are compromised (such as when a trusted device is misplaced).
+	 *
+	 * If the calling account had been granted `role`, emits a {RoleRevoked}
+	 * event.
+	 *
+	 * Requirements:
+	 *
+	 * - the caller must be `account`.
+	 */
+	RenounceRole(role string, account common.Account) error
+}
@@ -0,0 +1,195 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package access
+
+import (
+	"fmt"
+
+	"github.com/studyzy/openzeppelin-go/common"
+)
+
+var _ IAccessControl = (*AccessControl)(nil)
+
+/**
+ * @dev Contract module that allows children to implement role-based access
+ * control mechanisms.
+ *
+ * Roles can be granted and revoked dynamically via the {grantRole} and
+ * {revokeRole} functions. Each role has an associated admin role, and only
+ * accounts that have a role's admin role can call {grantRole} and {revokeRole}.
+ *
+ * By default, the admin role for all roles is `AdminRole`, which means
+ * that only accounts with this role will be able to grant or revoke other
+ * roles. More complex role relationships can be created by using
+ * {SetRoleAdmin}.
+ *
+ * 角色数据通过common.StateOperator持久化，可以直接嵌入到各个Token合约中使用。
+ */
+type AccessControl struct {
+	dal *AccessControlDAL
+	sdk common.ContractSDK
+}
+
+func NewAccessControl(sdk common.ContractSDK) *AccessControl {
+	return &AccessControl{
+		sdk: sdk,
+		dal: NewAccessControlDAL(sdk),
+	}
+}
+
+func (c *AccessControl) SetSDK(sdk common.ContractSDK) {
+	c.sdk = sdk
+	c.dal = NewAccessControlDAL(sdk)
+}
+
+func (c *AccessControl) HasRole(role string, account common.Account) (bool, error) {
+	return c.dal.HasRole(role, account)
+}
+
+func (c *AccessControl) GetRoleAdmin(role string) (string, error) {
+	return c.dal.GetRoleAdmin(role)
+}
+
+// RolesOf 返回account持有的所有角色
+func (c *AccessControl) RolesOf(account common.Account) ([]string, error) {
+	return c.dal.GetRoles(account)
+}
+
+/**
+ * @dev Revert with a standard message if `account` is missing `role`.
+ *
+ * The format of the revert reason is given by the following regular expression:
+ *
+ *  /^AccessControl: account (0x[0-9a-f]{40}) is missing role (0x[0-9a-f]{64})$/
+ */
+func (c *AccessControl) CheckRole(role string, account common.Account) error {
+	has, err := c.dal.HasRole(role, account)
+	if err != nil {
+		return err
+	}
+	if !has {
+		return fmt.Errorf("AccessControl: account %s is missing role %s", account.ToString(), role)
+	}
+	return nil
+}
+
+// OnlyRole 检查交易发送者是否拥有role角色
+func (c *AccessControl) OnlyRole(role string) error {
+	sender, err := c.sdk.GetTxSender()
+	if err != nil {
+		return fmt.Errorf("Get sender address failed, err:%s", err)
+	}
+	return c.CheckRole(role, sender)
+}
+
+func (c *AccessControl) GrantRole(role string, account common.Account) error {
+	adminRole, err := c.dal.GetRoleAdmin(role)
+	if err != nil {
+		return err
+	}
+	if err = c.OnlyRole(adminRole); err != nil {
+		return err
+	}
+	return c.SetupRole(role, account)
+}
+
+func (c *AccessControl) RevokeRole(role string, account common.Account) error {
+	adminRole, err := c.dal.GetRoleAdmin(role)
+	if err != nil {
+		return err
+	}
+	if err = c.OnlyRole(adminRole); err != nil {
+		return err
+	}
+	return c.baseRevokeRole(role, account)
+}
+
+func (c *AccessControl) RenounceRole(role string, account common.Account) error {
+	sender, err := c.sdk.GetTxSender()
+	if err != nil {
+		return fmt.Errorf("Get sender address failed, err:%s", err)
+	}
+	err = common.Require(account.Equal(sender), "AccessControl: can only renounce roles for self")
+	if err != nil {
+		return err
+	}
+	return c.baseRevokeRole(role, account)
+}
+
+/**
+ * @dev Grants `role` to `account`.
+ *
+ * Internal function without access restriction, 一般在合约初始化时调用来设置初始的角色。
+ *
+ * May emit a {RoleGranted} event.
+ */
+func (c *AccessControl) SetupRole(role string, account common.Account) error {
+	has, err := c.dal.HasRole(role, account)
+	if err != nil {
+		return err
+	}
+	if has {
+		return nil
+	}
+	if err = c.dal.SetRole(role, account, true); err != nil {
+		return err
+	}
+	sender, err := c.sdk.GetTxSender()
+	if err != nil {
+		return fmt.Errorf("Get sender address failed, err:%s", err)
+	}
+	return c.sdk.EmitEvent("roleGranted", role, account.ToString(), sender.ToString())
+}
+
+/**
+ * @dev Sets `adminRole` as ``role``'s admin role.
+ *
+ * Internal function without access restriction.
+ *
+ * Emits a {RoleAdminChanged} event.
+ */
+func (c *AccessControl) SetRoleAdmin(role string, adminRole string) error {
+	previousAdminRole, err := c.dal.GetRoleAdmin(role)
+	if err != nil {
+		return err
+	}
+	if err = c.dal.SetRoleAdmin(role, adminRole); err != nil {
+		return err
+	}
+	return c.sdk.EmitEvent("roleAdminChanged", role, previousAdminRole, adminRole)
+}
+
+/**
+ * @dev Revokes `role` from `account`.
+ *
+ * May emit a {RoleRevoked} event.
+ */
+func (c *AccessControl) baseRevokeRole(role string, account common.Account) error {
+	has, err := c.dal.HasRole(role, account)
+	if err != nil {
+		return err
+	}
+	if !has {
+		return nil
+	}
+	if err = c.dal.SetRole(role, account, false); err != nil {
+		return err
+	}
+	sender, err := c.sdk.GetTxSender()
+	if err != nil {
+		return fmt.Errorf("Get sender address failed, err:%s", err)
+	}
+	return c.sdk.EmitEvent("roleRevoked", role, account.ToString(), sender.ToString())
+}
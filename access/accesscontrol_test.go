@@ -0,0 +1,104 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package access
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/studyzy/openzeppelin-go/mock"
+)
+
+func TestAccessControl(t *testing.T) {
+	chain, roles, _ := newOwnable(t)
+	if err := chain.Invoke(bob, func() error { return roles.GrantRole(MinterRole, bob) }); err == nil {
+		t.Fatal("account without admin role should not grant")
+	}
+	if err := chain.Invoke(alice, func() error { return roles.GrantRole(MinterRole, bob) }); err != nil {
+		t.Fatal(err)
+	}
+	if has, _ := roles.HasRole(MinterRole, bob); !has {
+		t.Fatal("role not granted")
+	}
+	if got := rolesOf(t, roles, bob); !reflect.DeepEqual(got, []string{MinterRole}) {
+		t.Fatalf("roles of bob = %v", got)
+	}
+	if err := chain.Invoke(bob, func() error { return roles.RenounceRole(MinterRole, alice) }); err == nil {
+		t.Fatal("can only renounce roles for self")
+	}
+	if err := chain.Invoke(bob, func() error { return roles.RenounceRole(MinterRole, bob) }); err != nil {
+		t.Fatal(err)
+	}
+	if has, _ := roles.HasRole(MinterRole, bob); has {
+		t.Fatal("role not renounced")
+	}
+	if len(chain.EventsByTopic("roleGranted")) != 4 || len(chain.EventsByTopic("roleRevoked")) != 1 {
+		t.Fatal("missing role events")
+	}
+}
+
+func TestRevokeRole(t *testing.T) {
+	chain, roles, _ := newOwnable(t)
+	if err := chain.Invoke(alice, func() error { return roles.GrantRole(PauserRole, bob) }); err != nil {
+		t.Fatal(err)
+	}
+	if err := chain.Invoke(bob, func() error { return roles.RevokeRole(PauserRole, alice) }); err == nil {
+		t.Fatal("account without admin role should not revoke")
+	}
+	if err := chain.Invoke(alice, func() error { return roles.RevokeRole(PauserRole, bob) }); err != nil {
+		t.Fatal(err)
+	}
+	if err := roles.CheckRole(PauserRole, bob); err == nil {
+		t.Fatal("role not revoked")
+	}
+}
+
+func TestSetRoleAdmin(t *testing.T) {
+	chain, roles, _ := newOwnable(t)
+	//把铸币角色的管理员改成暂停角色之后，只有暂停角色能授予铸币角色
+	err := chain.Invoke(alice, func() error {
+		if err := roles.SetRoleAdmin(MinterRole, PauserRole); err != nil {
+			return err
+		}
+		return roles.RevokeRole(PauserRole, alice)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if admin, _ := roles.GetRoleAdmin(MinterRole); admin != PauserRole {
+		t.Fatalf("minter admin = %s, want %s", admin, PauserRole)
+	}
+	if err = chain.Invoke(alice, func() error { return roles.GrantRole(MinterRole, bob) }); err == nil {
+		t.Fatal("admin role holder should not grant a role administered by another role")
+	}
+	if err = chain.Invoke(alice, func() error { return roles.GrantRole(PauserRole, bob) }); err != nil {
+		t.Fatal(err)
+	}
+	if err = chain.Invoke(bob, func() error { return roles.GrantRole(MinterRole, bob) }); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOnlyCreator(t *testing.T) {
+	chain := mock.NewChain()
+	chain.SetSender(alice)
+	sdk := chain.Deploy("token", nil)
+	if err := chain.Invoke(bob, func() error { return OnlyCreator(sdk) }); err == nil {
+		t.Fatal("only the creator should pass")
+	}
+	if err := chain.Invoke(alice, func() error { return OnlyCreator(sdk) }); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package access
+
+import (
+	"fmt"
+
+	"github.com/studyzy/openzeppelin-go/common"
+)
+
+// OnlyCreator 检查当前交易的发送者是部署合约的账户，用来限制只能由部署者调用的初始化方法
+func OnlyCreator(sdk common.ContractSDK) error {
+	isCreator, err := sdk.IsTxSenderCreator()
+	if err != nil {
+		return fmt.Errorf("Get contract creator failed, err:%s", err)
+	}
+	return common.Require(isCreator, "Access: caller is not the contract creator")
+}
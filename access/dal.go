@@ -0,0 +1,146 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package access
+
+import (
+	"bytes"
+
+	"github.com/studyzy/openzeppelin-go/common"
+)
+
+const (
+	roleMemberKey = "role"
+	roleAdminKey  = "roleAdmin"
+)
+
+type AccessControlDAL struct {
+	sdk common.StateOperator
+}
+
+func NewAccessControlDAL(sdk common.StateOperator) *AccessControlDAL {
+	return &AccessControlDAL{sdk: sdk}
+}
+
+func (c *AccessControlDAL) HasRole(role string, account common.Account) (bool, error) {
+	key, err := c.sdk.CreateCompositeKey(roleMemberKey, role, account.ToString())
+	if err != nil {
+		return false, err
+	}
+	b, err := c.sdk.GetState(key)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(b, []byte("true")), nil
+}
+func (c *AccessControlDAL) SetRole(role string, account common.Account, granted bool) error {
+	key, err := c.sdk.CreateCompositeKey(roleMemberKey, role, account.ToString())
+	if err != nil {
+		return err
+	}
+	if !granted {
+		return c.sdk.DelState(key)
+	}
+	return c.sdk.PutState(key, []byte("true"))
+}
+
+// GetRoles 遍历所有角色成员记录，返回account持有的所有角色
+func (c *AccessControlDAL) GetRoles(account common.Account) ([]string, error) {
+	it, err := c.sdk.NewIteratorWithCompositeKey(roleMemberKey)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	var roles []string
+	for it.HasNext() {
+		key, value, err := it.Next()
+		if err != nil {
+			return nil, err
+		}
+		_, parts, err := c.sdk.SplitCompositeKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if len(parts) == 2 && parts[1] == account.ToString() && bytes.Equal(value, []byte("true")) {
+			roles = append(roles, parts[0])
+		}
+	}
+	return roles, nil
+}
+
+// GetRoleAdmin 没有设置过管理角色的角色，默认由AdminRole管理
+func (c *AccessControlDAL) GetRoleAdmin(role string) (string, error) {
+	key, err := c.sdk.CreateCompositeKey(roleAdminKey, role)
+	if err != nil {
+		return "", err
+	}
+	b, err := c.sdk.GetState(key)
+	if err != nil {
+		return "", err
+	}
+	if len(b) == 0 {
+		return AdminRole, nil
+	}
+	return string(b), nil
+}
+func (c *AccessControlDAL) SetRoleAdmin(role string, adminRole string) error {
+	key, err := c.sdk.CreateCompositeKey(roleAdminKey, role)
+	if err != nil {
+		return err
+	}
+	return c.sdk.PutState(key, []byte(adminRole))
+}
+
+const (
+	ownerKey        = "owner"
+	pendingOwnerKey = "pendingOwner"
+)
+
+type OwnableDAL struct {
+	sdk common.StateOperator
+}
+
+func NewOwnableDAL(sdk common.StateOperator) *OwnableDAL {
+	return &OwnableDAL{sdk: sdk}
+}
+
+func (c *OwnableDAL) GetOwner() (common.Account, error) {
+	b, err := c.sdk.GetState(ownerKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return c.sdk.NewZeroAccount(), nil
+	}
+	return c.sdk.NewAccountFromBytes(b)
+}
+func (c *OwnableDAL) SetOwner(owner common.Account) error {
+	return c.sdk.PutState(ownerKey, owner.Bytes())
+}
+func (c *OwnableDAL) GetPendingOwner() (common.Account, error) {
+	b, err := c.sdk.GetState(pendingOwnerKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return c.sdk.NewZeroAccount(), nil
+	}
+	return c.sdk.NewAccountFromBytes(b)
+}
+func (c *OwnableDAL) SetPendingOwner(owner common.Account) error {
+	return c.sdk.PutState(pendingOwnerKey, owner.Bytes())
+}
+func (c *OwnableDAL) DeletePendingOwner() error {
+	return c.sdk.DelState(pendingOwnerKey)
+}
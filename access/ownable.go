@@ -0,0 +1,217 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package access
+
+import (
+	"fmt"
+
+	"github.com/studyzy/openzeppelin-go/common"
+)
+
+/**
+ * @dev Contract module which provides a basic access control mechanism, where
+ * there is an account (an owner) that can be granted exclusive access to
+ * specific functions.
+ *
+ * The owner is set with {SetupOwner} when the contract is initialized, and can
+ * later be changed with a two-step process: the current owner calls
+ * {TransferOwnership} to nominate a pending owner, and the transfer only takes
+ * effect once the pending owner calls {AcceptOwnership}. This prevents the
+ * ownership from being handed to a mistyped or unreachable account.
+ *
+ * 通过NewOwnableWithRoles创建时，owner持有的角色随所有权一起变化：新owner接受转移时
+ * 接管原owner持有的所有角色，放弃所有权时原owner持有的所有角色都被撤销。
+ */
+type Ownable struct {
+	dal   *OwnableDAL
+	sdk   common.ContractSDK
+	roles *AccessControl
+}
+
+func NewOwnable(sdk common.ContractSDK) *Ownable {
+	return &Ownable{
+		sdk: sdk,
+		dal: NewOwnableDAL(sdk),
+	}
+}
+
+// NewOwnableWithRoles 创建一个所有权变化时同时转移roles中owner所持有角色的Ownable
+func NewOwnableWithRoles(sdk common.ContractSDK, roles *AccessControl) *Ownable {
+	ownable := NewOwnable(sdk)
+	ownable.roles = roles
+	return ownable
+}
+
+func (c *Ownable) SetSDK(sdk common.ContractSDK) {
+	c.sdk = sdk
+	c.dal = NewOwnableDAL(sdk)
+	if c.roles != nil {
+		c.roles.SetSDK(sdk)
+	}
+}
+
+/**
+ * @dev Returns the address of the current owner.
+ */
+func (c *Ownable) Owner() (common.Account, error) {
+	return c.dal.GetOwner()
+}
+
+/**
+ * @dev Returns the address of the pending owner.
+ */
+func (c *Ownable) PendingOwner() (common.Account, error) {
+	return c.dal.GetPendingOwner()
+}
+
+/**
+ * @dev Throws if the sender is not the owner.
+ */
+func (c *Ownable) OnlyOwner() error {
+	sender, err := c.sdk.GetTxSender()
+	if err != nil {
+		return fmt.Errorf("Get sender address failed, err:%s", err)
+	}
+	owner, err := c.dal.GetOwner()
+	if err != nil {
+		return err
+	}
+	return common.Require(!owner.IsZero() && owner.Equal(sender), "Ownable: caller is not the owner")
+}
+
+/**
+ * @dev Starts the ownership transfer of the contract to a new account. Replaces the pending transfer if there is one.
+ * Can only be called by the current owner.
+ *
+ * Emits an {OwnershipTransferStarted} event.
+ */
+func (c *Ownable) TransferOwnership(newOwner common.Account) error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		if err := c.OnlyOwner(); err != nil {
+			return err
+		}
+		err := common.Require(!newOwner.IsZero(), "Ownable: new owner is the zero address")
+		if err != nil {
+			return err
+		}
+		if err = c.dal.SetPendingOwner(newOwner); err != nil {
+			return err
+		}
+		owner, err := c.dal.GetOwner()
+		if err != nil {
+			return err
+		}
+		return c.sdk.EmitEvent("ownershipTransferStarted", owner.ToString(), newOwner.ToString())
+	})
+}
+
+/**
+ * @dev The new owner accepts the ownership transfer, and takes over every
+ * role held by the previous owner when created with {NewOwnableWithRoles}.
+ *
+ * Emits an {OwnershipTransferred} event.
+ */
+func (c *Ownable) AcceptOwnership() error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		sender, err := c.sdk.GetTxSender()
+		if err != nil {
+			return fmt.Errorf("Get sender address failed, err:%s", err)
+		}
+		pendingOwner, err := c.dal.GetPendingOwner()
+		if err != nil {
+			return err
+		}
+		err = common.Require(!pendingOwner.IsZero() && pendingOwner.Equal(sender), "Ownable2Step: caller is not the new owner")
+		if err != nil {
+			return err
+		}
+		previousOwner, err := c.dal.GetOwner()
+		if err != nil {
+			return err
+		}
+		if err = c.SetupOwner(sender); err != nil {
+			return err
+		}
+		return c.transferRoles(previousOwner, sender)
+	})
+}
+
+/**
+ * @dev Leaves the contract without owner. It will not be possible to call
+ * `onlyOwner` functions anymore. Can only be called by the current owner.
+ *
+ * NOTE: Renouncing ownership will leave the contract without an owner,
+ * thereby removing any functionality that is only available to the owner.
+ * When created with {NewOwnableWithRoles}, every role held by the owner is
+ * revoked as well.
+ */
+func (c *Ownable) RenounceOwnership() error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		if err := c.OnlyOwner(); err != nil {
+			return err
+		}
+		owner, err := c.dal.GetOwner()
+		if err != nil {
+			return err
+		}
+		zero := c.sdk.NewZeroAccount()
+		if err = c.SetupOwner(zero); err != nil {
+			return err
+		}
+		return c.transferRoles(owner, zero)
+	})
+}
+
+/**
+ * @dev Transfers ownership of the contract to a new account (`newOwner`) and deletes any pending owner.
+ * Internal function without access restriction, 一般在合约初始化时调用来设置初始的owner。
+ *
+ * Emits an {OwnershipTransferred} event.
+ */
+func (c *Ownable) SetupOwner(newOwner common.Account) error {
+	if err := c.dal.DeletePendingOwner(); err != nil {
+		return err
+	}
+	oldOwner, err := c.dal.GetOwner()
+	if err != nil {
+		return err
+	}
+	if err = c.dal.SetOwner(newOwner); err != nil {
+		return err
+	}
+	return c.sdk.EmitEvent("ownershipTransferred", oldOwner.ToString(), newOwner.ToString())
+}
+
+// transferRoles 把from持有的所有角色转给to，to为零地址时只撤销from的角色
+func (c *Ownable) transferRoles(from common.Account, to common.Account) error {
+	if c.roles == nil || from.IsZero() || from.Equal(to) {
+		return nil
+	}
+	roles, err := c.roles.RolesOf(from)
+	if err != nil {
+		return err
+	}
+	for _, role := range roles {
+		if !to.IsZero() {
+			if err = c.roles.SetupRole(role, to); err != nil {
+				return err
+			}
+		}
+		if err = c.roles.baseRevokeRole(role, from); err != nil {
+			return err
+		}
+	}
+	return nil
+}
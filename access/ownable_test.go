@@ -0,0 +1,113 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package access
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/studyzy/openzeppelin-go/mock"
+)
+
+var (
+	alice = mock.NewAccount("alice")
+	bob   = mock.NewAccount("bob")
+)
+
+func newOwnable(t *testing.T) (*mock.Chain, *AccessControl, *Ownable) {
+	t.Helper()
+	chain := mock.NewChain()
+	sdk := chain.Deploy("token", nil)
+	roles := NewAccessControl(sdk)
+	ownable := NewOwnableWithRoles(sdk, roles)
+	err := chain.Invoke(alice, func() error {
+		if err := ownable.SetupOwner(alice); err != nil {
+			return err
+		}
+		for _, role := range []string{AdminRole, MinterRole, PauserRole} {
+			if err := roles.SetupRole(role, alice); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return chain, roles, ownable
+}
+
+func rolesOf(t *testing.T, roles *AccessControl, account mock.Account) []string {
+	t.Helper()
+	result, err := roles.RolesOf(account)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func TestAcceptOwnershipMovesRoles(t *testing.T) {
+	chain, roles, ownable := newOwnable(t)
+	if err := chain.Invoke(bob, func() error { return ownable.TransferOwnership(bob) }); err == nil {
+		t.Fatal("non-owner should not transfer ownership")
+	}
+	if err := chain.Invoke(alice, func() error { return ownable.TransferOwnership(bob) }); err != nil {
+		t.Fatal(err)
+	}
+	if owner, _ := ownable.Owner(); !owner.Equal(alice) {
+		t.Fatal("ownership should not move before it is accepted")
+	}
+	if err := chain.Invoke(alice, ownable.AcceptOwnership); err == nil {
+		t.Fatal("only the pending owner can accept")
+	}
+	if err := chain.Invoke(bob, ownable.AcceptOwnership); err != nil {
+		t.Fatal(err)
+	}
+	if owner, _ := ownable.Owner(); !owner.Equal(bob) {
+		t.Fatalf("owner = %s, want bob", owner.ToString())
+	}
+	if pending, _ := ownable.PendingOwner(); !pending.IsZero() {
+		t.Fatal("pending owner not cleared")
+	}
+	if got := rolesOf(t, roles, bob); len(got) != 3 {
+		t.Fatalf("bob roles = %v, want all three", got)
+	}
+	if got := rolesOf(t, roles, alice); len(got) != 0 {
+		t.Fatalf("alice kept roles %v", got)
+	}
+	if len(chain.EventsByTopic("ownershipTransferred")) != 2 {
+		t.Fatal("missing ownershipTransferred event")
+	}
+}
+
+func TestRenounceOwnershipRevokesRoles(t *testing.T) {
+	chain, roles, ownable := newOwnable(t)
+	if err := chain.Invoke(bob, ownable.RenounceOwnership); err == nil {
+		t.Fatal("non-owner should not renounce")
+	}
+	if err := chain.Invoke(alice, ownable.RenounceOwnership); err != nil {
+		t.Fatal(err)
+	}
+	if owner, _ := ownable.Owner(); !owner.IsZero() {
+		t.Fatalf("owner = %s, want zero", owner.ToString())
+	}
+	if got := rolesOf(t, roles, alice); len(got) != 0 {
+		t.Fatalf("alice kept roles %v", got)
+	}
+	if err := chain.Invoke(alice, func() error { return roles.OnlyRole(AdminRole) }); err == nil {
+		t.Fatal("renounced owner still has the admin role")
+	}
+}
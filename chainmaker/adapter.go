@@ -16,6 +16,8 @@ package chainmaker
 
 import (
 	"bytes"
+	"errors"
+	"strconv"
 	"strings"
 
 	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
@@ -24,7 +26,8 @@ import (
 )
 
 type SdkAdapter struct {
-	cmsdk sdk.SDKInterface
+	cmsdk    sdk.SDKInterface
+	verifier common.SignatureVerifier
 }
 
 func NewSdkAdapter(cmsdk sdk.SDKInterface) *SdkAdapter {
@@ -50,12 +53,89 @@ func (s SdkAdapter) DelState(key string) error {
 	return s.cmsdk.DelState(key, "")
 }
 
+// 组合键的各个部分用下划线连接，与之前的存储格式兼容。部分中的"."和"_"会被转义为".."和"._"，
+// 这样部分中包含下划线时也能被正确拆分。普通键中不能包含下划线，否则会被当作组合键
+const (
+	compositeKeySeparator = '_'
+	compositeKeyEscape    = '.'
+)
+
+func escapeCompositeKeyPart(part string) string {
+	var b strings.Builder
+	for i := 0; i < len(part); i++ {
+		if part[i] == compositeKeySeparator || part[i] == compositeKeyEscape {
+			b.WriteByte(compositeKeyEscape)
+		}
+		b.WriteByte(part[i])
+	}
+	return b.String()
+}
+
+func isCompositeKey(key string) bool {
+	return strings.IndexByte(key, compositeKeySeparator) >= 0
+}
+
 func (s SdkAdapter) CreateCompositeKey(prefix string, data ...string) (string, error) {
-	return prefix + "_" + strings.Join(data, "_"), nil
+	parts := make([]string, len(data))
+	for i, part := range data {
+		parts[i] = escapeCompositeKeyPart(part)
+	}
+	return escapeCompositeKeyPart(prefix) + string(compositeKeySeparator) + strings.Join(parts, string(compositeKeySeparator)), nil
 }
 
+func (s SdkAdapter) SplitCompositeKey(compositeKey string) (string, []string, error) {
+	var parts []string
+	var b strings.Builder
+	for i := 0; i < len(compositeKey); i++ {
+		switch compositeKey[i] {
+		case compositeKeyEscape:
+			i++
+			if i == len(compositeKey) {
+				return "", nil, errors.New("invalid composite key")
+			}
+			b.WriteByte(compositeKey[i])
+		case compositeKeySeparator:
+			parts = append(parts, b.String())
+			b.Reset()
+		default:
+			b.WriteByte(compositeKey[i])
+		}
+	}
+	parts = append(parts, b.String())
+	if len(parts) < 2 {
+		return "", nil, errors.New("invalid composite key")
+	}
+	return parts[0], parts[1:], nil
+}
+
+// NewIteratorWithPrefix 与Fabric的范围查询一致，普通键的遍历不会返回组合键
+func (s SdkAdapter) NewIteratorWithPrefix(prefix string) (common.StateIterator, error) {
+	rs, err := s.cmsdk.NewIteratorPrefixWithKey(prefix)
+	if err != nil {
+		return nil, err
+	}
+	return &resultSetIterator{rs: rs, skipCompositeKey: true}, nil
+}
+
+func (s SdkAdapter) NewIteratorWithCompositeKey(prefix string, data ...string) (common.StateIterator, error) {
+	//部分匹配时需要以分隔符结尾，避免prefix_a匹配到prefix_ab_c
+	partial, err := s.CreateCompositeKey(prefix, data...)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > 0 {
+		partial += string(compositeKeySeparator)
+	}
+	rs, err := s.cmsdk.NewIteratorPrefixWithKey(partial)
+	if err != nil {
+		return nil, err
+	}
+	return &resultSetIterator{rs: rs}, nil
+}
+
+// IsContract 长安链的合约SDK无法查询一个地址是否为合约，这里始终返回false，
+// 需要回调接收方的逻辑应该直接发起跨合约调用，根据调用结果判断对方是否实现了回调
 func (s SdkAdapter) IsContract(account common.Account) bool {
-	//TODO
 	return false
 }
 
@@ -91,6 +171,56 @@ func (s SdkAdapter) GetTxSender() (common.Account, error) {
 	return s.NewAccountFromString(sender)
 }
 
+// IsTxSenderCreator 比较交易发送者和合约创建者的公钥
+func (s SdkAdapter) IsTxSenderCreator() (bool, error) {
+	creator, err := s.cmsdk.GetCreatorPk()
+	if err != nil {
+		return false, err
+	}
+	sender, err := s.cmsdk.GetSenderPk()
+	if err != nil {
+		return false, err
+	}
+	return creator == sender, nil
+}
+
+func (s SdkAdapter) GetTxId() (string, error) {
+	return s.cmsdk.GetTxId()
+}
+
+func (s SdkAdapter) GetTxTimestamp() (int64, error) {
+	ts, err := s.cmsdk.GetTxTimeStamp()
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(ts, 10, 64)
+}
+
+func (s SdkAdapter) GetBlockHeight() (uint64, error) {
+	height, err := s.cmsdk.GetBlockHeight()
+	if err != nil {
+		return 0, err
+	}
+	return uint64(height), nil
+}
+
+// ClockMode 长安链的合约可以获得区块高度，时间点使用区块高度
+func (s SdkAdapter) ClockMode() string {
+	return common.ClockModeBlockNumber
+}
+
+// SetSignatureVerifier 设置VerifySignature使用的验签器，一般为NewPublicKeyVerifier
+func (s *SdkAdapter) SetSignatureVerifier(verifier common.SignatureVerifier) {
+	s.verifier = verifier
+}
+
+func (s SdkAdapter) VerifySignature(account common.Account, digest []byte, signature []byte) error {
+	if s.verifier == nil {
+		return errors.New("chainmaker: signature verifier not set")
+	}
+	return s.verifier.VerifySignature(account, digest, signature)
+}
+
 func (s SdkAdapter) EmitEvent(topic string, data ...string) error {
 	s.cmsdk.EmitEvent(topic, data)
 	return nil
@@ -98,6 +228,51 @@ func (s SdkAdapter) EmitEvent(topic string, data ...string) error {
 
 var _ common.ContractSDK = (*SdkAdapter)(nil)
 
+var _ common.StateIterator = (*resultSetIterator)(nil)
+
+// resultSetIterator 把长安链的ResultSetKV包装为common.StateIterator
+type resultSetIterator struct {
+	rs sdk.ResultSetKV
+	//skipCompositeKey 为true时跳过组合键
+	skipCompositeKey bool
+	next             *common.KeyValue
+	err              error
+}
+
+func (it *resultSetIterator) HasNext() bool {
+	for it.next == nil && it.err == nil && it.rs.HasNext() {
+		key, _, value, err := it.rs.Next()
+		if err != nil {
+			it.err = err
+			break
+		}
+		if it.skipCompositeKey && isCompositeKey(key) {
+			continue
+		}
+		it.next = &common.KeyValue{Key: key, Value: value}
+	}
+	return it.next != nil || it.err != nil
+}
+
+func (it *resultSetIterator) Next() (string, []byte, error) {
+	if !it.HasNext() {
+		return "", nil, errors.New("iterator has no more elements")
+	}
+	if it.err != nil {
+		err := it.err
+		it.err = nil
+		return "", nil, err
+	}
+	kv := it.next
+	it.next = nil
+	return kv.Key, kv.Value, nil
+}
+
+func (it *resultSetIterator) Close() error {
+	_, err := it.rs.Close()
+	return err
+}
+
 var _ common.Account = (*Address)(nil)
 
 type Address struct {
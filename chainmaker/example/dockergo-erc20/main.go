@@ -15,6 +15,9 @@
 package main
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
@@ -27,6 +30,13 @@ import (
 	"github.com/studyzy/openzeppelin-go/erc20"
 )
 
+// Permit签名域中的链ID和合约名，部署到其他链或者使用其他合约名部署时需要修改，
+// 否则为这个合约生成的签名可以被重放到其他部署上
+const (
+	permitChainId      = "chain1"
+	permitContractName = "erc20"
+)
+
 type ERC20DockerGo struct {
 	supper  *erc20.ERC20Contract
 	methods map[string]func() protogo.Response
@@ -39,8 +49,13 @@ func NewERC20DockerGo() *ERC20DockerGo {
 		AfterTransfer:  nil,
 		Burnable:       true,
 		Minable:        true,
+		Domain: common.TypedDataDomain{
+			ChainId:           permitChainId,
+			VerifyingContract: permitContractName,
+		},
 	}
 	adapter := chainmaker.NewSdkAdapter(sdk.Instance)
+	adapter.SetSignatureVerifier(chainmaker.NewPublicKeyVerifier(publicKeyToAddress))
 	contract := &ERC20DockerGo{methods: make(map[string]func() protogo.Response), adapter: adapter}
 	contract.supper = erc20.NewERC20Contract(erc20ption, "TestToken", "TT", adapter)
 	contract.registerMethods(erc20ption)
@@ -52,11 +67,65 @@ func (erc20 *ERC20DockerGo) registerMethods(option erc20.Option) {
 	erc20.RegisterMethod("symbol", erc20.symbol)
 	erc20.RegisterMethod("decimals", erc20.decimals)
 	erc20.RegisterMethod("totalSupply", erc20.totalSupply)
+	erc20.RegisterMethod("cap", erc20.cap)
 	erc20.RegisterMethod("balanceOf", erc20.balanceOf)
 	erc20.RegisterMethod("transfer", erc20.transfer)
+	erc20.RegisterMethod("batchTransfer", erc20.batchTransfer)
+	erc20.RegisterMethod("transferAndCall", erc20.transferAndCall)
+	erc20.RegisterMethod("transferFromAndCall", erc20.transferFromAndCall)
+	erc20.RegisterMethod("approveAndCall", erc20.approveAndCall)
 	erc20.RegisterMethod("allowance", erc20.allowance)
 	erc20.RegisterMethod("approve", erc20.approve)
+	erc20.RegisterMethod("increaseAllowance", erc20.increaseAllowance)
+	erc20.RegisterMethod("decreaseAllowance", erc20.decreaseAllowance)
 	erc20.RegisterMethod("transferFrom", erc20.transferFrom)
+	erc20.RegisterMethod("permit", erc20.permit)
+	erc20.RegisterMethod("nonces", erc20.nonces)
+	erc20.RegisterMethod("domainSeparator", erc20.domainSeparator)
+	erc20.RegisterMethod("hasRole", erc20.hasRole)
+	erc20.RegisterMethod("getRoleAdmin", erc20.getRoleAdmin)
+	erc20.RegisterMethod("grantRole", erc20.grantRole)
+	erc20.RegisterMethod("revokeRole", erc20.revokeRole)
+	erc20.RegisterMethod("renounceRole", erc20.renounceRole)
+	erc20.RegisterMethod("owner", erc20.owner)
+	erc20.RegisterMethod("pendingOwner", erc20.pendingOwner)
+	erc20.RegisterMethod("transferOwnership", erc20.transferOwnership)
+	erc20.RegisterMethod("acceptOwnership", erc20.acceptOwnership)
+	erc20.RegisterMethod("renounceOwnership", erc20.renounceOwnership)
+	erc20.RegisterMethod("paused", erc20.paused)
+	erc20.RegisterMethod("pause", erc20.pause)
+	erc20.RegisterMethod("unpause", erc20.unpause)
+	erc20.RegisterMethod("snapshot", erc20.snapshot)
+	erc20.RegisterMethod("balanceOfAt", erc20.balanceOfAt)
+	erc20.RegisterMethod("totalSupplyAt", erc20.totalSupplyAt)
+	erc20.RegisterMethod("holderCount", erc20.holderCount)
+	erc20.RegisterMethod("holdersPage", erc20.holdersPage)
+	erc20.RegisterMethod("allowancesOf", erc20.allowancesOf)
+	erc20.RegisterMethod("indexHolders", erc20.indexHolders)
+	erc20.RegisterMethod("initFlashMint", erc20.initFlashMint)
+	erc20.RegisterMethod("maxFlashLoan", erc20.maxFlashLoan)
+	erc20.RegisterMethod("flashFee", erc20.flashFee)
+	erc20.RegisterMethod("flashLoan", erc20.flashLoan)
+	erc20.RegisterMethod("initWrapper", erc20.initWrapper)
+	erc20.RegisterMethod("underlying", erc20.underlying)
+	erc20.RegisterMethod("depositFor", erc20.depositFor)
+	erc20.RegisterMethod("withdrawTo", erc20.withdrawTo)
+	erc20.RegisterMethod("recover", erc20.recover)
+	erc20.RegisterMethod("isFrozen", erc20.isFrozen)
+	erc20.RegisterMethod("freeze", erc20.freeze)
+	erc20.RegisterMethod("unfreeze", erc20.unfreeze)
+	erc20.RegisterMethod("seize", erc20.seize)
+	erc20.RegisterMethod("feeConfig", erc20.feeConfig)
+	erc20.RegisterMethod("setFeeConfig", erc20.setFeeConfig)
+	erc20.RegisterMethod("isFeeExempt", erc20.isFeeExempt)
+	erc20.RegisterMethod("setFeeExempt", erc20.setFeeExempt)
+	erc20.RegisterMethod("delegate", erc20.delegate)
+	erc20.RegisterMethod("delegates", erc20.delegates)
+	erc20.RegisterMethod("getVotes", erc20.getVotes)
+	erc20.RegisterMethod("getPastVotes", erc20.getPastVotes)
+	erc20.RegisterMethod("getPastTotalSupply", erc20.getPastTotalSupply)
+	erc20.RegisterMethod("clock", erc20.clock)
+	erc20.RegisterMethod("clockMode", erc20.clockMode)
 	if option.Minable {
 		erc20.RegisterMethod("mint", erc20.mint)
 	}
@@ -82,6 +151,10 @@ func (c *ERC20DockerGo) UpgradeContract() protogo.Response {
 	if err != nil {
 		return sdk.Error(err.Error())
 	}
+	//旧版本只保存了一个管理员，升级后需要把它迁移为管理员和铸币角色
+	if err = c.supper.MigrateLegacyAdmin(); err != nil {
+		return sdk.Error(err.Error())
+	}
 	return sdk.Success([]byte("Upgrade contract success"))
 }
 
@@ -110,6 +183,16 @@ func (c *ERC20DockerGo) updateErc20Info() error {
 		}
 		totalSupplyValue = t
 	}
+	//cap is optional, empty or zero means no cap
+	capStr := string(args["cap"])
+	capValue := common.NewSafeUint256(0)
+	if len(capStr) > 0 {
+		t, ok := common.ParseSafeUint256(capStr)
+		if !ok {
+			return fmt.Errorf("param cap err")
+		}
+		capValue = t
+	}
 	admin, err := sdk.Instance.Sender()
 	if err != nil {
 		return fmt.Errorf("get sender failed, err:%s", err)
@@ -120,7 +203,7 @@ func (c *ERC20DockerGo) updateErc20Info() error {
 	}
 	//此处支持在安装合约的时候指定name,symbol
 	//如果没有参数指定，那么就使用NewERC20Contract构造的时候的值
-	err = c.supper.InitERC20(name, symbol, decimal, totalSupplyValue, adminAccount)
+	err = c.supper.InitERC20(name, symbol, decimal, totalSupplyValue, capValue, adminAccount)
 	if err != nil {
 		return fmt.Errorf("set admin failed, err:%s", err)
 	}
@@ -146,6 +229,14 @@ func (erc20 *ERC20DockerGo) requireAccount(key string) (common.Account, error) {
 	}
 	return erc20.adapter.NewAccountFromString(string(acc))
 }
+func (erc20 *ERC20DockerGo) requireString(key string) (string, error) {
+	args := sdk.Instance.GetArgs()
+	str, ok := args[key]
+	if !ok || len(str) == 0 {
+		return "", errors.New("require string:" + key)
+	}
+	return string(str), nil
+}
 func (erc20 *ERC20DockerGo) requireAmount(key string) (*common.SafeUint256, error) {
 	args := sdk.Instance.GetArgs()
 	amt, ok := args[key]
@@ -158,6 +249,14 @@ func (erc20 *ERC20DockerGo) requireAmount(key string) (*common.SafeUint256, erro
 	}
 	return num, nil
 }
+func (erc20 *ERC20DockerGo) requireUint64(key string) (uint64, error) {
+	args := sdk.Instance.GetArgs()
+	num, ok := args[key]
+	if !ok {
+		return 0, errors.New("require uint64:" + key)
+	}
+	return strconv.ParseUint(string(num), 10, 64)
+}
 
 func (erc20 *ERC20DockerGo) name() protogo.Response {
 	return chainmaker.ReturnString(erc20.supper.Name())
@@ -175,6 +274,10 @@ func (erc20 *ERC20DockerGo) totalSupply() protogo.Response {
 	return chainmaker.ReturnUint256(erc20.supper.TotalSupply())
 }
 
+func (erc20 *ERC20DockerGo) cap() protogo.Response {
+	return chainmaker.ReturnUint256(erc20.supper.Cap())
+}
+
 func (erc20 *ERC20DockerGo) balanceOf() protogo.Response {
 	account, err := erc20.requireAccount("account")
 	if err != nil {
@@ -195,6 +298,78 @@ func (erc20 *ERC20DockerGo) transfer() protogo.Response {
 	return chainmaker.ReturnBool(erc20.supper.Transfer(to, amt))
 }
 
+// batchTransfer 参数recipients和amounts都是json数组，例如["addr1","addr2"]和["100","200"]
+func (erc20 *ERC20DockerGo) batchTransfer() protogo.Response {
+	args := sdk.Instance.GetArgs()
+	var recipientStrs, amountStrs []string
+	if err := json.Unmarshal(args["recipients"], &recipientStrs); err != nil {
+		return sdk.Error("invalid recipients:" + err.Error())
+	}
+	if err := json.Unmarshal(args["amounts"], &amountStrs); err != nil {
+		return sdk.Error("invalid amounts:" + err.Error())
+	}
+	recipients := make([]common.Account, len(recipientStrs))
+	for i, str := range recipientStrs {
+		acc, err := erc20.adapter.NewAccountFromString(str)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		recipients[i] = acc
+	}
+	amounts := make([]*common.SafeUint256, len(amountStrs))
+	for i, str := range amountStrs {
+		amt, ok := common.ParseSafeUint256(str)
+		if !ok {
+			return sdk.Error("invalid uint256")
+		}
+		amounts[i] = amt
+	}
+	return chainmaker.ReturnBool(erc20.supper.BatchTransfer(recipients, amounts))
+}
+
+func (erc20 *ERC20DockerGo) transferAndCall() protogo.Response {
+	to, err := erc20.requireAccount("to")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	amt, err := erc20.requireAmount("amount")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	data := sdk.Instance.GetArgs()["data"]
+	return chainmaker.ReturnBool(erc20.supper.TransferAndCall(to, amt, data))
+}
+
+func (erc20 *ERC20DockerGo) transferFromAndCall() protogo.Response {
+	from, err := erc20.requireAccount("from")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	to, err := erc20.requireAccount("to")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	amt, err := erc20.requireAmount("amount")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	data := sdk.Instance.GetArgs()["data"]
+	return chainmaker.ReturnBool(erc20.supper.TransferFromAndCall(from, to, amt, data))
+}
+
+func (erc20 *ERC20DockerGo) approveAndCall() protogo.Response {
+	spender, err := erc20.requireAccount("spender")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	amt, err := erc20.requireAmount("amount")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	data := sdk.Instance.GetArgs()["data"]
+	return chainmaker.ReturnBool(erc20.supper.ApproveAndCall(spender, amt, data))
+}
+
 func (erc20 *ERC20DockerGo) allowance() protogo.Response {
 	owner, err := erc20.requireAccount("owner")
 	if err != nil {
@@ -220,6 +395,30 @@ func (erc20 *ERC20DockerGo) approve() protogo.Response {
 	return chainmaker.ReturnBool(erc20.supper.Approve(spender, amt))
 }
 
+func (erc20 *ERC20DockerGo) increaseAllowance() protogo.Response {
+	spender, err := erc20.requireAccount("spender")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	amt, err := erc20.requireAmount("amount")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return chainmaker.ReturnBool(erc20.supper.IncreaseAllowance(spender, amt))
+}
+
+func (erc20 *ERC20DockerGo) decreaseAllowance() protogo.Response {
+	spender, err := erc20.requireAccount("spender")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	amt, err := erc20.requireAmount("amount")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return chainmaker.ReturnBool(erc20.supper.DecreaseAllowance(spender, amt))
+}
+
 func (erc20 *ERC20DockerGo) transferFrom() protogo.Response {
 	from, err := erc20.requireAccount("from")
 	if err != nil {
@@ -268,6 +467,454 @@ func (erc20 *ERC20DockerGo) burnFrom() protogo.Response {
 	return chainmaker.ReturnBool(erc20.supper.BurnFrom(account, amt))
 }
 
+func (erc20 *ERC20DockerGo) hasRole() protogo.Response {
+	role, err := erc20.requireString("role")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	account, err := erc20.requireAccount("account")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return chainmaker.ReturnBool(erc20.supper.HasRole(role, account))
+}
+
+func (erc20 *ERC20DockerGo) getRoleAdmin() protogo.Response {
+	role, err := erc20.requireString("role")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return chainmaker.ReturnString(erc20.supper.GetRoleAdmin(role))
+}
+
+func (erc20 *ERC20DockerGo) grantRole() protogo.Response {
+	role, err := erc20.requireString("role")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	account, err := erc20.requireAccount("account")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return chainmaker.Return(erc20.supper.GrantRole(role, account))
+}
+
+func (erc20 *ERC20DockerGo) revokeRole() protogo.Response {
+	role, err := erc20.requireString("role")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	account, err := erc20.requireAccount("account")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return chainmaker.Return(erc20.supper.RevokeRole(role, account))
+}
+
+func (erc20 *ERC20DockerGo) renounceRole() protogo.Response {
+	role, err := erc20.requireString("role")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	account, err := erc20.requireAccount("account")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return chainmaker.Return(erc20.supper.RenounceRole(role, account))
+}
+
+func (erc20 *ERC20DockerGo) owner() protogo.Response {
+	return chainmaker.ReturnAccount(erc20.supper.Owner())
+}
+
+func (erc20 *ERC20DockerGo) pendingOwner() protogo.Response {
+	return chainmaker.ReturnAccount(erc20.supper.PendingOwner())
+}
+
+func (erc20 *ERC20DockerGo) transferOwnership() protogo.Response {
+	newOwner, err := erc20.requireAccount("newOwner")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return chainmaker.Return(erc20.supper.TransferOwnership(newOwner))
+}
+
+func (erc20 *ERC20DockerGo) acceptOwnership() protogo.Response {
+	return chainmaker.Return(erc20.supper.AcceptOwnership())
+}
+
+func (erc20 *ERC20DockerGo) renounceOwnership() protogo.Response {
+	return chainmaker.Return(erc20.supper.RenounceOwnership())
+}
+
+func (erc20 *ERC20DockerGo) paused() protogo.Response {
+	return chainmaker.ReturnBool(erc20.supper.Paused())
+}
+
+func (erc20 *ERC20DockerGo) pause() protogo.Response {
+	return chainmaker.Return(erc20.supper.Pause())
+}
+
+func (erc20 *ERC20DockerGo) unpause() protogo.Response {
+	return chainmaker.Return(erc20.supper.Unpause())
+}
+
+func (erc20 *ERC20DockerGo) snapshot() protogo.Response {
+	return chainmaker.ReturnUint64(erc20.supper.Snapshot())
+}
+
+func (erc20 *ERC20DockerGo) balanceOfAt() protogo.Response {
+	account, err := erc20.requireAccount("account")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	snapshotId, err := erc20.requireUint64("snapshotId")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return chainmaker.ReturnUint256(erc20.supper.BalanceOfAt(account, snapshotId))
+}
+
+func (erc20 *ERC20DockerGo) totalSupplyAt() protogo.Response {
+	snapshotId, err := erc20.requireUint64("snapshotId")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return chainmaker.ReturnUint256(erc20.supper.TotalSupplyAt(snapshotId))
+}
+
+func (erc20 *ERC20DockerGo) holderCount() protogo.Response {
+	return chainmaker.ReturnUint64(erc20.supper.HolderCount())
+}
+
+func (erc20 *ERC20DockerGo) holdersPage() protogo.Response {
+	offset, err := erc20.requireUint64("offset")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	limit, err := erc20.requireUint64("limit")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	holders, err := erc20.supper.HoldersPage(offset, limit)
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	result := make([]string, len(holders))
+	for i, holder := range holders {
+		result[i] = holder.ToString()
+	}
+	return chainmaker.ReturnJson(result, nil)
+}
+
+func (erc20 *ERC20DockerGo) allowancesOf() protogo.Response {
+	owner, err := erc20.requireAccount("owner")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	offset, err := erc20.requireUint64("offset")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	limit, err := erc20.requireUint64("limit")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	allowances, err := erc20.supper.AllowancesOf(owner, offset, limit)
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	//spender -> amount
+	result := make(map[string]string, len(allowances))
+	for _, allowance := range allowances {
+		result[allowance.Spender.ToString()] = allowance.Amount.ToString()
+	}
+	return chainmaker.ReturnJson(result, nil)
+}
+
+func (erc20 *ERC20DockerGo) indexHolders() protogo.Response {
+	args := sdk.Instance.GetArgs()
+	var accountStrs []string
+	if err := json.Unmarshal(args["accounts"], &accountStrs); err != nil {
+		return sdk.Error("invalid accounts:" + err.Error())
+	}
+	accounts := make([]common.Account, len(accountStrs))
+	for i, str := range accountStrs {
+		acc, err := erc20.adapter.NewAccountFromString(str)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		accounts[i] = acc
+	}
+	return chainmaker.Return(erc20.supper.IndexHolders(accounts))
+}
+
+// initFlashMint 开启闪电贷，self为本合约的地址
+func (erc20 *ERC20DockerGo) initFlashMint() protogo.Response {
+	self, err := erc20.requireAccount("self")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return chainmaker.Return(erc20.supper.InitERC20FlashMint(self))
+}
+
+func (erc20 *ERC20DockerGo) maxFlashLoan() protogo.Response {
+	return chainmaker.ReturnUint256(erc20.supper.MaxFlashLoan())
+}
+
+func (erc20 *ERC20DockerGo) flashFee() protogo.Response {
+	amt, err := erc20.requireAmount("amount")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return chainmaker.ReturnUint256(erc20.supper.FlashFee(amt))
+}
+
+func (erc20 *ERC20DockerGo) flashLoan() protogo.Response {
+	receiver, err := erc20.requireAccount("receiver")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	amt, err := erc20.requireAmount("amount")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	data := sdk.Instance.GetArgs()["data"]
+	return chainmaker.ReturnBool(erc20.supper.FlashLoan(receiver, amt, data))
+}
+
+// initWrapper 把本合约设置为underlying的包装代币，self为本合约的地址
+func (erc20 *ERC20DockerGo) initWrapper() protogo.Response {
+	underlying, err := erc20.requireAccount("underlying")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	self, err := erc20.requireAccount("self")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return chainmaker.Return(erc20.supper.InitERC20Wrapper(underlying, self))
+}
+
+func (erc20 *ERC20DockerGo) underlying() protogo.Response {
+	return chainmaker.ReturnAccount(erc20.supper.Underlying())
+}
+
+func (erc20 *ERC20DockerGo) depositFor() protogo.Response {
+	account, err := erc20.requireAccount("account")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	amt, err := erc20.requireAmount("amount")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return chainmaker.ReturnBool(erc20.supper.DepositFor(account, amt))
+}
+
+func (erc20 *ERC20DockerGo) withdrawTo() protogo.Response {
+	account, err := erc20.requireAccount("account")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	amt, err := erc20.requireAmount("amount")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return chainmaker.ReturnBool(erc20.supper.WithdrawTo(account, amt))
+}
+
+func (erc20 *ERC20DockerGo) recover() protogo.Response {
+	account, err := erc20.requireAccount("account")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return chainmaker.ReturnUint256(erc20.supper.Recover(account))
+}
+
+func (erc20 *ERC20DockerGo) isFrozen() protogo.Response {
+	account, err := erc20.requireAccount("account")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return chainmaker.ReturnBool(erc20.supper.IsFrozen(account))
+}
+
+func (erc20 *ERC20DockerGo) freeze() protogo.Response {
+	account, err := erc20.requireAccount("account")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return chainmaker.Return(erc20.supper.Freeze(account))
+}
+
+func (erc20 *ERC20DockerGo) unfreeze() protogo.Response {
+	account, err := erc20.requireAccount("account")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return chainmaker.Return(erc20.supper.Unfreeze(account))
+}
+
+func (erc20 *ERC20DockerGo) seize() protogo.Response {
+	from, err := erc20.requireAccount("from")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	to, err := erc20.requireAccount("to")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	amt, err := erc20.requireAmount("amount")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return chainmaker.ReturnBool(erc20.supper.Seize(from, to, amt))
+}
+
+func (erc20 *ERC20DockerGo) feeConfig() protogo.Response {
+	return chainmaker.ReturnJson(erc20.supper.GetFeeConfig())
+}
+
+func (erc20 *ERC20DockerGo) setFeeConfig() protogo.Response {
+	bps, err := erc20.requireUint64("bps")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	recipient, err := erc20.requireAccount("recipient")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	maxFee, err := erc20.requireAmount("maxFee")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return chainmaker.Return(erc20.supper.SetFeeConfig(bps, recipient, maxFee))
+}
+
+func (erc20 *ERC20DockerGo) isFeeExempt() protogo.Response {
+	account, err := erc20.requireAccount("account")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return chainmaker.ReturnBool(erc20.supper.IsFeeExempt(account))
+}
+
+func (erc20 *ERC20DockerGo) setFeeExempt() protogo.Response {
+	account, err := erc20.requireAccount("account")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	exempt, err := strconv.ParseBool(string(sdk.Instance.GetArgs()["exempt"]))
+	if err != nil {
+		return sdk.Error("require bool:exempt")
+	}
+	return chainmaker.Return(erc20.supper.SetFeeExempt(account, exempt))
+}
+
+func (erc20 *ERC20DockerGo) delegate() protogo.Response {
+	delegatee, err := erc20.requireAccount("delegatee")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return chainmaker.Return(erc20.supper.Delegate(delegatee))
+}
+
+func (erc20 *ERC20DockerGo) delegates() protogo.Response {
+	account, err := erc20.requireAccount("account")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return chainmaker.ReturnAccount(erc20.supper.Delegates(account))
+}
+
+func (erc20 *ERC20DockerGo) getVotes() protogo.Response {
+	account, err := erc20.requireAccount("account")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return chainmaker.ReturnUint256(erc20.supper.GetVotes(account))
+}
+
+func (erc20 *ERC20DockerGo) getPastVotes() protogo.Response {
+	account, err := erc20.requireAccount("account")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	blockNumber, err := erc20.requireUint64("blockNumber")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return chainmaker.ReturnUint256(erc20.supper.GetPastVotes(account, blockNumber))
+}
+
+func (erc20 *ERC20DockerGo) getPastTotalSupply() protogo.Response {
+	blockNumber, err := erc20.requireUint64("blockNumber")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return chainmaker.ReturnUint256(erc20.supper.GetPastTotalSupply(blockNumber))
+}
+
+func (erc20 *ERC20DockerGo) clock() protogo.Response {
+	return chainmaker.ReturnUint64(erc20.supper.Clock())
+}
+
+func (erc20 *ERC20DockerGo) clockMode() protogo.Response {
+	return chainmaker.ReturnString(erc20.supper.ClockMode())
+}
+
+func (erc20 *ERC20DockerGo) permit() protogo.Response {
+	owner, err := erc20.requireAccount("owner")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	spender, err := erc20.requireAccount("spender")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	value, err := erc20.requireAmount("value")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	deadlineStr, err := erc20.requireString("deadline")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	deadline, err := strconv.ParseInt(deadlineStr, 10, 64)
+	if err != nil {
+		return sdk.Error("invalid deadline")
+	}
+	signature, err := erc20.requireString("signature")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return chainmaker.Return(erc20.supper.Permit(owner, spender, value, deadline, []byte(signature)))
+}
+
+func (erc20 *ERC20DockerGo) nonces() protogo.Response {
+	owner, err := erc20.requireAccount("owner")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return chainmaker.ReturnUint64(erc20.supper.Nonces(owner))
+}
+
+func (erc20 *ERC20DockerGo) domainSeparator() protogo.Response {
+	separator, err := erc20.supper.DomainSeparator()
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.Success([]byte(hex.EncodeToString(separator)))
+}
+
+// publicKeyToAddress 按长安链默认的ChainMaker地址类型计算地址，即公钥DER编码的SHA256摘要的后20字节，
+// 链使用其他地址类型时需要替换
+func publicKeyToAddress(publicKeyDER []byte) (string, error) {
+	hash := sha256.Sum256(publicKeyDER)
+	return hex.EncodeToString(hash[len(hash)-20:]), nil
+}
+
 func main() {
 	erc20 := NewERC20DockerGo()
 	err := sandbox.Start(erc20)
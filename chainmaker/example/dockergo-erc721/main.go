@@ -58,6 +58,19 @@ func (erc721 *ERC721DockerGo) registerMethods(option erc721.Option) {
 	erc721.RegisterMethod("setApprovalForAll", erc721.setApprovalForAll)
 	erc721.RegisterMethod("getApproved", erc721.getApproved)
 	erc721.RegisterMethod("isApprovedForAll", erc721.isApprovedForAll)
+	erc721.RegisterMethod("hasRole", erc721.hasRole)
+	erc721.RegisterMethod("getRoleAdmin", erc721.getRoleAdmin)
+	erc721.RegisterMethod("grantRole", erc721.grantRole)
+	erc721.RegisterMethod("revokeRole", erc721.revokeRole)
+	erc721.RegisterMethod("renounceRole", erc721.renounceRole)
+	erc721.RegisterMethod("owner", erc721.owner)
+	erc721.RegisterMethod("pendingOwner", erc721.pendingOwner)
+	erc721.RegisterMethod("transferOwnership", erc721.transferOwnership)
+	erc721.RegisterMethod("acceptOwnership", erc721.acceptOwnership)
+	erc721.RegisterMethod("renounceOwnership", erc721.renounceOwnership)
+	erc721.RegisterMethod("paused", erc721.paused)
+	erc721.RegisterMethod("pause", erc721.pause)
+	erc721.RegisterMethod("unpause", erc721.unpause)
 	if option.Minable {
 		erc721.RegisterMethod("mint", erc721.mint)
 	}
@@ -82,6 +95,10 @@ func (erc721 *ERC721DockerGo) UpgradeContract() protogo.Response {
 	if err != nil {
 		return sdk.Error(err.Error())
 	}
+	//旧版本只保存了一个管理员，升级后需要把它迁移为管理员和铸币角色
+	if err = erc721.supper.MigrateLegacyAdmin(); err != nil {
+		return sdk.Error(err.Error())
+	}
 	return sdk.Success([]byte("Upgrade contract success"))
 }
 
@@ -135,6 +152,14 @@ func (erc721 *ERC721DockerGo) requireAccount(key string) (common.Account, error)
 	}
 	return erc721.adapter.NewAccountFromString(string(acc))
 }
+func (erc721 *ERC721DockerGo) requireString(key string) (string, error) {
+	args := sdk.Instance.GetArgs()
+	str, ok := args[key]
+	if !ok || len(str) == 0 {
+		return "", errors.New("require string:" + key)
+	}
+	return string(str), nil
+}
 func (erc721 *ERC721DockerGo) requireTokenId(key string) (*common.SafeUint256, error) {
 	args := sdk.Instance.GetArgs()
 	tokenId, ok := args[key]
@@ -290,6 +315,98 @@ func (erc721 *ERC721DockerGo) tokenURI() protogo.Response {
 	return chainmaker.ReturnString(erc721.supper.TokenURI(tokenId))
 }
 
+func (erc721 *ERC721DockerGo) hasRole() protogo.Response {
+	role, err := erc721.requireString("role")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	account, err := erc721.requireAccount("account")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return chainmaker.ReturnBool(erc721.supper.HasRole(role, account))
+}
+
+func (erc721 *ERC721DockerGo) getRoleAdmin() protogo.Response {
+	role, err := erc721.requireString("role")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return chainmaker.ReturnString(erc721.supper.GetRoleAdmin(role))
+}
+
+func (erc721 *ERC721DockerGo) grantRole() protogo.Response {
+	role, err := erc721.requireString("role")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	account, err := erc721.requireAccount("account")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return chainmaker.Return(erc721.supper.GrantRole(role, account))
+}
+
+func (erc721 *ERC721DockerGo) revokeRole() protogo.Response {
+	role, err := erc721.requireString("role")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	account, err := erc721.requireAccount("account")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return chainmaker.Return(erc721.supper.RevokeRole(role, account))
+}
+
+func (erc721 *ERC721DockerGo) renounceRole() protogo.Response {
+	role, err := erc721.requireString("role")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	account, err := erc721.requireAccount("account")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return chainmaker.Return(erc721.supper.RenounceRole(role, account))
+}
+
+func (erc721 *ERC721DockerGo) owner() protogo.Response {
+	return chainmaker.ReturnAccount(erc721.supper.Owner())
+}
+
+func (erc721 *ERC721DockerGo) pendingOwner() protogo.Response {
+	return chainmaker.ReturnAccount(erc721.supper.PendingOwner())
+}
+
+func (erc721 *ERC721DockerGo) transferOwnership() protogo.Response {
+	newOwner, err := erc721.requireAccount("newOwner")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return chainmaker.Return(erc721.supper.TransferOwnership(newOwner))
+}
+
+func (erc721 *ERC721DockerGo) acceptOwnership() protogo.Response {
+	return chainmaker.Return(erc721.supper.AcceptOwnership())
+}
+
+func (erc721 *ERC721DockerGo) renounceOwnership() protogo.Response {
+	return chainmaker.Return(erc721.supper.RenounceOwnership())
+}
+
+func (erc721 *ERC721DockerGo) paused() protogo.Response {
+	return chainmaker.ReturnBool(erc721.supper.Paused())
+}
+
+func (erc721 *ERC721DockerGo) pause() protogo.Response {
+	return chainmaker.Return(erc721.supper.Pause())
+}
+
+func (erc721 *ERC721DockerGo) unpause() protogo.Response {
+	return chainmaker.Return(erc721.supper.Unpause())
+}
+
 func main() {
 	erc20 := NewERC721DockerGo()
 	err := sandbox.Start(erc20)
@@ -6,6 +6,7 @@ require (
 	chainmaker.org/chainmaker/contract-sdk-go/v2 v2.3.3-0.20230112114602-93f6adab4548
 	chainmaker.org/chainmaker/contract-utils v1.0.0
 	github.com/studyzy/openzeppelin-go v0.0.0-20230104174003-39bfdffbcb48
+	github.com/tjfoc/gmsm v1.4.1
 )
 
 require (
@@ -22,7 +23,6 @@ require (
 	github.com/tidwall/match v1.1.1 // indirect
 	github.com/tidwall/pretty v1.2.0 // indirect
 	github.com/tidwall/tinylru v1.1.0 // indirect
-	github.com/tjfoc/gmsm v1.4.1 // indirect
 	go.uber.org/atomic v1.7.0 // indirect
 	go.uber.org/multierr v1.6.0 // indirect
 	go.uber.org/zap v1.18.1 // indirect
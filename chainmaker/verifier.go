@@ -0,0 +1,96 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chainmaker
+
+import (
+	"crypto/ecdsa"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"chainmaker.org/chainmaker/contract-utils/address"
+	"github.com/studyzy/openzeppelin-go/common"
+	"github.com/tjfoc/gmsm/sm2"
+	gmx509 "github.com/tjfoc/gmsm/x509"
+)
+
+var _ common.SignatureVerifier = (*PublicKeyVerifier)(nil)
+
+// AddressFunc 根据DER编码的公钥计算长安链账户地址，需要与链的地址类型配置保持一致
+type AddressFunc func(publicKeyDER []byte) (string, error)
+
+// PublicKeyVerifier 验证长安链账户的签名，支持ECDSA和SM2。签名为json编码的common.Signature，
+// 其中PublicKey可以是PEM编码的公钥或者证书，根据公钥计算出的地址必须与account相同
+type PublicKeyVerifier struct {
+	addressOf AddressFunc
+}
+
+// NewPublicKeyVerifier 创建PublicKeyVerifier，addressOf用于把公钥转换为链上地址
+func NewPublicKeyVerifier(addressOf AddressFunc) *PublicKeyVerifier {
+	return &PublicKeyVerifier{addressOf: addressOf}
+}
+
+func (v *PublicKeyVerifier) VerifySignature(account common.Account, digest []byte, signature []byte) error {
+	sig, err := common.ParseSignature(signature)
+	if err != nil {
+		return err
+	}
+	block, _ := pem.Decode(sig.PublicKey)
+	if block == nil {
+		return errors.New("chainmaker: invalid public key pem")
+	}
+	var der []byte
+	var pub interface{}
+	switch block.Type {
+	case "CERTIFICATE":
+		cert, err := gmx509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return err
+		}
+		der, pub = cert.RawSubjectPublicKeyInfo, cert.PublicKey
+	case "PUBLIC KEY":
+		if pub, err = gmx509.ParsePKIXPublicKey(block.Bytes); err != nil {
+			return err
+		}
+		der = block.Bytes
+	default:
+		return fmt.Errorf("chainmaker: unsupported pem type %s", block.Type)
+	}
+	//公钥必须属于account，否则任何人都可以用自己的密钥冒充account签名
+	addr, err := v.addressOf(der)
+	if err != nil {
+		return err
+	}
+	signer, err := address.ParseAddress(addr)
+	if err != nil {
+		return err
+	}
+	if !(&Address{addr: *signer}).Equal(account) {
+		return errors.New("chainmaker: public key does not belong to account")
+	}
+	var valid bool
+	switch key := pub.(type) {
+	case *ecdsa.PublicKey:
+		valid = ecdsa.VerifyASN1(key, digest, sig.Signature)
+	case *sm2.PublicKey:
+		valid = key.Verify(digest, sig.Signature)
+	default:
+		return errors.New("chainmaker: only ecdsa and sm2 public key are supported")
+	}
+	if !valid {
+		return errors.New("chainmaker: invalid signature")
+	}
+	return nil
+}
@@ -70,6 +70,17 @@ func ReturnUint8(num uint8, err error) protogo.Response {
 	return sdk.Success([]byte(strconv.Itoa(int(num))))
 }
 
+// ReturnUint64 封装返回uint64类型为Response，如果有error则忽略num，封装error
+// @param num
+// @param err
+// @return Response
+func ReturnUint64(num uint64, err error) protogo.Response {
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.Success([]byte(strconv.FormatUint(num, 10)))
+}
+
 // ReturnJson 封装返回对象类型为json格式到Response，如果有error则忽略对象，封装error
 // @param obj
 // @param err
@@ -0,0 +1,41 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import "fmt"
+
+// ERC-6372定义的时钟模式，由ContractSDK.ClockMode返回
+const (
+	// ClockModeBlockNumber 时间点为区块高度
+	ClockModeBlockNumber = "mode=blocknumber&from=default"
+	// ClockModeTimestamp 时间点为交易时间戳，单位为秒
+	ClockModeTimestamp = "mode=timestamp"
+)
+
+// Clock 按sdk的时钟模式返回当前的时间点，获取失败时直接返回错误，不会换用另一种时钟
+func Clock(sdk ContractSDK) (uint64, error) {
+	switch mode := sdk.ClockMode(); mode {
+	case ClockModeBlockNumber:
+		return sdk.GetBlockHeight()
+	case ClockModeTimestamp:
+		timestamp, err := sdk.GetTxTimestamp()
+		if err != nil {
+			return 0, err
+		}
+		return uint64(timestamp), nil
+	default:
+		return 0, fmt.Errorf("unsupported clock mode:%s", mode)
+	}
+}
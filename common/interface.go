@@ -27,13 +27,38 @@ type StateOperator interface {
 	PutState(key string, value []byte) error
 	DelState(key string) error
 	CreateCompositeKey(prefix string, data ...string) (string, error)
+	// SplitCompositeKey 把CreateCompositeKey生成的组合键拆分为前缀和各个部分
+	SplitCompositeKey(compositeKey string) (string, []string, error)
+	// NewIteratorWithPrefix 按key的字典序遍历所有以prefix开头的普通键
+	NewIteratorWithPrefix(prefix string) (StateIterator, error)
+	// NewIteratorWithCompositeKey 遍历前缀为prefix、并且前几个部分与data相同的所有组合键
+	NewIteratorWithCompositeKey(prefix string, data ...string) (StateIterator, error)
+}
+
+// StateIterator 状态数据迭代器，使用完毕后必须调用Close释放资源
+type StateIterator interface {
+	HasNext() bool
+	Next() (key string, value []byte, err error)
+	Close() error
 }
 type ContractSDK interface {
 	StateOperator
 	GetTxSender() (Account, error)
+	// IsTxSenderCreator 当前交易的发送者是否为部署合约的账户
+	IsTxSenderCreator() (bool, error)
+	// GetTxId 当前交易的ID
+	GetTxId() (string, error)
+	// GetTxTimestamp 当前交易的时间戳，单位为秒
+	GetTxTimestamp() (int64, error)
+	// GetBlockHeight 当前交易所在区块的高度
+	GetBlockHeight() (uint64, error)
+	// ClockMode 合约记录时间点使用的时钟，ClockModeBlockNumber或者ClockModeTimestamp，同一条链上始终不变
+	ClockMode() string
 	EmitEvent(topic string, data ...string) error
 	IsContract(account Account) bool
 	CallContract(account Account, method string, args []KeyValue) Response
+	// VerifySignature 验证account对digest的签名，签名无效时返回error，具体的验签方式由各条链的适配器提供
+	VerifySignature(account Account, digest []byte, signature []byte) error
 }
 
 func Require(exp bool, msg string) error {
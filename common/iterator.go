@@ -0,0 +1,46 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import "errors"
+
+// kvIterator 基于内存中已排好序的KeyValue列表实现的StateIterator
+type kvIterator struct {
+	kvs   []KeyValue
+	index int
+}
+
+// NewKVIterator 把已排好序的KeyValue列表包装成StateIterator
+func NewKVIterator(kvs []KeyValue) StateIterator {
+	return &kvIterator{kvs: kvs}
+}
+
+func (it *kvIterator) HasNext() bool {
+	return it.index < len(it.kvs)
+}
+
+func (it *kvIterator) Next() (string, []byte, error) {
+	if !it.HasNext() {
+		return "", nil, errors.New("iterator has no more elements")
+	}
+	kv := it.kvs[it.index]
+	it.index++
+	return kv.Key, kv.Value, nil
+}
+
+func (it *kvIterator) Close() error {
+	it.kvs = nil
+	return nil
+}
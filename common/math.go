@@ -0,0 +1,45 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import "math/big"
+
+// Rounding 除法的取整方向
+type Rounding int
+
+const (
+	// RoundDown 向下取整
+	RoundDown Rounding = iota
+	// RoundUp 向上取整
+	RoundUp
+)
+
+// MulDiv 计算x*y/denominator，中间结果不会溢出，按rounding取整，x、y和denominator都不会被修改。
+// denominator为0或者结果超过MaxUint256时返回false
+func MulDiv(x, y, denominator *SafeUint256, rounding Rounding) (*SafeUint256, bool) {
+	d := (*big.Int)(denominator)
+	if d.Sign() == 0 {
+		return nil, false
+	}
+	product := new(big.Int).Mul((*big.Int)(x), (*big.Int)(y))
+	z, m := new(big.Int).QuoRem(product, d, new(big.Int))
+	if rounding == RoundUp && m.Sign() > 0 {
+		z.Add(z, big.NewInt(1))
+	}
+	if z.Cmp((*big.Int)(MaxSafeUint256)) > 0 {
+		return nil, false
+	}
+	return (*SafeUint256)(z), true
+}
@@ -0,0 +1,83 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+)
+
+// SignatureVerifier 验签器，链的适配器通过它实现ContractSDK.VerifySignature，
+// 不同的链可以根据自己的账户体系（证书、公钥地址等）提供不同的实现
+type SignatureVerifier interface {
+	VerifySignature(account Account, digest []byte, signature []byte) error
+}
+
+// Signature 链下签名的通用格式，PublicKey为PEM编码的公钥或者证书，Signature为DER编码的签名
+type Signature struct {
+	PublicKey []byte `json:"publicKey"`
+	Signature []byte `json:"signature"`
+}
+
+// ParseSignature 解析json编码的Signature
+func ParseSignature(data []byte) (*Signature, error) {
+	sig := &Signature{}
+	if err := json.Unmarshal(data, sig); err != nil {
+		return nil, err
+	}
+	if len(sig.PublicKey) == 0 || len(sig.Signature) == 0 {
+		return nil, errors.New("invalid signature format")
+	}
+	return sig, nil
+}
+
+// TypedDataDomain 仿照EIP-712的签名域，用于区分不同的链和合约，防止签名被重放到别的合约上
+type TypedDataDomain struct {
+	Name              string
+	Version           string
+	ChainId           string
+	VerifyingContract string
+}
+
+const typedDataDomainType = "EIP712Domain(string name,string version,string chainId,string verifyingContract)"
+
+// Separator 返回签名域的哈希
+func (d TypedDataDomain) Separator() []byte {
+	return HashStruct(typedDataDomainType, d.Name, d.Version, d.ChainId, d.VerifyingContract)
+}
+
+// HashStruct 计算结构化数据的哈希：sha256(sha256(typ) || sha256(field1) || sha256(field2) ...)，
+// typ是结构的类型描述，例如"Permit(address owner,address spender,uint256 value,uint256 nonce,uint256 deadline)"，
+// 每个字段都先哈希为32字节，这样字段之间不会因为拼接产生歧义
+func HashStruct(typ string, fields ...string) []byte {
+	h := sha256.New()
+	typeHash := sha256.Sum256([]byte(typ))
+	h.Write(typeHash[:])
+	for _, field := range fields {
+		fieldHash := sha256.Sum256([]byte(field))
+		h.Write(fieldHash[:])
+	}
+	return h.Sum(nil)
+}
+
+// HashTypedData 计算最终需要签名的摘要：sha256("\x19\x01" || domainSeparator || structHash)
+func HashTypedData(domain TypedDataDomain, structHash []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{0x19, 0x01})
+	h.Write(domain.Separator())
+	h.Write(structHash)
+	return h.Sum(nil)
+}
@@ -0,0 +1,254 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var _ ContractSDK = (*TxContext)(nil)
+
+type stagedValue struct {
+	value   []byte
+	deleted bool
+}
+
+type stagedEvent struct {
+	topic string
+	data  []string
+}
+
+// TxContext 包装一个ContractSDK，暂存一次合约方法调用中的所有状态写入和事件，
+// 只有调用Commit时才写入底层SDK，方法执行失败时调用Rollback丢弃，
+// 这样在不会因为合约返回错误而回滚整个交易的链上也不会留下写了一半的状态。
+//
+// CallContract之前会先把已暂存的写入刷到底层SDK，保证被调合约回调本合约时能读到一致的状态，
+// 同时记录这些key被覆盖前的值，Rollback时按相反的顺序恢复，所以跨合约调用之前的写入也能被回滚。
+// 事件一直暂存到Commit，因此调用前发出的事件会排在被调合约的事件之后。
+//
+// 注意：被调合约自己写入的状态，包括它回调本合约时产生的写入，不经过TxContext，
+// 这部分仍然依赖链在合约返回错误时丢弃整个交易的写集。
+type TxContext struct {
+	ContractSDK
+	writes map[string]stagedValue
+	keys   []string
+	events []stagedEvent
+	//已经刷到底层SDK的key被覆盖前的值，以及它们第一次被刷入的顺序
+	undo     map[string]stagedValue
+	undoKeys []string
+	//通过CreateCompositeKey生成的组合键，遍历时用来区分暂存写入中的普通键和组合键
+	compositeKeys map[string]struct{}
+}
+
+// NewTxContext 基于sdk创建一个新的写缓冲
+func NewTxContext(sdk ContractSDK) *TxContext {
+	return &TxContext{
+		ContractSDK:   sdk,
+		writes:        make(map[string]stagedValue),
+		undo:          make(map[string]stagedValue),
+		compositeKeys: make(map[string]struct{}),
+	}
+}
+
+// Atomic 在sdk之上的写缓冲中执行fn：先通过setSDK把合约切换到写缓冲上，fn成功才把所有写入和事件提交到sdk，
+// fn返回错误时回滚所有写入，包括跨合约调用前已经刷到sdk的写入。返回前会通过setSDK把合约切换回sdk
+func Atomic(sdk ContractSDK, setSDK func(ContractSDK), fn func() error) error {
+	tx := NewTxContext(sdk)
+	setSDK(tx)
+	defer setSDK(sdk)
+	if err := fn(); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return fmt.Errorf("%s, rollback failed, err:%s", err, rollbackErr)
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+func (t *TxContext) GetState(key string) (value []byte, err error) {
+	if v, ok := t.writes[key]; ok {
+		if v.deleted {
+			return nil, nil
+		}
+		return v.value, nil
+	}
+	return t.ContractSDK.GetState(key)
+}
+
+func (t *TxContext) PutState(key string, value []byte) error {
+	t.stage(key, stagedValue{value: value})
+	return nil
+}
+
+func (t *TxContext) DelState(key string) error {
+	t.stage(key, stagedValue{deleted: true})
+	return nil
+}
+
+func (t *TxContext) EmitEvent(topic string, data ...string) error {
+	t.events = append(t.events, stagedEvent{topic: topic, data: data})
+	return nil
+}
+
+func (t *TxContext) CallContract(account Account, method string, args []KeyValue) Response {
+	if err := t.flush(); err != nil {
+		return Response{Status: ERROR, Message: err.Error()}
+	}
+	return t.ContractSDK.CallContract(account, method, args)
+}
+
+// CreateCompositeKey 记录生成的组合键，普通键的遍历不会返回暂存写入中的组合键
+func (t *TxContext) CreateCompositeKey(prefix string, data ...string) (string, error) {
+	key, err := t.ContractSDK.CreateCompositeKey(prefix, data...)
+	if err != nil {
+		return "", err
+	}
+	t.compositeKeys[key] = struct{}{}
+	return key, nil
+}
+
+// NewIteratorWithPrefix 遍历时会合并底层SDK中的状态和尚未提交的写入
+func (t *TxContext) NewIteratorWithPrefix(prefix string) (StateIterator, error) {
+	it, err := t.ContractSDK.NewIteratorWithPrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+	return t.merge(it, func(key string) bool {
+		_, composite := t.compositeKeys[key]
+		return !composite && strings.HasPrefix(key, prefix)
+	})
+}
+
+// NewIteratorWithCompositeKey 遍历时会合并底层SDK中的状态和尚未提交的写入
+func (t *TxContext) NewIteratorWithCompositeKey(prefix string, data ...string) (StateIterator, error) {
+	it, err := t.ContractSDK.NewIteratorWithCompositeKey(prefix, data...)
+	if err != nil {
+		return nil, err
+	}
+	return t.merge(it, func(key string) bool {
+		if _, composite := t.compositeKeys[key]; !composite {
+			return false
+		}
+		p, parts, err := t.SplitCompositeKey(key)
+		if err != nil || p != prefix || len(parts) < len(data) {
+			return false
+		}
+		for i := range data {
+			if parts[i] != data[i] {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// merge 读出底层迭代器的全部结果，再用暂存的写入覆盖，按key排序后返回
+func (t *TxContext) merge(it StateIterator, match func(key string) bool) (StateIterator, error) {
+	defer it.Close()
+	values := make(map[string][]byte)
+	for it.HasNext() {
+		key, value, err := it.Next()
+		if err != nil {
+			return nil, err
+		}
+		values[key] = value
+	}
+	for key, v := range t.writes {
+		if !match(key) {
+			continue
+		}
+		if v.deleted {
+			delete(values, key)
+		} else {
+			values[key] = v.value
+		}
+	}
+	kvs := make([]KeyValue, 0, len(values))
+	for key, value := range values {
+		kvs = append(kvs, KeyValue{Key: key, Value: value})
+	}
+	sort.Slice(kvs, func(i, j int) bool {
+		return kvs[i].Key < kvs[j].Key
+	})
+	return NewKVIterator(kvs), nil
+}
+
+// Commit 按写入顺序把暂存的状态和事件写入底层SDK，然后清空缓冲，提交之后的写入不能再回滚
+func (t *TxContext) Commit() error {
+	if err := t.flush(); err != nil {
+		return err
+	}
+	for _, e := range t.events {
+		if err := t.ContractSDK.EmitEvent(e.topic, e.data...); err != nil {
+			return err
+		}
+	}
+	t.events = nil
+	t.undo = make(map[string]stagedValue)
+	t.undoKeys = nil
+	return nil
+}
+
+// Rollback 丢弃暂存的写入和事件，并把跨合约调用前已经刷到底层SDK的key恢复成原来的值
+func (t *TxContext) Rollback() error {
+	t.writes = make(map[string]stagedValue)
+	t.keys = nil
+	t.events = nil
+	for i := len(t.undoKeys) - 1; i >= 0; i-- {
+		key := t.undoKeys[i]
+		if err := t.write(key, t.undo[key]); err != nil {
+			return err
+		}
+	}
+	t.undo = make(map[string]stagedValue)
+	t.undoKeys = nil
+	return nil
+}
+
+// flush 按写入顺序把暂存的状态写入底层SDK，第一次写入某个key之前先记录它原来的值
+func (t *TxContext) flush() error {
+	for _, key := range t.keys {
+		if _, ok := t.undo[key]; !ok {
+			old, err := t.ContractSDK.GetState(key)
+			if err != nil {
+				return err
+			}
+			t.undo[key] = stagedValue{value: old, deleted: len(old) == 0}
+			t.undoKeys = append(t.undoKeys, key)
+		}
+		if err := t.write(key, t.writes[key]); err != nil {
+			return err
+		}
+	}
+	t.writes = make(map[string]stagedValue)
+	t.keys = nil
+	return nil
+}
+
+func (t *TxContext) write(key string, v stagedValue) error {
+	if v.deleted {
+		return t.ContractSDK.DelState(key)
+	}
+	return t.ContractSDK.PutState(key, v.value)
+}
+
+func (t *TxContext) stage(key string, v stagedValue) {
+	if _, ok := t.writes[key]; !ok {
+		t.keys = append(t.keys, key)
+	}
+	t.writes[key] = v
+}
@@ -0,0 +1,163 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/studyzy/openzeppelin-go/common"
+	"github.com/studyzy/openzeppelin-go/mock"
+)
+
+func TestAtomicCommit(t *testing.T) {
+	chain := mock.NewChain()
+	sdk := chain.Deploy("token", nil)
+	var current common.ContractSDK = sdk
+	err := common.Atomic(sdk, func(s common.ContractSDK) { current = s }, func() error {
+		if err := current.PutState("k", []byte("v")); err != nil {
+			return err
+		}
+		//提交之前写入只在缓冲中可见
+		if v, _ := sdk.GetState("k"); v != nil {
+			t.Fatalf("write leaked before commit: %s", v)
+		}
+		return current.EmitEvent("set", "k", "v")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current != common.ContractSDK(sdk) {
+		t.Fatal("sdk not restored after Atomic")
+	}
+	if v, _ := sdk.GetState("k"); string(v) != "v" {
+		t.Fatalf("got %q, want v", v)
+	}
+	if len(chain.EventsByTopic("set")) != 1 {
+		t.Fatal("event not emitted")
+	}
+}
+
+func TestAtomicRollbackAfterCallContract(t *testing.T) {
+	chain := mock.NewChain()
+	sdk := chain.Deploy("token", nil)
+	if err := sdk.PutState("existing", []byte("old")); err != nil {
+		t.Fatal(err)
+	}
+	var seen []byte
+	chain.Deploy("receiver", func(s *mock.SDK, method string, args []common.KeyValue) common.Response {
+		//被调合约回调时能读到调用方已经写入的状态
+		seen, _ = chain.NewSDK("token").GetState("existing")
+		return mock.Success(nil)
+	})
+	var current common.ContractSDK = sdk
+	failure := errors.New("repay failed")
+	err := common.Atomic(sdk, func(s common.ContractSDK) { current = s }, func() error {
+		current.PutState("existing", []byte("new"))
+		current.PutState("created", []byte("1"))
+		current.EmitEvent("before")
+		if resp := current.CallContract(mock.NewAccount("receiver"), "onCall", nil); resp.Status != common.OK {
+			return errors.New(resp.Message)
+		}
+		current.PutState("after", []byte("1"))
+		return failure
+	})
+	if err != failure {
+		t.Fatalf("got %v, want %v", err, failure)
+	}
+	if string(seen) != "new" {
+		t.Fatalf("callee saw %q, want new", seen)
+	}
+	if v, _ := sdk.GetState("existing"); string(v) != "old" {
+		t.Fatalf("existing = %q, want old", v)
+	}
+	for _, key := range []string{"created", "after"} {
+		if v, _ := sdk.GetState(key); v != nil {
+			t.Fatalf("%s = %q, want deleted", key, v)
+		}
+	}
+	if len(chain.EventsByTopic("before")) != 0 {
+		t.Fatal("event of failed call emitted")
+	}
+}
+
+func TestNestedAtomicRollback(t *testing.T) {
+	chain := mock.NewChain()
+	sdk := chain.Deploy("token", nil)
+	chain.Deploy("receiver", func(s *mock.SDK, method string, args []common.KeyValue) common.Response {
+		return mock.Success(nil)
+	})
+	var current common.ContractSDK = sdk
+	setSDK := func(s common.ContractSDK) { current = s }
+	err := common.Atomic(sdk, setSDK, func() error {
+		current.PutState("outer", []byte("1"))
+		inner := common.Atomic(current, setSDK, func() error {
+			current.PutState("inner", []byte("1"))
+			current.CallContract(mock.NewAccount("receiver"), "onCall", nil)
+			return errors.New("inner failed")
+		})
+		if inner == nil {
+			t.Fatal("inner error lost")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := sdk.GetState("outer"); string(v) != "1" {
+		t.Fatalf("outer = %q, want 1", v)
+	}
+	if v, _ := sdk.GetState("inner"); v != nil {
+		t.Fatalf("inner = %q, want rolled back", v)
+	}
+}
+
+func TestTxContextIterators(t *testing.T) {
+	chain := mock.NewChain()
+	sdk := chain.Deploy("token", nil)
+	sdk.PutState("b1", []byte("1"))
+	tx := common.NewTxContext(sdk)
+	tx.PutState("b2", []byte("1"))
+	tx.DelState("b1")
+	key, _ := tx.CreateCompositeKey("b", "x")
+	tx.PutState(key, []byte("1"))
+	key, _ = tx.CreateCompositeKey("b", "y")
+	tx.PutState(key, []byte("1"))
+	//普通键的遍历与底层SDK一致，不返回组合键
+	it, err := tx.NewIteratorWithPrefix("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for it.HasNext() {
+		k, _, _ := it.Next()
+		keys = append(keys, k)
+	}
+	if len(keys) != 1 || keys[0] != "b2" {
+		t.Fatalf("prefix scan got %q", keys)
+	}
+	it, err = tx.NewIteratorWithCompositeKey("b", "y")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys = nil
+	for it.HasNext() {
+		k, _, _ := it.Next()
+		keys = append(keys, k)
+	}
+	if len(keys) != 1 || keys[0] != key {
+		t.Fatalf("composite scan got %q", keys)
+	}
+}
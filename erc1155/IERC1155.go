@@ -96,3 +96,8 @@ type IERC1155 interface {
 	 */
 	Uri(id *common.SafeUint256) (string, error)
 }
+
+type Mintable interface {
+	Mint(to common.Account, id, amount *common.SafeUint256, data []byte) error
+	MintBatch(to common.Account, ids, amounts []*common.SafeUint256, data []byte) error
+}
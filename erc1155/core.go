@@ -18,7 +18,9 @@ import (
 	"encoding/json"
 	"errors"
 
+	"github.com/studyzy/openzeppelin-go/access"
 	"github.com/studyzy/openzeppelin-go/common"
+	"github.com/studyzy/openzeppelin-go/security"
 )
 
 // Option 初始化ERC20合约的选项
@@ -29,6 +31,14 @@ type Option struct {
 	AfterTransfer func(operator, from, to common.Account, ids, amounts []*common.SafeUint256, data []byte) error
 }
 
+func (c *ERC1155Contract) SetSDK(sdk common.ContractSDK) {
+	c.sdk = sdk
+	c.dal = NewERC20ContractDAL(sdk)
+	c.AccessControl = access.NewAccessControl(sdk)
+	c.Ownable = access.NewOwnableWithRoles(sdk, c.AccessControl)
+	c.RolePausable = security.NewRolePausable(sdk, c.AccessControl, access.PauserRole, "ERC1155")
+}
+
 func asSingletonArray(element *common.SafeUint256) []*common.SafeUint256 {
 	array := make([]*common.SafeUint256, 1)
 	array[0] = element
@@ -49,6 +59,9 @@ func asSingletonArray(element *common.SafeUint256) []*common.SafeUint256 {
  * acceptance magic value.
  */
 func (c *ERC1155Contract) baseSafeTransferFrom(from, to common.Account, id, amount *common.SafeUint256, data []byte) error {
+	if err := c.RequireNotPaused(); err != nil {
+		return err
+	}
 	err := common.Require(!to.IsZero(), "ERC1155: transfer to the zero address")
 	if err != nil {
 		return err
@@ -111,6 +124,9 @@ func (c *ERC1155Contract) baseSafeTransferFrom(from, to common.Account, id, amou
  * acceptance magic value.
  */
 func (c *ERC1155Contract) baseSafeBatchTransferFrom(from, to common.Account, ids, amounts []*common.SafeUint256, data []byte) error {
+	if err := c.RequireNotPaused(); err != nil {
+		return err
+	}
 	err := common.Require(len(ids) == len(amounts), "ERC1155: ids and amounts length mismatch")
 	if err != nil {
 		return err
@@ -180,6 +196,9 @@ func (c *ERC1155Contract) baseSafeBatchTransferFrom(from, to common.Account, ids
  * acceptance magic value.
  */
 func (c *ERC1155Contract) baseMint(to common.Account, id, amount *common.SafeUint256, data []byte) error {
+	if err := c.RequireNotPaused(); err != nil {
+		return err
+	}
 	if err := common.Require(!to.IsZero(), "ERC1155: mint to the zero address"); err != nil {
 		return err
 	}
@@ -231,6 +250,9 @@ func (c *ERC1155Contract) baseMint(to common.Account, id, amount *common.SafeUin
  * acceptance magic value.
  */
 func (c *ERC1155Contract) baseMintBatch(to common.Account, ids, amounts []*common.SafeUint256, data []byte) error {
+	if err := c.RequireNotPaused(); err != nil {
+		return err
+	}
 	if err := common.Require(!to.IsZero(), "ERC1155: mint to the zero address"); err != nil {
 		return err
 	}
@@ -285,6 +307,9 @@ func (c *ERC1155Contract) baseMintBatch(to common.Account, ids, amounts []*commo
  * - `from` must have at least `amount` tokens of token type `id`.
  */
 func (c *ERC1155Contract) baseBurn(from common.Account, id, amount *common.SafeUint256) error {
+	if err := c.RequireNotPaused(); err != nil {
+		return err
+	}
 	if err := common.Require(!from.IsZero(), "ERC1155: burn from the zero address"); err != nil {
 		return err
 	}
@@ -338,6 +363,9 @@ func (c *ERC1155Contract) baseBurn(from common.Account, id, amount *common.SafeU
  * - `ids` and `amounts` must have the same length.
  */
 func (c *ERC1155Contract) baseBurnBatch(from common.Account, ids, amounts []*common.SafeUint256) error {
+	if err := c.RequireNotPaused(); err != nil {
+		return err
+	}
 	if err := common.Require(!from.IsZero(), "ERC1155: burn from the zero address"); err != nil {
 		return err
 	}
@@ -450,7 +478,7 @@ func (c *ERC1155Contract) baseCheckOnERC721Received(from, to common.Account, tok
 func (c *ERC1155Contract) doSafeTransferAcceptanceCheck(operator, from, to common.Account,
 	id, amount *common.SafeUint256, data []byte) error {
 	if c.sdk.IsContract(to) {
-		args := make([]common.KeyValue, 4)
+		args := make([]common.KeyValue, 6)
 		args[0] = common.KeyValue{
 			Key:   "operator",
 			Value: operator.Bytes(),
@@ -491,7 +519,7 @@ func (c *ERC1155Contract) doSafeTransferAcceptanceCheck(operator, from, to commo
 
 func (c *ERC1155Contract) doSafeBatchTransferAcceptanceCheck(sender common.Account, from common.Account, to common.Account, ids []*common.SafeUint256, amounts []*common.SafeUint256, data []byte) error {
 	if c.sdk.IsContract(to) {
-		args := make([]common.KeyValue, 4)
+		args := make([]common.KeyValue, 6)
 		args[0] = common.KeyValue{
 			Key:   "operator",
 			Value: sender.Bytes(),
@@ -24,7 +24,7 @@ import (
 const (
 	balanceKey          = "b"
 	operatorApprovalKey = "o"
-	uri                 = "uri"
+	uriKey              = "uri"
 	adminKey            = "admin"
 )
 
@@ -80,23 +80,27 @@ func (c *ERC1155Dal) GetOperatorApproval(owner common.Account, operator common.A
 	return bytes.Equal(b, []byte("true")), nil
 }
 
-func (c *ERC1155Dal) GetAdmin() (common.Account, error) {
+// GetLegacyAdmin 获得角色权限上线之前保存的管理员，没有保存时返回nil
+func (c *ERC1155Dal) GetLegacyAdmin() (common.Account, error) {
 	b, err := c.sdk.GetState(adminKey)
-	if err != nil {
+	if err != nil || len(b) == 0 {
 		return nil, err
 	}
 	return c.sdk.NewAccountFromBytes(b)
 }
-func (c *ERC1155Dal) SetAdmin(admin common.Account) error {
-	return c.sdk.PutState(adminKey, admin.Bytes())
+
+// DelLegacyAdmin 迁移完成后删除旧的管理员记录
+func (c *ERC1155Dal) DelLegacyAdmin() error {
+	return c.sdk.DelState(adminKey)
 }
+
 func bytes2String(b []byte, err error) (string, error) {
 	return string(b), err
 
 }
 func (c *ERC1155Dal) GetUri() (string, error) {
-	return bytes2String(c.sdk.GetState(uri))
+	return bytes2String(c.sdk.GetState(uriKey))
 }
 func (c *ERC1155Dal) SetUri(uri string) error {
-	return c.sdk.PutState(uri, []byte(uri))
+	return c.sdk.PutState(uriKey, []byte(uri))
 }
@@ -17,17 +17,83 @@ package erc1155
 import (
 	"fmt"
 
+	"github.com/studyzy/openzeppelin-go/access"
 	"github.com/studyzy/openzeppelin-go/common"
+	"github.com/studyzy/openzeppelin-go/security"
 )
 
 var _ IERC1155 = (*ERC1155Contract)(nil)
+var _ Mintable = (*ERC1155Contract)(nil)
 
 type ERC1155Contract struct {
+	*access.AccessControl
+	*access.Ownable
+	*security.RolePausable
 	option Option
 	dal    *ERC1155Dal
 	sdk    common.ContractSDK
 }
 
+func NewERC1155Contract(option Option, sdk common.ContractSDK) *ERC1155Contract {
+	roles := access.NewAccessControl(sdk)
+	erc1155 := &ERC1155Contract{
+		AccessControl: roles,
+		Ownable:       access.NewOwnableWithRoles(sdk, roles),
+		RolePausable:  security.NewRolePausable(sdk, roles, access.PauserRole, "ERC1155"),
+		option:        option,
+		sdk:           sdk,
+		dal:           NewERC20ContractDAL(sdk),
+	}
+	return erc1155
+}
+
+func (c *ERC1155Contract) InitERC1155(uri string, admin common.Account) error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		//uri中可以包含%s，查询时会被替换为token id
+		if err := c.dal.SetUri(uri); err != nil {
+			return err
+		}
+		//admin同时是合约的owner，之后可以通过两步转移修改owner
+		if err := c.SetupOwner(admin); err != nil {
+			return fmt.Errorf("set owner failed, err:%s", err)
+		}
+		//给admin授予管理员、铸币和暂停角色，方便后面mint的时候判断权限
+		if err := c.SetupRole(access.AdminRole, admin); err != nil {
+			return fmt.Errorf("set admin failed, err:%s", err)
+		}
+		if err := c.SetupRole(access.MinterRole, admin); err != nil {
+			return fmt.Errorf("set minter failed, err:%s", err)
+		}
+		if err := c.SetupRole(access.PauserRole, admin); err != nil {
+			return fmt.Errorf("set pauser failed, err:%s", err)
+		}
+		return nil
+	})
+}
+
+/**
+ * @dev Grants `AdminRole` and `MinterRole` to the admin stored by versions
+ * before role-based access control and removes the legacy record, so the
+ * admin of an upgraded contract can still mint and manage roles.
+ *
+ * Does nothing if no legacy admin is stored, so it is safe to call on every upgrade.
+ */
+func (c *ERC1155Contract) MigrateLegacyAdmin() error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		admin, err := c.dal.GetLegacyAdmin()
+		if err != nil || admin == nil {
+			return err
+		}
+		if err = c.SetupRole(access.AdminRole, admin); err != nil {
+			return fmt.Errorf("set admin failed, err:%s", err)
+		}
+		if err = c.SetupRole(access.MinterRole, admin); err != nil {
+			return fmt.Errorf("set minter failed, err:%s", err)
+		}
+		return c.dal.DelLegacyAdmin()
+	})
+}
+
 func (c *ERC1155Contract) SupportsInterface(interfaceId string) bool {
 	return interfaceId == "ERC1155" || interfaceId == "ERC1155Metadata" || interfaceId == "ERC165"
 }
@@ -58,11 +124,16 @@ func (c *ERC1155Contract) BalanceOfBatch(accounts []common.Account, ids []*commo
 }
 
 func (c *ERC1155Contract) SetApprovalForAll(operator common.Account, approved bool) error {
-	sender, err := c.sdk.GetTxSender()
-	if err != nil {
-		return err
-	}
-	return c.dal.SetOperatorApproval(sender, operator, approved)
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		if err := c.RequireNotPaused(); err != nil {
+			return err
+		}
+		sender, err := c.sdk.GetTxSender()
+		if err != nil {
+			return err
+		}
+		return c.dal.SetOperatorApproval(sender, operator, approved)
+	})
 }
 
 func (c *ERC1155Contract) IsApprovedForAll(account common.Account, operator common.Account) (bool, error) {
@@ -70,35 +141,39 @@ func (c *ERC1155Contract) IsApprovedForAll(account common.Account, operator comm
 }
 
 func (c *ERC1155Contract) SafeTransferFrom(from, to common.Account, id, amount *common.SafeUint256, data []byte) error {
-	sender, err := c.sdk.GetTxSender()
-	if err != nil {
-		return err
-	}
-	isApproved, err := c.IsApprovedForAll(from, sender)
-	if err != nil {
-		return err
-	}
-	err = common.Require(from.Equal(sender) || isApproved, "ERC1155: caller is not token owner or approved")
-	if err != nil {
-		return err
-	}
-	return c.baseSafeTransferFrom(from, to, id, amount, data)
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		sender, err := c.sdk.GetTxSender()
+		if err != nil {
+			return err
+		}
+		isApproved, err := c.IsApprovedForAll(from, sender)
+		if err != nil {
+			return err
+		}
+		err = common.Require(from.Equal(sender) || isApproved, "ERC1155: caller is not token owner or approved")
+		if err != nil {
+			return err
+		}
+		return c.baseSafeTransferFrom(from, to, id, amount, data)
+	})
 }
 
 func (c *ERC1155Contract) SafeBatchTransferFrom(from, to common.Account, ids, amounts []*common.SafeUint256, data []byte) error {
-	sender, err := c.sdk.GetTxSender()
-	if err != nil {
-		return err
-	}
-	isApproved, err := c.IsApprovedForAll(from, sender)
-	if err != nil {
-		return err
-	}
-	err = common.Require(from.Equal(sender) || isApproved, "ERC1155: caller is not token owner or approved")
-	if err != nil {
-		return err
-	}
-	return c.baseSafeBatchTransferFrom(from, to, ids, amounts, data)
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		sender, err := c.sdk.GetTxSender()
+		if err != nil {
+			return err
+		}
+		isApproved, err := c.IsApprovedForAll(from, sender)
+		if err != nil {
+			return err
+		}
+		err = common.Require(from.Equal(sender) || isApproved, "ERC1155: caller is not token owner or approved")
+		if err != nil {
+			return err
+		}
+		return c.baseSafeBatchTransferFrom(from, to, ids, amounts, data)
+	})
 }
 
 func (c *ERC1155Contract) Uri(id *common.SafeUint256) (string, error) {
@@ -108,3 +183,23 @@ func (c *ERC1155Contract) Uri(id *common.SafeUint256) (string, error) {
 	}
 	return fmt.Sprintf(uri, id.ToString()), nil
 }
+
+func (c *ERC1155Contract) Mint(to common.Account, id, amount *common.SafeUint256, data []byte) error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		//check is minter
+		if err := c.OnlyRole(access.MinterRole); err != nil {
+			return err
+		}
+		return c.baseMint(to, id, amount, data)
+	})
+}
+
+func (c *ERC1155Contract) MintBatch(to common.Account, ids, amounts []*common.SafeUint256, data []byte) error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		//check is minter
+		if err := c.OnlyRole(access.MinterRole); err != nil {
+			return err
+		}
+		return c.baseMintBatch(to, ids, amounts, data)
+	})
+}
@@ -0,0 +1,70 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erc1155
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/studyzy/openzeppelin-go/common"
+	"github.com/studyzy/openzeppelin-go/mock"
+)
+
+func TestPauseBlocksTransfersAndMints(t *testing.T) {
+	admin := mock.NewAccount("admin")
+	alice := mock.NewAccount("alice")
+	bob := mock.NewAccount("bob")
+	chain := mock.NewChain()
+	token := NewERC1155Contract(Option{}, chain.Deploy("multi", nil))
+	id := common.NewSafeUint256(1)
+	amount := common.NewSafeUint256(10)
+	invoke := func(sender mock.Account, fn func() error) error {
+		return chain.Invoke(sender, fn)
+	}
+	if err := invoke(admin, func() error { return token.InitERC1155("", admin) }); err != nil {
+		t.Fatal(err)
+	}
+	if err := invoke(admin, func() error { return token.Mint(alice, id, amount, nil) }); err != nil {
+		t.Fatal(err)
+	}
+	if err := invoke(alice, token.Pause); err == nil {
+		t.Fatal("account without pauser role should not pause")
+	}
+	if err := invoke(admin, token.Pause); err != nil {
+		t.Fatal(err)
+	}
+	for name, op := range map[string]struct {
+		sender mock.Account
+		fn     func() error
+	}{
+		"safeTransferFrom":  {alice, func() error { return token.SafeTransferFrom(alice, bob, id, amount, nil) }},
+		"setApprovalForAll": {alice, func() error { return token.SetApprovalForAll(bob, true) }},
+		"mint":              {admin, func() error { return token.Mint(bob, id, amount, nil) }},
+	} {
+		err := invoke(op.sender, op.fn)
+		if err == nil || !strings.Contains(err.Error(), "ERC1155Pausable: token operation while paused") {
+			t.Fatalf("%s while paused: got %v", name, err)
+		}
+	}
+	if err := invoke(admin, token.Unpause); err != nil {
+		t.Fatal(err)
+	}
+	if err := invoke(alice, func() error { return token.SafeTransferFrom(alice, bob, id, amount, nil) }); err != nil {
+		t.Fatal(err)
+	}
+	if balance, _ := token.BalanceOf(bob, id); !balance.Equal(amount) {
+		t.Fatalf("balance of bob = %s, want 10", balance.ToString())
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erc20
+
+import (
+	"testing"
+
+	"github.com/studyzy/openzeppelin-go/common"
+	"github.com/studyzy/openzeppelin-go/mock"
+)
+
+func requireAllowance(t *testing.T, token *ERC20Contract, owner, spender mock.Account, want *common.SafeUint256) {
+	t.Helper()
+	allowance, err := token.Allowance(owner, spender)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !allowance.Equal(want) {
+		t.Fatalf("allowance = %s, want %s", allowance.ToString(), want.ToString())
+	}
+}
+
+func TestIncreaseAndDecreaseAllowance(t *testing.T) {
+	chain, token := newToken(t, Option{})
+	mustInvoke(t, chain, alice, func() error {
+		_, err := token.IncreaseAllowance(bob, amount(100))
+		return err
+	})
+	requireAllowance(t, token, alice, bob, amount(100))
+	mustInvoke(t, chain, alice, func() error {
+		_, err := token.IncreaseAllowance(bob, amount(50))
+		return err
+	})
+	requireAllowance(t, token, alice, bob, amount(150))
+	mustInvoke(t, chain, alice, func() error {
+		_, err := token.DecreaseAllowance(bob, amount(120))
+		return err
+	})
+	requireAllowance(t, token, alice, bob, amount(30))
+	if len(chain.EventsByTopic("approve")) != 3 {
+		t.Fatal("missing approve events")
+	}
+
+	mustFail(t, chain, alice, func() error {
+		_, err := token.DecreaseAllowance(bob, amount(31))
+		return err
+	}, "ERC20: decreased allowance below zero")
+	requireAllowance(t, token, alice, bob, amount(30))
+	mustFail(t, chain, alice, func() error {
+		_, err := token.IncreaseAllowance(mock.ZeroAccount, amount(1))
+		return err
+	}, "the zero address")
+}
+
+func TestIncreaseAllowanceOverflow(t *testing.T) {
+	chain, token := newToken(t, Option{})
+	mustInvoke(t, chain, alice, func() error {
+		_, err := token.Approve(bob, common.MaxSafeUint256)
+		return err
+	})
+	mustFail(t, chain, alice, func() error {
+		_, err := token.IncreaseAllowance(bob, amount(1))
+		return err
+	}, "ERC20: increased allowance overflow")
+	requireAllowance(t, token, alice, bob, common.MaxSafeUint256)
+}
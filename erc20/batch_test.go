@@ -0,0 +1,50 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erc20
+
+import (
+	"testing"
+
+	"github.com/studyzy/openzeppelin-go/common"
+	"github.com/studyzy/openzeppelin-go/mock"
+)
+
+func TestBatchTransfer(t *testing.T) {
+	chain, token := newToken(t, Option{})
+	carol := mock.NewAccount("carol")
+	mint(t, chain, token, alice, 100)
+	mustInvoke(t, chain, alice, func() error {
+		_, err := token.BatchTransfer([]common.Account{bob, carol}, []*common.SafeUint256{amount(30), amount(20)})
+		return err
+	})
+	requireBalance(t, token, alice, 50)
+	requireBalance(t, token, bob, 30)
+	requireBalance(t, token, carol, 20)
+	//余额不足时一笔都不转
+	mustFail(t, chain, alice, func() error {
+		_, err := token.BatchTransfer([]common.Account{bob, carol}, []*common.SafeUint256{amount(30), amount(30)})
+		return err
+	}, "ERC20: transfer amount exceeds balance")
+	mustFail(t, chain, alice, func() error {
+		_, err := token.BatchTransfer([]common.Account{bob}, []*common.SafeUint256{amount(1), amount(1)})
+		return err
+	}, "ERC20: recipients and amounts length mismatch")
+	mustFail(t, chain, alice, func() error {
+		_, err := token.BatchTransfer([]common.Account{bob, carol}, []*common.SafeUint256{amount(1), nil})
+		return err
+	}, "ERC20: batch amount is nil")
+	requireBalance(t, token, alice, 50)
+	requireBalance(t, token, bob, 30)
+}
@@ -0,0 +1,121 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erc20
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/studyzy/openzeppelin-go/common"
+)
+
+// TokenCaller 通过CallContract调用另一个ERC20合约，方法名和参数名与示例合约中注册的一致，
+// 被调合约返回失败或者返回false时都视为调用失败
+type TokenCaller struct {
+	sdk   common.ContractSDK
+	token common.Account
+}
+
+// NewTokenCaller 创建调用token合约的TokenCaller
+func NewTokenCaller(sdk common.ContractSDK, token common.Account) *TokenCaller {
+	return &TokenCaller{sdk: sdk, token: token}
+}
+
+// Token 返回被调用的合约地址
+func (t *TokenCaller) Token() common.Account {
+	return t.token
+}
+
+func (t *TokenCaller) BalanceOf(account common.Account) (*common.SafeUint256, error) {
+	payload, err := t.call("balanceOf", common.KeyValue{Key: "account", Value: account.Bytes()})
+	if err != nil {
+		return nil, err
+	}
+	return parseUint256(payload)
+}
+
+// GetPastVotes 查询支持ERC20Votes的代币在blockNumber时account的投票权
+func (t *TokenCaller) GetPastVotes(account common.Account, blockNumber uint64) (*common.SafeUint256, error) {
+	payload, err := t.call("getPastVotes",
+		common.KeyValue{Key: "account", Value: account.Bytes()},
+		common.KeyValue{Key: "blockNumber", Value: []byte(strconv.FormatUint(blockNumber, 10))})
+	if err != nil {
+		return nil, err
+	}
+	return parseUint256(payload)
+}
+
+// GetPastTotalSupply 查询支持ERC20Votes的代币在blockNumber时的总发行量
+func (t *TokenCaller) GetPastTotalSupply(blockNumber uint64) (*common.SafeUint256, error) {
+	payload, err := t.call("getPastTotalSupply",
+		common.KeyValue{Key: "blockNumber", Value: []byte(strconv.FormatUint(blockNumber, 10))})
+	if err != nil {
+		return nil, err
+	}
+	return parseUint256(payload)
+}
+
+// Clock 查询代币合约ERC-6372的当前时间点，与GetPastVotes使用的时间点一致
+func (t *TokenCaller) Clock() (uint64, error) {
+	payload, err := t.call("clock")
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(string(payload), 10, 64)
+}
+
+// ClockMode 查询代币合约ERC-6372的时钟描述
+func (t *TokenCaller) ClockMode() (string, error) {
+	payload, err := t.call("clockMode")
+	if err != nil {
+		return "", err
+	}
+	return string(payload), nil
+}
+
+func (t *TokenCaller) Transfer(to common.Account, amount *common.SafeUint256) error {
+	_, err := t.call("transfer",
+		common.KeyValue{Key: "to", Value: to.Bytes()},
+		common.KeyValue{Key: "amount", Value: []byte(amount.ToString())})
+	return err
+}
+
+func (t *TokenCaller) TransferFrom(from, to common.Account, amount *common.SafeUint256) error {
+	_, err := t.call("transferFrom",
+		common.KeyValue{Key: "from", Value: from.Bytes()},
+		common.KeyValue{Key: "to", Value: to.Bytes()},
+		common.KeyValue{Key: "amount", Value: []byte(amount.ToString())})
+	return err
+}
+
+func (t *TokenCaller) call(method string, args ...common.KeyValue) ([]byte, error) {
+	response := t.sdk.CallContract(t.token, method, args)
+	if response.Status != common.OK {
+		return nil, fmt.Errorf("call %s.%s failed, err:%s", t.token.ToString(), method, response.Message)
+	}
+	if string(response.Payload) == "false" {
+		return nil, fmt.Errorf("call %s.%s returned false", t.token.ToString(), method)
+	}
+	return response.Payload, nil
+}
+
+func parseUint256(payload []byte) (*common.SafeUint256, error) {
+	num, ok := common.ParseSafeUint256(string(payload))
+	if !ok {
+		return nil, errors.New("invalid uint256 data")
+	}
+	return num, nil
+}
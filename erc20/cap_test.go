@@ -0,0 +1,75 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erc20
+
+import (
+	"testing"
+
+	"github.com/studyzy/openzeppelin-go/common"
+	"github.com/studyzy/openzeppelin-go/mock"
+)
+
+func TestCap(t *testing.T) {
+	chain := mock.NewChain()
+	token := NewERC20Contract(Option{Minable: true}, "Token", "TKN", chain.Deploy("token", nil))
+	//初始发行量不能超过上限
+	mustFail(t, chain, admin, func() error {
+		return token.InitERC20("", "", 18, amount(1001), amount(1000), admin)
+	}, "ERC20Capped: cap exceeded")
+	mustInvoke(t, chain, admin, func() error {
+		return token.InitERC20("", "", 18, amount(400), amount(1000), admin)
+	})
+	capValue, err := token.Cap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !capValue.Equal(amount(1000)) {
+		t.Fatalf("cap = %s, want 1000", capValue.ToString())
+	}
+	mint(t, chain, token, alice, 600)
+	mustFail(t, chain, admin, func() error {
+		_, err := token.Mint(alice, amount(1))
+		return err
+	}, "ERC20Capped: cap exceeded")
+	requireBalance(t, token, alice, 600)
+}
+
+func TestUncapped(t *testing.T) {
+	_, token := newToken(t, Option{Minable: true})
+	capValue, err := token.Cap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !capValue.Equal(common.MaxSafeUint256) {
+		t.Fatalf("cap = %s, want MaxUint256", capValue.ToString())
+	}
+}
+
+func TestCapWithNilTotalSupply(t *testing.T) {
+	chain := mock.NewChain()
+	token := NewERC20Contract(Option{Minable: true}, "Token", "TKN", chain.Deploy("token", nil))
+	//nil的初始发行量等同于0，不能导致上限检查panic
+	mustInvoke(t, chain, admin, func() error {
+		return token.InitERC20("", "", 18, nil, amount(1000), admin)
+	})
+	supply, err := token.TotalSupply()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !supply.Equal(amount(0)) {
+		t.Fatalf("total supply = %s, want 0", supply.ToString())
+	}
+	mint(t, chain, token, alice, 1000)
+}
@@ -0,0 +1,115 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erc20
+
+import (
+	"github.com/studyzy/openzeppelin-go/common"
+)
+
+// checkpoints 一个按时间点（快照ID或区块高度）递增排列的检查点序列，
+// 每个检查点单独存储，查询时用二分查找，避免把整个序列读出来
+type checkpoints struct {
+	dal  *ERC20ContractDAL
+	kind string
+	id   string
+}
+
+func (c *ERC20Contract) checkpoints(kind, id string) checkpoints {
+	return checkpoints{dal: c.dal, kind: kind, id: id}
+}
+
+func (s checkpoints) length() (uint64, error) {
+	return s.dal.GetCheckpointCount(s.kind, s.id)
+}
+
+func (s checkpoints) at(index uint64) (uint64, *common.SafeUint256, error) {
+	return s.dal.GetCheckpoint(s.kind, s.id, index)
+}
+
+// latest 返回最后一个检查点，序列为空时ok为false
+func (s checkpoints) latest() (point uint64, value *common.SafeUint256, ok bool, err error) {
+	n, err := s.length()
+	if err != nil || n == 0 {
+		return 0, common.NewSafeUint256(0), false, err
+	}
+	point, value, err = s.at(n - 1)
+	if err != nil {
+		return 0, nil, false, err
+	}
+	return point, value, true, nil
+}
+
+// push 追加一个检查点，如果最后一个检查点的时间点与point相同则直接覆盖它的值
+func (s checkpoints) push(point uint64, value *common.SafeUint256) error {
+	n, err := s.length()
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		last, _, err := s.at(n - 1)
+		if err != nil {
+			return err
+		}
+		if last == point {
+			return s.dal.SetCheckpoint(s.kind, s.id, n-1, point, value)
+		}
+		if err = common.Require(last < point, "Checkpoint: decreasing keys"); err != nil {
+			return err
+		}
+	}
+	if err = s.dal.SetCheckpoint(s.kind, s.id, n, point, value); err != nil {
+		return err
+	}
+	return s.dal.SetCheckpointCount(s.kind, s.id, n+1)
+}
+
+// lowerBound 返回第一个时间点>=point的检查点下标，不存在时返回序列长度
+func (s checkpoints) lowerBound(point uint64) (uint64, error) {
+	n, err := s.length()
+	if err != nil {
+		return 0, err
+	}
+	low, high := uint64(0), n
+	for low < high {
+		mid := low + (high-low)/2
+		p, _, err := s.at(mid)
+		if err != nil {
+			return 0, err
+		}
+		if p < point {
+			low = mid + 1
+		} else {
+			high = mid
+		}
+	}
+	return low, nil
+}
+
+// upperLookup 返回最后一个时间点<=point的检查点的值，不存在时返回0
+func (s checkpoints) upperLookup(point uint64) (*common.SafeUint256, error) {
+	if point == ^uint64(0) {
+		_, value, _, err := s.latest()
+		return value, err
+	}
+	index, err := s.lowerBound(point + 1)
+	if err != nil {
+		return nil, err
+	}
+	if index == 0 {
+		return common.NewSafeUint256(0), nil
+	}
+	_, value, err := s.at(index - 1)
+	return value, err
+}
@@ -0,0 +1,136 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erc20
+
+import (
+	"fmt"
+
+	"github.com/studyzy/openzeppelin-go/access"
+	"github.com/studyzy/openzeppelin-go/common"
+)
+
+// IsFrozen 返回account是否被冻结
+func (c *ERC20Contract) IsFrozen(account common.Account) (bool, error) {
+	return c.dal.IsFrozen(account)
+}
+
+/**
+ * @dev Freezes `account`, it can no longer send, receive, burn or spend
+ * allowances until it is unfrozen.
+ *
+ * Emits an {AccountFrozen} event.
+ *
+ * Requirements:
+ *
+ * - the caller must have the `ComplianceRole`.
+ * - `account` cannot be the zero address.
+ */
+func (c *ERC20Contract) Freeze(account common.Account) error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		sender, err := c.complianceSender()
+		if err != nil {
+			return err
+		}
+		if err = checkAccount(account); err != nil {
+			return err
+		}
+		if err = c.dal.SetFrozen(account, true); err != nil {
+			return err
+		}
+		c.sdk.EmitEvent("accountFrozen", account.ToString(), sender.ToString())
+		return nil
+	})
+}
+
+/**
+ * @dev Unfreezes `account`.
+ *
+ * Emits an {AccountUnfrozen} event.
+ *
+ * Requirements:
+ *
+ * - the caller must have the `ComplianceRole`.
+ */
+func (c *ERC20Contract) Unfreeze(account common.Account) error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		sender, err := c.complianceSender()
+		if err != nil {
+			return err
+		}
+		if err = c.dal.SetFrozen(account, false); err != nil {
+			return err
+		}
+		c.sdk.EmitEvent("accountUnfrozen", account.ToString(), sender.ToString())
+		return nil
+	})
+}
+
+/**
+ * @dev Forcibly moves `amount` tokens from `from` to `to`, even if `from` is frozen.
+ *
+ * Emits a {Transfer} event and a {Seized} event.
+ *
+ * Requirements:
+ *
+ * - the caller must have the `ComplianceRole`.
+ * - `to` cannot be frozen.
+ * - `from` must have a balance of at least `amount`.
+ */
+func (c *ERC20Contract) Seize(from, to common.Account, amount *common.SafeUint256) (bool, error) {
+	err := common.Atomic(c.sdk, c.SetSDK, func() error {
+		sender, err := c.complianceSender()
+		if err != nil {
+			return err
+		}
+		if err = c.requireNotFrozen(to); err != nil {
+			return err
+		}
+		if err = c.baseMove(from, to, amount); err != nil {
+			return err
+		}
+		c.sdk.EmitEvent("seized", from.ToString(), to.ToString(), amount.ToString(), sender.ToString())
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// complianceSender 检查调用者拥有合规角色并返回调用者
+func (c *ERC20Contract) complianceSender() (common.Account, error) {
+	if err := c.OnlyRole(access.ComplianceRole); err != nil {
+		return nil, err
+	}
+	sender, err := c.sdk.GetTxSender()
+	if err != nil {
+		return nil, fmt.Errorf("Get sender address failed, err:%s", err)
+	}
+	return sender, nil
+}
+
+// requireNotFrozen 任何一个账户被冻结都返回错误
+func (c *ERC20Contract) requireNotFrozen(accounts ...common.Account) error {
+	for _, account := range accounts {
+		frozen, err := c.dal.IsFrozen(account)
+		if err != nil {
+			return err
+		}
+		if frozen {
+			return fmt.Errorf("ERC20Compliance: account %s is frozen", account.ToString())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erc20
+
+import (
+	"testing"
+
+	"github.com/studyzy/openzeppelin-go/access"
+	"github.com/studyzy/openzeppelin-go/mock"
+)
+
+func TestFreezeAndSeize(t *testing.T) {
+	chain, token := newToken(t, Option{})
+	mint(t, chain, token, alice, 100)
+	//InitERC20把合规角色授予了admin
+	ok, err := token.HasRole(access.ComplianceRole, admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("admin should have the compliance role")
+	}
+	mustFail(t, chain, bob, func() error {
+		return token.Freeze(alice)
+	}, "is missing role")
+	mustInvoke(t, chain, admin, func() error {
+		return token.Freeze(alice)
+	})
+	mustFail(t, chain, alice, func() error {
+		_, err := token.Transfer(bob, amount(10))
+		return err
+	}, "frozen")
+	mustFail(t, chain, bob, func() error {
+		_, err := token.Transfer(alice, amount(0))
+		return err
+	}, "frozen")
+	//被冻结的账户也可以被强制划转
+	mustInvoke(t, chain, admin, func() error {
+		_, err := token.Seize(alice, bob, amount(40))
+		return err
+	})
+	requireBalance(t, token, alice, 60)
+	requireBalance(t, token, bob, 40)
+	mustInvoke(t, chain, admin, func() error {
+		return token.Unfreeze(alice)
+	})
+	mustInvoke(t, chain, alice, func() error {
+		_, err := token.Transfer(bob, amount(10))
+		return err
+	})
+	requireBalance(t, token, bob, 50)
+}
+
+func TestFrozenKeyDoesNotCollide(t *testing.T) {
+	chain, token := newToken(t, Option{})
+	mint(t, chain, token, alice, 100)
+	//前缀拼接时"f"+"eeBps"会覆盖手续费率，之后的转账都无法计算手续费
+	mustInvoke(t, chain, admin, func() error {
+		return token.Freeze(mock.NewAccount("eeBps"))
+	})
+	mustInvoke(t, chain, alice, func() error {
+		_, err := token.Transfer(bob, amount(10))
+		return err
+	})
+	requireBalance(t, token, bob, 10)
+}
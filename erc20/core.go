@@ -17,7 +17,9 @@ package erc20
 import (
 	"errors"
 
+	"github.com/studyzy/openzeppelin-go/access"
 	"github.com/studyzy/openzeppelin-go/common"
+	"github.com/studyzy/openzeppelin-go/security"
 )
 
 // Option 初始化ERC20合约的选项
@@ -30,6 +32,11 @@ type Option struct {
 	Burnable bool
 	// Minable 是否允许后续铸造
 	Minable bool
+	// Domain Permit签名使用的域，Name为空时使用代币名称，Version为空时使用"1"，
+	// ChainId和VerifyingContract用来防止签名被重放到其他链或者其他合约上，使用Permit时必须设置
+	Domain common.TypedDataDomain
+	// FlashFee 计算闪电贷的手续费，为nil时不收手续费
+	FlashFee func(amount *common.SafeUint256) (*common.SafeUint256, error)
 }
 
 func checkAccount(acct ...common.Account) error {
@@ -42,8 +49,60 @@ func checkAccount(acct ...common.Account) error {
 }
 func (c *ERC20Contract) SetSDK(sdk common.ContractSDK) {
 	c.sdk = sdk
+	c.dal = NewERC20ContractDAL(sdk)
+	c.AccessControl = access.NewAccessControl(sdk)
+	c.Ownable = access.NewOwnableWithRoles(sdk, c.AccessControl)
+	c.RolePausable = security.NewRolePausable(sdk, c.AccessControl, access.PauserRole, "ERC20")
 }
+
+// BaseMint 给account铸造amount个代币，不检查调用者的权限，供其他包中基于ERC20Contract的合约使用
+func (c *ERC20Contract) BaseMint(account common.Account, amount *common.SafeUint256) error {
+	return c.baseMint(account, amount)
+}
+
+// BaseBurn 销毁account的amount个代币，不检查调用者的权限，供其他包中基于ERC20Contract的合约使用
+func (c *ERC20Contract) BaseBurn(account common.Account, amount *common.SafeUint256) error {
+	return c.baseBurn(account, amount)
+}
+
+// BaseSpendAllowance 扣减owner给spender的授权额度，供其他包中基于ERC20Contract的合约使用
+func (c *ERC20Contract) BaseSpendAllowance(owner common.Account, spender common.Account, amount *common.SafeUint256) error {
+	return c.baseSpendAllowance(owner, spender, amount)
+}
+
+// setSelf 记录本合约的地址，包装代币和闪电贷共用同一个记录，已经记录过时必须相同
+func (c *ERC20Contract) setSelf(self common.Account) error {
+	current, err := c.dal.GetSelf()
+	if err != nil {
+		return err
+	}
+	if !current.IsZero() {
+		return common.Require(current.Equal(self), "ERC20: self address mismatch")
+	}
+	return c.dal.SetSelf(self)
+}
+
 func (c *ERC20Contract) baseTransfer(from common.Account, to common.Account, amount *common.SafeUint256) error {
+	_, err := c.baseTransferNet(from, to, amount)
+	return err
+}
+
+// baseTransferNet 与baseTransfer相同，同时返回扣除手续费之后to实际收到的数量
+func (c *ERC20Contract) baseTransferNet(from common.Account, to common.Account, amount *common.SafeUint256) (
+	*common.SafeUint256, error) {
+	//被冻结的账户不能转出也不能转入
+	if err := c.requireNotFrozen(from, to); err != nil {
+		return nil, err
+	}
+	//配置了手续费时，手续费部分单独转给手续费接收账户
+	return c.baseTransferWithFee(from, to, amount)
+}
+
+// baseMove 不检查冻结状态的转账，强制划转时也使用它
+func (c *ERC20Contract) baseMove(from common.Account, to common.Account, amount *common.SafeUint256) error {
+	if err := c.RequireNotPaused(); err != nil {
+		return err
+	}
 	//检查from和to的合法性
 	err := checkAccount(from, to)
 	if err != nil {
@@ -55,6 +114,10 @@ func (c *ERC20Contract) baseTransfer(from common.Account, to common.Account, amo
 			return err
 		}
 	}
+	//余额变化前先记录快照
+	if err = c.updateSnapshot(from, to); err != nil {
+		return err
+	}
 	//检查from余额充足
 	fromBalance, err := c.dal.GetBalance(from)
 	if err != nil {
@@ -84,6 +147,10 @@ func (c *ERC20Contract) baseTransfer(from common.Account, to common.Account, amo
 	//触发事件
 
 	c.sdk.EmitEvent("transfer", from.ToString(), to.ToString(), amount.ToString())
+	//余额变化后转移投票权
+	if err = c.updateVotes(from, to, amount); err != nil {
+		return err
+	}
 	//触发用户自定义的afterTransfer
 	if c.option.AfterTransfer != nil {
 		return c.option.AfterTransfer(from, to, amount)
@@ -92,6 +159,9 @@ func (c *ERC20Contract) baseTransfer(from common.Account, to common.Account, amo
 }
 
 func (c *ERC20Contract) baseApprove(owner common.Account, spender common.Account, amount *common.SafeUint256) error {
+	if err := c.RequireNotPaused(); err != nil {
+		return err
+	}
 	//检查from和to的合法性
 	err := checkAccount(owner, spender)
 	if err != nil {
@@ -108,6 +178,10 @@ func (c *ERC20Contract) baseApprove(owner common.Account, spender common.Account
 }
 
 func (c *ERC20Contract) baseSpendAllowance(owner common.Account, spender common.Account, amount *common.SafeUint256) error {
+	//被冻结的账户不能使用授权，也不能被他人使用授权
+	if err := c.requireNotFrozen(owner, spender); err != nil {
+		return err
+	}
 	//获得授权的额度
 	currentAllowance, err := c.dal.GetAllowance(owner, spender)
 	if err != nil {
@@ -129,6 +203,9 @@ func (c *ERC20Contract) baseSpendAllowance(owner common.Account, spender common.
 	return c.baseApprove(owner, spender, newCurrentAllowance)
 }
 func (c *ERC20Contract) baseMint(account common.Account, amount *common.SafeUint256) error {
+	if err := c.RequireNotPaused(); err != nil {
+		return err
+	}
 	//检查account的合法性
 	err := checkAccount(account)
 	if err != nil {
@@ -142,6 +219,10 @@ func (c *ERC20Contract) baseMint(account common.Account, amount *common.SafeUint
 			return err
 		}
 	}
+	//余额变化前先记录快照
+	if err = c.updateSnapshot(from, account); err != nil {
+		return err
+	}
 	//更新TotalSupply
 	totalSupply, err := c.dal.GetTotalSupply()
 	if err != nil {
@@ -151,6 +232,14 @@ func (c *ERC20Contract) baseMint(account common.Account, amount *common.SafeUint
 	if !ok {
 		return errors.New("calculate totalSupply failed")
 	}
+	//铸币后总量不能超过发行上限
+	capValue, err := c.dal.GetCap()
+	if err != nil {
+		return err
+	}
+	if !capValue.GTE(newTotal) {
+		return errors.New("ERC20Capped: cap exceeded")
+	}
 	err = c.dal.SetTotalSupply(newTotal)
 	if err != nil {
 		return err
@@ -170,6 +259,10 @@ func (c *ERC20Contract) baseMint(account common.Account, amount *common.SafeUint
 	}
 	//触发事件
 	c.sdk.EmitEvent("transfer", from.ToString(), account.ToString(), amount.ToString())
+	//余额变化后转移投票权
+	if err = c.updateVotes(from, account, amount); err != nil {
+		return err
+	}
 	//触发用户自定义的afterTransfer
 	if c.option.AfterTransfer != nil {
 		return c.option.AfterTransfer(from, account, amount)
@@ -178,6 +271,12 @@ func (c *ERC20Contract) baseMint(account common.Account, amount *common.SafeUint
 }
 
 func (c *ERC20Contract) baseBurn(account common.Account, amount *common.SafeUint256) error {
+	if err := c.RequireNotPaused(); err != nil {
+		return err
+	}
+	if err := c.requireNotFrozen(account); err != nil {
+		return err
+	}
 	//检查account的合法性
 	err := checkAccount(account)
 	if err != nil {
@@ -190,6 +289,10 @@ func (c *ERC20Contract) baseBurn(account common.Account, amount *common.SafeUint
 			return err
 		}
 	}
+	//余额变化前先记录快照
+	if err = c.updateSnapshot(account, to); err != nil {
+		return err
+	}
 	//检查用户余额充足
 	fromBalance, err := c.dal.GetBalance(account)
 	if err != nil {
@@ -222,6 +325,10 @@ func (c *ERC20Contract) baseBurn(account common.Account, amount *common.SafeUint
 	}
 	//触发事件
 	c.sdk.EmitEvent("transfer", account.ToString(), to.ToString(), amount.ToString())
+	//余额变化后转移投票权
+	if err = c.updateVotes(account, to, amount); err != nil {
+		return err
+	}
 	//触发用户自定义的afterTransfer
 	if c.option.AfterTransfer != nil {
 		return c.option.AfterTransfer(account, to, amount)
@@ -17,6 +17,7 @@ package erc20
 import (
 	"errors"
 	"strconv"
+	"strings"
 
 	"github.com/studyzy/openzeppelin-go/common"
 )
@@ -28,6 +29,21 @@ const (
 	nameKey        = "name"
 	symbolKey      = "symbol"
 	decimalKey     = "decimal"
+	checkpointKey  = "c"
+	checkpointLen  = "cl"
+	snapshotIdKey  = "snapshotId"
+	delegateKey    = "d"
+	capKey         = "cap"
+	nonceKey       = "n"
+	holderKey      = "h"
+	holderCountKey = "holderCount"
+	underlyingKey  = "underlying"
+	selfKey        = "self"
+	frozenKey      = "f"
+	feeBpsKey      = "feeBps"
+	feeToKey       = "feeRecipient"
+	feeMaxKey      = "feeMax"
+	feeExemptKey   = "fe"
 	adminKey       = "admin"
 )
 
@@ -54,13 +70,145 @@ func (c *ERC20ContractDAL) GetUint256(key string) (*common.SafeUint256, error) {
 func (c *ERC20ContractDAL) GetBalance(account common.Account) (*common.SafeUint256, error) {
 	return c.GetUint256(balanceKey + account.ToString())
 }
+
+// SetBalance 更新余额的同时维护持有人索引，余额为0的账户会从索引中移除
 func (c *ERC20ContractDAL) SetBalance(account common.Account, amount *common.SafeUint256) error {
-	return c.sdk.PutState(balanceKey+account.ToString(), []byte(amount.ToString()))
+	if err := c.sdk.PutState(balanceKey+account.ToString(), []byte(amount.ToString())); err != nil {
+		return err
+	}
+	return c.updateHolderIndex(account, !amount.Equal(common.SafeUintZero))
+}
+
+// IndexHolder 按account当前的余额更新持有人索引
+func (c *ERC20ContractDAL) IndexHolder(account common.Account) error {
+	balance, err := c.GetBalance(account)
+	if err != nil {
+		return err
+	}
+	return c.updateHolderIndex(account, !balance.Equal(common.SafeUintZero))
+}
+
+func (c *ERC20ContractDAL) updateHolderIndex(account common.Account, holding bool) error {
+	key, err := c.sdk.CreateCompositeKey(holderKey, account.ToString())
+	if err != nil {
+		return err
+	}
+	b, err := c.sdk.GetState(key)
+	if err != nil {
+		return err
+	}
+	if indexed := len(b) > 0; indexed == holding {
+		return nil
+	}
+	count, err := c.GetHolderCount()
+	if err != nil {
+		return err
+	}
+	if holding {
+		err = c.sdk.PutState(key, []byte("true"))
+		count++
+	} else {
+		err = c.sdk.DelState(key)
+		count--
+	}
+	if err != nil {
+		return err
+	}
+	return c.sdk.PutState(holderCountKey, []byte(strconv.FormatUint(count, 10)))
+}
+
+// GetHolderCount 获得余额不为0的账户数量
+func (c *ERC20ContractDAL) GetHolderCount() (uint64, error) {
+	b, err := c.sdk.GetState(holderCountKey)
+	if err != nil || len(b) == 0 {
+		return 0, err
+	}
+	return strconv.ParseUint(string(b), 10, 64)
 }
+
+// GetHolders 按账户的字典序分页获得持有人，跳过前offset个，最多返回limit个
+func (c *ERC20ContractDAL) GetHolders(offset, limit uint64) ([]common.Account, error) {
+	it, err := c.sdk.NewIteratorWithCompositeKey(holderKey)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	holders := make([]common.Account, 0)
+	for i := uint64(0); it.HasNext() && uint64(len(holders)) < limit; i++ {
+		key, _, err := it.Next()
+		if err != nil {
+			return nil, err
+		}
+		if i < offset {
+			continue
+		}
+		_, parts, err := c.sdk.SplitCompositeKey(key)
+		if err != nil {
+			return nil, err
+		}
+		account, err := c.sdk.NewAccountFromString(parts[0])
+		if err != nil {
+			return nil, err
+		}
+		holders = append(holders, account)
+	}
+	return holders, nil
+}
+
+// SetAllowance 授权额度为0时删除记录，这样遍历授权时只会得到有效的授权
 func (c *ERC20ContractDAL) SetAllowance(owner common.Account, spender common.Account, amount *common.SafeUint256) error {
 	key, _ := c.sdk.CreateCompositeKey(allowanceKey, owner.ToString(), spender.ToString())
+	if amount.Equal(common.SafeUintZero) {
+		return c.sdk.DelState(key)
+	}
 	return c.sdk.PutState(key, []byte(amount.ToString()))
 }
+
+// SpenderAllowance 被授权账户及其授权额度
+type SpenderAllowance struct {
+	Spender common.Account
+	Amount  *common.SafeUint256
+}
+
+// GetAllowances 按被授权账户的字典序分页获得owner给出的授权，跳过前offset个，最多返回limit个，
+// 索引上线之前写入的额度为0的授权不计入
+func (c *ERC20ContractDAL) GetAllowances(owner common.Account, offset, limit uint64) ([]SpenderAllowance, error) {
+	it, err := c.sdk.NewIteratorWithCompositeKey(allowanceKey, owner.ToString())
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	allowances := make([]SpenderAllowance, 0)
+	for i := uint64(0); it.HasNext() && uint64(len(allowances)) < limit; {
+		key, value, err := it.Next()
+		if err != nil {
+			return nil, err
+		}
+		amount, ok := common.ParseSafeUint256(string(value))
+		if !ok {
+			return nil, errors.New("invalid uint256 data")
+		}
+		if amount.Equal(common.SafeUintZero) {
+			continue
+		}
+		if i++; i <= offset {
+			continue
+		}
+		_, parts, err := c.sdk.SplitCompositeKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if len(parts) != 2 {
+			return nil, errors.New("invalid allowance key")
+		}
+		spender, err := c.sdk.NewAccountFromString(parts[1])
+		if err != nil {
+			return nil, err
+		}
+		allowances = append(allowances, SpenderAllowance{Spender: spender, Amount: amount})
+	}
+	return allowances, nil
+}
 func (c *ERC20ContractDAL) GetAllowance(owner common.Account, spender common.Account) (*common.SafeUint256, error) {
 	key, _ := c.sdk.CreateCompositeKey(allowanceKey, owner.ToString(), spender.ToString())
 	return c.GetUint256(key)
@@ -98,16 +246,234 @@ func (c *ERC20ContractDAL) SetDecimals(decimal uint8) error {
 	return c.sdk.PutState(decimalKey, []byte(strconv.Itoa(int(decimal))))
 }
 
-func (c *ERC20ContractDAL) GetAdmin() (common.Account, error) {
-	b, err := c.sdk.GetState(adminKey)
+// GetCap 获得发行上限，没有设置上限时返回MaxUint256
+func (c *ERC20ContractDAL) GetCap() (*common.SafeUint256, error) {
+	b, err := c.sdk.GetState(capKey)
 	if err != nil {
 		return nil, err
 	}
+	if len(b) == 0 {
+		//返回一个新的对象，避免调用方修改全局的MaxSafeUint256
+		b = []byte(common.MaxSafeUint256.ToString())
+	}
+	capValue, ok := common.ParseSafeUint256(string(b))
+	if !ok {
+		return nil, errors.New("invalid uint256 data")
+	}
+	return capValue, nil
+}
+func (c *ERC20ContractDAL) SetCap(capValue *common.SafeUint256) error {
+	return c.sdk.PutState(capKey, []byte(capValue.ToString()))
+}
+
+// GetCheckpointCount 获得某个检查点序列中检查点的数量
+func (c *ERC20ContractDAL) GetCheckpointCount(kind, id string) (uint64, error) {
+	key, err := c.sdk.CreateCompositeKey(checkpointLen, kind, id)
+	if err != nil {
+		return 0, err
+	}
+	b, err := c.sdk.GetState(key)
+	if err != nil || len(b) == 0 {
+		return 0, err
+	}
+	return strconv.ParseUint(string(b), 10, 64)
+}
+func (c *ERC20ContractDAL) SetCheckpointCount(kind, id string, count uint64) error {
+	key, err := c.sdk.CreateCompositeKey(checkpointLen, kind, id)
+	if err != nil {
+		return err
+	}
+	return c.sdk.PutState(key, []byte(strconv.FormatUint(count, 10)))
+}
+
+// GetCheckpoint 获得检查点序列中第index个检查点，存储格式为"时间点,数值"
+func (c *ERC20ContractDAL) GetCheckpoint(kind, id string, index uint64) (uint64, *common.SafeUint256, error) {
+	key, err := c.sdk.CreateCompositeKey(checkpointKey, kind, id, strconv.FormatUint(index, 10))
+	if err != nil {
+		return 0, nil, err
+	}
+	b, err := c.sdk.GetState(key)
+	if err != nil {
+		return 0, nil, err
+	}
+	parts := strings.SplitN(string(b), ",", 2)
+	if len(parts) != 2 {
+		return 0, nil, errors.New("invalid checkpoint data")
+	}
+	point, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, nil, err
+	}
+	value, ok := common.ParseSafeUint256(parts[1])
+	if !ok {
+		return 0, nil, errors.New("invalid uint256 data")
+	}
+	return point, value, nil
+}
+func (c *ERC20ContractDAL) SetCheckpoint(kind, id string, index uint64, point uint64, value *common.SafeUint256) error {
+	key, err := c.sdk.CreateCompositeKey(checkpointKey, kind, id, strconv.FormatUint(index, 10))
+	if err != nil {
+		return err
+	}
+	return c.sdk.PutState(key, []byte(strconv.FormatUint(point, 10)+","+value.ToString()))
+}
+
+func (c *ERC20ContractDAL) GetCurrentSnapshotId() (uint64, error) {
+	b, err := c.sdk.GetState(snapshotIdKey)
+	if err != nil || len(b) == 0 {
+		return 0, err
+	}
+	return strconv.ParseUint(string(b), 10, 64)
+}
+func (c *ERC20ContractDAL) SetCurrentSnapshotId(id uint64) error {
+	return c.sdk.PutState(snapshotIdKey, []byte(strconv.FormatUint(id, 10)))
+}
+
+// GetDelegate 获得account委托投票权的账户，没有委托时返回零地址
+func (c *ERC20ContractDAL) GetDelegate(account common.Account) (common.Account, error) {
+	return c.getAccount(delegateKey + account.ToString())
+}
+func (c *ERC20ContractDAL) SetDelegate(account common.Account, delegatee common.Account) error {
+	return c.sdk.PutState(delegateKey+account.ToString(), []byte(delegatee.ToString()))
+}
+
+// GetNonce 获得account的Permit签名序号
+func (c *ERC20ContractDAL) GetNonce(account common.Account) (uint64, error) {
+	key, err := c.sdk.CreateCompositeKey(nonceKey, account.ToString())
+	if err != nil {
+		return 0, err
+	}
+	b, err := c.sdk.GetState(key)
+	if err != nil || len(b) == 0 {
+		return 0, err
+	}
+	return strconv.ParseUint(string(b), 10, 64)
+}
+func (c *ERC20ContractDAL) SetNonce(account common.Account, nonce uint64) error {
+	key, err := c.sdk.CreateCompositeKey(nonceKey, account.ToString())
+	if err != nil {
+		return err
+	}
+	return c.sdk.PutState(key, []byte(strconv.FormatUint(nonce, 10)))
+}
+
+// GetUnderlying 获得包装代币对应的底层代币合约，没有设置时返回零地址
+func (c *ERC20ContractDAL) GetUnderlying() (common.Account, error) {
+	return c.getAccount(underlyingKey)
+}
+func (c *ERC20ContractDAL) SetUnderlying(token common.Account) error {
+	return c.sdk.PutState(underlyingKey, []byte(token.ToString()))
+}
+
+// GetSelf 获得初始化时记录的本合约地址，没有设置时返回零地址
+func (c *ERC20ContractDAL) GetSelf() (common.Account, error) {
+	return c.getAccount(selfKey)
+}
+func (c *ERC20ContractDAL) SetSelf(self common.Account) error {
+	return c.sdk.PutState(selfKey, []byte(self.ToString()))
+}
+
+func (c *ERC20ContractDAL) getAccount(key string) (common.Account, error) {
+	b, err := c.sdk.GetState(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return c.sdk.NewZeroAccount(), nil
+	}
+	return c.sdk.NewAccountFromString(string(b))
+}
+
+// IsFrozen 账户是否被冻结
+func (c *ERC20ContractDAL) IsFrozen(account common.Account) (bool, error) {
+	key, err := c.sdk.CreateCompositeKey(frozenKey, account.ToString())
+	if err != nil {
+		return false, err
+	}
+	b, err := c.sdk.GetState(key)
+	if err != nil {
+		return false, err
+	}
+	return len(b) > 0, nil
+}
+
+// SetFrozen 设置账户的冻结状态，解冻时直接删除记录
+func (c *ERC20ContractDAL) SetFrozen(account common.Account, frozen bool) error {
+	key, err := c.sdk.CreateCompositeKey(frozenKey, account.ToString())
+	if err != nil {
+		return err
+	}
+	if frozen {
+		return c.sdk.PutState(key, []byte("true"))
+	}
+	return c.sdk.DelState(key)
+}
+
+// GetFeeBps 获得转账手续费率，单位为万分之一
+func (c *ERC20ContractDAL) GetFeeBps() (uint64, error) {
+	b, err := c.sdk.GetState(feeBpsKey)
+	if err != nil || len(b) == 0 {
+		return 0, err
+	}
+	return strconv.ParseUint(string(b), 10, 64)
+}
+func (c *ERC20ContractDAL) SetFeeBps(bps uint64) error {
+	return c.sdk.PutState(feeBpsKey, []byte(strconv.FormatUint(bps, 10)))
+}
+
+// GetFeeRecipient 获得手续费接收账户，没有设置时返回零地址
+func (c *ERC20ContractDAL) GetFeeRecipient() (common.Account, error) {
+	return c.getAccount(feeToKey)
+}
+func (c *ERC20ContractDAL) SetFeeRecipient(recipient common.Account) error {
+	return c.sdk.PutState(feeToKey, []byte(recipient.ToString()))
+}
+
+// GetMaxFee 获得单笔转账的手续费上限，0表示不限制
+func (c *ERC20ContractDAL) GetMaxFee() (*common.SafeUint256, error) {
+	return c.GetUint256(feeMaxKey)
+}
+func (c *ERC20ContractDAL) SetMaxFee(maxFee *common.SafeUint256) error {
+	return c.sdk.PutState(feeMaxKey, []byte(maxFee.ToString()))
+}
+
+// IsFeeExempt 账户是否免收手续费
+func (c *ERC20ContractDAL) IsFeeExempt(account common.Account) (bool, error) {
+	key, err := c.sdk.CreateCompositeKey(feeExemptKey, account.ToString())
+	if err != nil {
+		return false, err
+	}
+	b, err := c.sdk.GetState(key)
+	if err != nil {
+		return false, err
+	}
+	return len(b) > 0, nil
+}
+func (c *ERC20ContractDAL) SetFeeExempt(account common.Account, exempt bool) error {
+	key, err := c.sdk.CreateCompositeKey(feeExemptKey, account.ToString())
+	if err != nil {
+		return err
+	}
+	if exempt {
+		return c.sdk.PutState(key, []byte("true"))
+	}
+	return c.sdk.DelState(key)
+}
+
+// GetLegacyAdmin 获得角色权限上线之前保存的管理员，没有保存时返回nil
+func (c *ERC20ContractDAL) GetLegacyAdmin() (common.Account, error) {
+	b, err := c.sdk.GetState(adminKey)
+	if err != nil || len(b) == 0 {
+		return nil, err
+	}
 	return c.sdk.NewAccountFromBytes(b)
 }
-func (c *ERC20ContractDAL) SetAdmin(admin common.Account) error {
-	return c.sdk.PutState(adminKey, admin.Bytes())
+
+// DelLegacyAdmin 迁移完成后删除旧的管理员记录
+func (c *ERC20ContractDAL) DelLegacyAdmin() error {
+	return c.sdk.DelState(adminKey)
 }
+
 func bytes2String(b []byte, err error) (string, error) {
 	return string(b), err
 
@@ -0,0 +1,61 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erc20
+
+import (
+	"github.com/studyzy/openzeppelin-go/access"
+	"github.com/studyzy/openzeppelin-go/common"
+)
+
+// HolderCount 返回余额不为0的账户数量。
+// 注意：索引上线之前就持有代币、之后余额没有变化过的账户不会被计入，需要通过IndexHolders补录
+func (c *ERC20Contract) HolderCount() (uint64, error) {
+	return c.dal.GetHolderCount()
+}
+
+// HoldersPage 按账户的字典序分页返回持有人，跳过前offset个，最多返回limit个。
+// 注意：只有在索引上线之后余额发生过变化或者通过IndexHolders补录过的账户才会出现在结果中
+func (c *ERC20Contract) HoldersPage(offset, limit uint64) ([]common.Account, error) {
+	return c.dal.GetHolders(offset, limit)
+}
+
+// AllowancesOf 按被授权账户的字典序分页返回owner的授权和对应的剩余额度，跳过前offset个，最多返回limit个，
+// 额度为0的授权不会返回
+func (c *ERC20Contract) AllowancesOf(owner common.Account, offset, limit uint64) ([]SpenderAllowance, error) {
+	return c.dal.GetAllowances(owner, offset, limit)
+}
+
+/**
+ * @dev Adds `accounts` holding a non-zero balance to the holder index, and
+ * removes the ones that no longer hold any tokens. Used to backfill holders
+ * whose balances were set before the index existed.
+ *
+ * Requirements:
+ *
+ * - the caller must have the `AdminRole`.
+ */
+func (c *ERC20Contract) IndexHolders(accounts []common.Account) error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		if err := c.OnlyRole(access.AdminRole); err != nil {
+			return err
+		}
+		for _, account := range accounts {
+			if err := c.dal.IndexHolder(account); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
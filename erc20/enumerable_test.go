@@ -0,0 +1,119 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erc20
+
+import (
+	"testing"
+
+	"github.com/studyzy/openzeppelin-go/common"
+	"github.com/studyzy/openzeppelin-go/mock"
+)
+
+func requireHolders(t *testing.T, token *ERC20Contract, offset, limit uint64, want ...mock.Account) {
+	t.Helper()
+	holders, err := token.HoldersPage(offset, limit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(holders) != len(want) {
+		t.Fatalf("got %d holders, want %d", len(holders), len(want))
+	}
+	for i := range want {
+		if !want[i].Equal(holders[i]) {
+			t.Fatalf("holder %d = %s, want %s", i, holders[i].ToString(), want[i])
+		}
+	}
+}
+
+func TestHolders(t *testing.T) {
+	chain, token := newToken(t, Option{})
+	carol := mock.NewAccount("carol")
+	mint(t, chain, token, alice, 100)
+	mint(t, chain, token, bob, 100)
+	mustInvoke(t, chain, bob, func() error {
+		_, err := token.Transfer(alice, amount(100))
+		return err
+	})
+	//模拟索引上线之前写入的余额
+	if err := chain.NewSDK("token").PutState(balanceKey+carol.ToString(), []byte("50")); err != nil {
+		t.Fatal(err)
+	}
+	requireHolders(t, token, 0, 10, alice)
+	mustFail(t, chain, alice, func() error {
+		return token.IndexHolders([]common.Account{carol})
+	}, "is missing role")
+	mustInvoke(t, chain, admin, func() error {
+		return token.IndexHolders([]common.Account{carol, bob})
+	})
+	requireHolders(t, token, 0, 10, alice, carol)
+	requireHolders(t, token, 1, 10, carol)
+	requireHolders(t, token, 0, 1, alice)
+	count, err := token.HolderCount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Fatalf("holder count = %d, want 2", count)
+	}
+}
+
+func TestAllowancesOf(t *testing.T) {
+	chain, token := newToken(t, Option{})
+	carol := mock.NewAccount("carol")
+	dave := mock.NewAccount("dave")
+	for i, spender := range []mock.Account{bob, carol, dave} {
+		value := uint64(i + 1)
+		mustInvoke(t, chain, alice, func() error {
+			_, err := token.Approve(spender, amount(value))
+			return err
+		})
+	}
+	//额度为0的授权不返回，也不占用分页的位置
+	mustInvoke(t, chain, alice, func() error {
+		_, err := token.Approve(carol, amount(0))
+		return err
+	})
+	key, err := chain.NewSDK("token").CreateCompositeKey(allowanceKey, alice.ToString(), "0xstale")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = chain.NewSDK("token").PutState(key, []byte("0")); err != nil {
+		t.Fatal(err)
+	}
+	allowances, err := token.AllowancesOf(alice, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(allowances) != 2 || !bob.Equal(allowances[0].Spender) || !dave.Equal(allowances[1].Spender) {
+		t.Fatalf("unexpected allowances %v", allowances)
+	}
+	if !allowances[1].Amount.Equal(amount(3)) {
+		t.Fatalf("allowance of dave = %s, want 3", allowances[1].Amount.ToString())
+	}
+	allowances, err = token.AllowancesOf(alice, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(allowances) != 1 || !dave.Equal(allowances[0].Spender) {
+		t.Fatalf("unexpected allowances %v", allowances)
+	}
+	allowances, err = token.AllowancesOf(alice, 2, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(allowances) != 0 {
+		t.Fatalf("unexpected allowances %v", allowances)
+	}
+}
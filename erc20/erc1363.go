@@ -0,0 +1,145 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+ERC1363 Payable Token:
+https://eips.ethereum.org/EIPS/eip-1363
+*/
+
+package erc20
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/studyzy/openzeppelin-go/common"
+)
+
+/**
+ * @dev Moves `amount` tokens from the caller's account to `to`
+ * and then calls `onTransferReceived` on `to` with the amount `to` actually
+ * received after the transfer fee.
+ *
+ * Requirements:
+ *
+ * - `to` must be a contract implementing `onTransferReceived`.
+ * - the call to `onTransferReceived` must succeed.
+ */
+func (c *ERC20Contract) TransferAndCall(to common.Account, amount *common.SafeUint256, data []byte) (bool, error) {
+	err := common.Atomic(c.sdk, c.SetSDK, func() error {
+		from, err := c.sdk.GetTxSender()
+		if err != nil {
+			return fmt.Errorf("Get sender address failed, err:%s", err)
+		}
+		received, err := c.baseTransferNet(from, to, amount)
+		if err != nil {
+			return err
+		}
+		return c.baseCheckOnTransferReceived(from, from, to, received, data)
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+/**
+ * @dev Moves `amount` tokens from `from` to `to` using the allowance mechanism
+ * and then calls `onTransferReceived` on `to` with the amount `to` actually
+ * received after the transfer fee.
+ *
+ * Requirements:
+ *
+ * - the caller must have allowance for ``from``'s tokens of at least `amount`.
+ * - `to` must be a contract implementing `onTransferReceived`.
+ * - the call to `onTransferReceived` must succeed.
+ */
+func (c *ERC20Contract) TransferFromAndCall(from, to common.Account, amount *common.SafeUint256, data []byte) (bool, error) {
+	err := common.Atomic(c.sdk, c.SetSDK, func() error {
+		sender, err := c.sdk.GetTxSender()
+		if err != nil {
+			return fmt.Errorf("Get sender address failed, err:%s", err)
+		}
+		if err = c.baseSpendAllowance(from, sender, amount); err != nil {
+			return fmt.Errorf("spend allowance failed, err:%s", err)
+		}
+		received, err := c.baseTransferNet(from, to, amount)
+		if err != nil {
+			return err
+		}
+		return c.baseCheckOnTransferReceived(sender, from, to, received, data)
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+/**
+ * @dev Sets `amount` as the allowance of `spender` over the caller's tokens
+ * and then calls `onApprovalReceived` on `spender`.
+ *
+ * Requirements:
+ *
+ * - `spender` must be a contract implementing `onApprovalReceived`.
+ * - the call to `onApprovalReceived` must succeed.
+ */
+func (c *ERC20Contract) ApproveAndCall(spender common.Account, amount *common.SafeUint256, data []byte) (bool, error) {
+	err := common.Atomic(c.sdk, c.SetSDK, func() error {
+		owner, err := c.sdk.GetTxSender()
+		if err != nil {
+			return fmt.Errorf("Get sender address failed, err:%s", err)
+		}
+		if err = c.baseApprove(owner, spender, amount); err != nil {
+			return err
+		}
+		args := []common.KeyValue{
+			{Key: "owner", Value: owner.Bytes()},
+			{Key: "amount", Value: []byte(amount.ToString())},
+			{Key: "data", Value: data},
+		}
+		response := c.sdk.CallContract(spender, "onApprovalReceived", args)
+		if response.Status == common.OK {
+			return nil
+		}
+		if len(response.Message) == 0 {
+			return errors.New("ERC1363: approve to non ERC1363Spender implementer")
+		}
+		return errors.New(response.Message)
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// baseCheckOnTransferReceived 调用to的onTransferReceived，amount为to实际收到的数量。
+// 不是所有链都能判断一个地址是否为合约，所以这里不预先检查，对普通账户的调用会失败，整个转账随之回滚
+func (c *ERC20Contract) baseCheckOnTransferReceived(operator, from, to common.Account, amount *common.SafeUint256,
+	data []byte) error {
+	args := []common.KeyValue{
+		{Key: "operator", Value: operator.Bytes()},
+		{Key: "from", Value: from.Bytes()},
+		{Key: "amount", Value: []byte(amount.ToString())},
+		{Key: "data", Value: data},
+	}
+	response := c.sdk.CallContract(to, "onTransferReceived", args)
+	if response.Status == common.OK {
+		return nil
+	}
+	if len(response.Message) == 0 {
+		return errors.New("ERC1363: transfer to non ERC1363Receiver implementer")
+	}
+	return errors.New(response.Message)
+}
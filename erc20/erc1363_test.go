@@ -0,0 +1,107 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erc20
+
+import (
+	"testing"
+
+	"github.com/studyzy/openzeppelin-go/mock"
+)
+
+func TestTransferAndCall(t *testing.T) {
+	chain, token := newToken(t, Option{})
+	received := make(map[string]string)
+	receiver := deployReceiver(chain, "receiver", received)
+	treasury := mock.NewAccount("treasury")
+	mint(t, chain, token, alice, 1000)
+	//收取1%的手续费，回调中的数量是接收方实际收到的数量
+	mustInvoke(t, chain, admin, func() error {
+		return token.SetFeeConfig(100, treasury, amount(0))
+	})
+	mustInvoke(t, chain, alice, func() error {
+		_, err := token.TransferAndCall(receiver, amount(500), nil)
+		return err
+	})
+	requireBalance(t, token, receiver, 495)
+	requireBalance(t, token, treasury, 5)
+	if received["onTransferReceived"] != "495" {
+		t.Fatalf("onTransferReceived amount = %s, want 495", received["onTransferReceived"])
+	}
+	//接收方拒绝或者不是合约时整个转账回滚
+	mustFail(t, chain, alice, func() error {
+		_, err := token.TransferAndCall(receiver, amount(100), []byte("reject"))
+		return err
+	}, "receiver: rejected")
+	mustFail(t, chain, alice, func() error {
+		_, err := token.TransferAndCall(bob, amount(100), nil)
+		return err
+	}, "not found")
+	requireBalance(t, token, alice, 500)
+	requireBalance(t, token, bob, 0)
+}
+
+func TestTransferFromAndCall(t *testing.T) {
+	chain, token := newToken(t, Option{})
+	received := make(map[string]string)
+	receiver := deployReceiver(chain, "receiver", received)
+	mint(t, chain, token, alice, 1000)
+	mustInvoke(t, chain, alice, func() error {
+		_, err := token.Approve(bob, amount(300))
+		return err
+	})
+	mustInvoke(t, chain, bob, func() error {
+		_, err := token.TransferFromAndCall(alice, receiver, amount(200), nil)
+		return err
+	})
+	requireBalance(t, token, receiver, 200)
+	if received["onTransferReceived"] != "200" {
+		t.Fatalf("onTransferReceived amount = %s, want 200", received["onTransferReceived"])
+	}
+	mustFail(t, chain, bob, func() error {
+		_, err := token.TransferFromAndCall(alice, receiver, amount(100), []byte("reject"))
+		return err
+	}, "receiver: rejected")
+	allowance, err := token.Allowance(alice, bob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !allowance.Equal(amount(100)) {
+		t.Fatalf("allowance = %s, want 100", allowance.ToString())
+	}
+}
+
+func TestApproveAndCall(t *testing.T) {
+	chain, token := newToken(t, Option{})
+	received := make(map[string]string)
+	spender := deployReceiver(chain, "spender", received)
+	mustInvoke(t, chain, alice, func() error {
+		_, err := token.ApproveAndCall(spender, amount(300), nil)
+		return err
+	})
+	if received["onApprovalReceived"] != "300" {
+		t.Fatalf("onApprovalReceived amount = %s, want 300", received["onApprovalReceived"])
+	}
+	mustFail(t, chain, alice, func() error {
+		_, err := token.ApproveAndCall(spender, amount(500), []byte("reject"))
+		return err
+	}, "receiver: rejected")
+	allowance, err := token.Allowance(alice, spender)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !allowance.Equal(amount(300)) {
+		t.Fatalf("allowance = %s, want 300", allowance.ToString())
+	}
+}
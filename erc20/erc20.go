@@ -23,13 +23,18 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/studyzy/openzeppelin-go/access"
 	"github.com/studyzy/openzeppelin-go/common"
+	"github.com/studyzy/openzeppelin-go/security"
 )
 
 var _ IERC20 = (*ERC20Contract)(nil)
 
 // ERC20Contract erc20 contract
 type ERC20Contract struct {
+	*access.AccessControl
+	*access.Ownable
+	*security.RolePausable
 	option  Option
 	_name   string
 	_symbol string
@@ -43,64 +48,190 @@ type ERC20Contract struct {
 // @param symbol
 // @return *ERC20Contract
 func NewERC20Contract(option Option, name, symbol string, sdk common.ContractSDK) *ERC20Contract {
+	roles := access.NewAccessControl(sdk)
 	erc20 := &ERC20Contract{
-		option:  option,
-		_name:   name,
-		_symbol: symbol,
-		sdk:     sdk,
-		dal:     NewERC20ContractDAL(sdk),
+		AccessControl: roles,
+		Ownable:       access.NewOwnableWithRoles(sdk, roles),
+		RolePausable:  security.NewRolePausable(sdk, roles, access.PauserRole, "ERC20"),
+		option:        option,
+		_name:         name,
+		_symbol:       symbol,
+		sdk:           sdk,
+		dal:           NewERC20ContractDAL(sdk),
 	}
 	return erc20
 }
 
-func (c *ERC20Contract) InitERC20(name, symbol string, decimals uint8, totalSupply *common.SafeUint256, admin common.Account) error {
+// InitERC20 初始化合约，totalSupply为nil时等同于0，capValue为发行上限，nil或者0表示不限制发行量
+func (c *ERC20Contract) InitERC20(name, symbol string, decimals uint8, totalSupply *common.SafeUint256,
+	capValue *common.SafeUint256, admin common.Account) error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		//此处支持在安装合约的时候指定name,symbol
+		//如果没有参数指定，那么就使用NewERC20Contract构造的时候的值
+		if len(name) > 0 {
+			c._name = name
+		}
+		if err := c.dal.SetName(c._name); err != nil {
+			return err
+		}
+		if len(symbol) > 0 {
+			c._symbol = symbol
 
-	//此处支持在安装合约的时候指定name,symbol
-	//如果没有参数指定，那么就使用NewERC20Contract构造的时候的值
-	if len(name) > 0 {
-		c._name = name
-	}
-	if err := c.dal.SetName(c._name); err != nil {
-		return err
-	}
-	if len(symbol) > 0 {
-		c._symbol = symbol
+		}
+		if err := c.dal.SetSymbol(c._symbol); err != nil {
+			return err
+		}
+		//通过安装合约时参数可以修改decimals，如果不指定那么就是18位小数
+		if err := c.dal.SetDecimals(decimals); err != nil {
+			return err
+		}
+		//通过安装合约时参数可以指定发行总量，如果不指定则发行量是0，后期再调用mint函数来铸币
+		//total supply default to zero
+		if totalSupply == nil {
+			totalSupply = common.NewSafeUint256(0)
+		}
+		if err := c.dal.SetTotalSupply(totalSupply); err != nil {
+			return err
+		}
+		//初始发行量也要记录总量检查点，否则GetPastTotalSupply和基于它的治理法定人数会从0开始
+		if totalSupply != nil && !totalSupply.Equal(common.SafeUintZero) {
+			if err := c.writeVotesCheckpoint(c.checkpoints(votesTotalSupplyKind, votesTotalSupplyId), totalSupply); err != nil {
+				return err
+			}
+		}
+		//设置了发行上限时，初始发行量也不能超过上限
+		if capValue != nil && !capValue.Equal(common.SafeUintZero) {
+			if err := common.Require(capValue.GTE(totalSupply), "ERC20Capped: cap exceeded"); err != nil {
+				return err
+			}
+			if err := c.dal.SetCap(capValue); err != nil {
+				return err
+			}
+		}
+		//admin同时是合约的owner，之后可以通过两步转移修改owner
+		if err := c.SetupOwner(admin); err != nil {
+			return fmt.Errorf("set owner failed, err:%s", err)
+		}
+		//给admin授予管理员、铸币、暂停和合规角色，方便后面mint、冻结账户的时候判断权限
+		if err := c.SetupRole(access.AdminRole, admin); err != nil {
+			return fmt.Errorf("set admin failed, err:%s", err)
+		}
+		if err := c.SetupRole(access.MinterRole, admin); err != nil {
+			return fmt.Errorf("set minter failed, err:%s", err)
+		}
+		if err := c.SetupRole(access.PauserRole, admin); err != nil {
+			return fmt.Errorf("set pauser failed, err:%s", err)
+		}
+		if err := c.SetupRole(access.ComplianceRole, admin); err != nil {
+			return fmt.Errorf("set compliance failed, err:%s", err)
+		}
+		return nil
+	})
+}
 
-	}
-	if err := c.dal.SetSymbol(c._symbol); err != nil {
-		return err
-	}
-	//通过安装合约时参数可以修改decimals，如果不指定那么就是18位小数
-	if err := c.dal.SetDecimals(decimals); err != nil {
-		return err
-	}
-	//通过安装合约时参数可以指定发行总量，如果不指定则发行量是0，后期再调用mint函数来铸币
-	//total supply default to zero
-	if err := c.dal.SetTotalSupply(totalSupply); err != nil {
-		return err
-	}
-	//set Admin，方便后面mint的时候判断权限
-	if err := c.dal.SetAdmin(admin); err != nil {
-		return fmt.Errorf("set admin failed, err:%s", err)
-	}
-	return nil
+/**
+ * @dev Grants `AdminRole` and `MinterRole` to the admin stored by versions
+ * before role-based access control and removes the legacy record, so the
+ * admin of an upgraded contract can still mint and manage roles.
+ *
+ * Does nothing if no legacy admin is stored, so it is safe to call on every upgrade.
+ */
+func (c *ERC20Contract) MigrateLegacyAdmin() error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		admin, err := c.dal.GetLegacyAdmin()
+		if err != nil || admin == nil {
+			return err
+		}
+		if err = c.SetupRole(access.AdminRole, admin); err != nil {
+			return fmt.Errorf("set admin failed, err:%s", err)
+		}
+		if err = c.SetupRole(access.MinterRole, admin); err != nil {
+			return fmt.Errorf("set minter failed, err:%s", err)
+		}
+		return c.dal.DelLegacyAdmin()
+	})
 }
 
 func (c *ERC20Contract) TotalSupply() (*common.SafeUint256, error) {
 	return c.dal.GetTotalSupply()
 }
 
+/**
+ * @dev Returns the cap on the token's total supply.
+ * An uncapped token returns the maximum `uint256`.
+ */
+func (c *ERC20Contract) Cap() (*common.SafeUint256, error) {
+	return c.dal.GetCap()
+}
+
 func (c *ERC20Contract) BalanceOf(account common.Account) (*common.SafeUint256, error) {
 	return c.dal.GetBalance(account)
 }
 
 func (c *ERC20Contract) Transfer(to common.Account, amount *common.SafeUint256) (bool, error) {
-	from, err := c.sdk.GetTxSender()
+	err := common.Atomic(c.sdk, c.SetSDK, func() error {
+		from, err := c.sdk.GetTxSender()
+		if err != nil {
+			return fmt.Errorf("Get sender address failed, err:%s", err)
+		}
+		return c.baseTransfer(from, to, amount)
+	})
 	if err != nil {
-		return false, fmt.Errorf("Get sender address failed, err:%s", err)
+		return false, err
 	}
+	return true, nil
+}
 
-	err = c.baseTransfer(from, to, amount)
+/**
+ * @dev Moves `amounts[i]` tokens from the caller's account to `recipients[i]`
+ * for every i, all or nothing.
+ *
+ * Emits a {Transfer} event for every recipient.
+ *
+ * Requirements:
+ *
+ * - `recipients` and `amounts` must have the same non-zero length.
+ * - no recipient can be the zero address and no amount can be nil.
+ * - the caller must have a balance of at least the sum of `amounts`.
+ */
+func (c *ERC20Contract) BatchTransfer(recipients []common.Account, amounts []*common.SafeUint256) (bool, error) {
+	err := common.Atomic(c.sdk, c.SetSDK, func() error {
+		from, err := c.sdk.GetTxSender()
+		if err != nil {
+			return fmt.Errorf("Get sender address failed, err:%s", err)
+		}
+		if err = common.Require(len(recipients) > 0 && len(recipients) == len(amounts),
+			"ERC20: recipients and amounts length mismatch"); err != nil {
+			return err
+		}
+		//先检查所有参数和总金额，避免转了一部分才发现余额不足
+		if err = checkAccount(recipients...); err != nil {
+			return err
+		}
+		total := common.NewSafeUint256(0)
+		for _, amount := range amounts {
+			if err = common.Require(amount != nil, "ERC20: batch amount is nil"); err != nil {
+				return err
+			}
+			var ok bool
+			if total, ok = common.SafeAdd(total, amount); !ok {
+				return errors.New("ERC20: batch amount overflow")
+			}
+		}
+		balance, err := c.dal.GetBalance(from)
+		if err != nil {
+			return err
+		}
+		if !balance.GTE(total) {
+			return errors.New("ERC20: transfer amount exceeds balance")
+		}
+		for i, to := range recipients {
+			if err = c.baseTransfer(from, to, amounts[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		return false, err
 	}
@@ -124,17 +255,17 @@ func (c *ERC20Contract) Transfer(to common.Account, amount *common.SafeUint256)
  * `amount`.
  */
 func (c *ERC20Contract) TransferFrom(from, to common.Account, amount *common.SafeUint256) (bool, error) {
-
-	sender, err := c.sdk.GetTxSender()
-	if err != nil {
-		return false, fmt.Errorf("Get sender address failed, err:%s", err)
-	}
-
-	err = c.baseSpendAllowance(from, sender, amount)
-	if err != nil {
-		return false, fmt.Errorf("spend allowance failed, err:%s", err)
-	}
-	err = c.baseTransfer(from, to, amount)
+	err := common.Atomic(c.sdk, c.SetSDK, func() error {
+		sender, err := c.sdk.GetTxSender()
+		if err != nil {
+			return fmt.Errorf("Get sender address failed, err:%s", err)
+		}
+		err = c.baseSpendAllowance(from, sender, amount)
+		if err != nil {
+			return fmt.Errorf("spend allowance failed, err:%s", err)
+		}
+		return c.baseTransfer(from, to, amount)
+	})
 	if err != nil {
 		return false, err
 	}
@@ -152,12 +283,83 @@ func (c *ERC20Contract) TransferFrom(from, to common.Account, amount *common.Saf
  * - `spender` cannot be the zero address.
  */
 func (c *ERC20Contract) Approve(spender common.Account, amount *common.SafeUint256) (bool, error) {
-	sender, err := c.sdk.GetTxSender()
+	err := common.Atomic(c.sdk, c.SetSDK, func() error {
+		sender, err := c.sdk.GetTxSender()
+		if err != nil {
+			return fmt.Errorf("Get sender address failed, err:%s", err)
+		}
+		return c.baseApprove(sender, spender, amount)
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+/**
+ * @dev Atomically increases the allowance granted to `spender` by the caller.
+ *
+ * This is an alternative to {approve} that can be used as a mitigation for
+ * problems described in {IERC20-approve}.
+ *
+ * Emits an {Approval} event indicating the updated allowance.
+ *
+ * Requirements:
+ *
+ * - `spender` cannot be the zero address.
+ */
+func (c *ERC20Contract) IncreaseAllowance(spender common.Account, addedValue *common.SafeUint256) (bool, error) {
+	err := common.Atomic(c.sdk, c.SetSDK, func() error {
+		owner, err := c.sdk.GetTxSender()
+		if err != nil {
+			return fmt.Errorf("Get sender address failed, err:%s", err)
+		}
+		currentAllowance, err := c.dal.GetAllowance(owner, spender)
+		if err != nil {
+			return err
+		}
+		newAllowance, ok := common.SafeAdd(currentAllowance, addedValue)
+		if !ok {
+			return errors.New("ERC20: increased allowance overflow")
+		}
+		return c.baseApprove(owner, spender, newAllowance)
+	})
 	if err != nil {
-		return false, fmt.Errorf("Get sender address failed, err:%s", err)
+		return false, err
 	}
+	return true, nil
+}
 
-	err = c.baseApprove(sender, spender, amount)
+/**
+ * @dev Atomically decreases the allowance granted to `spender` by the caller.
+ *
+ * This is an alternative to {approve} that can be used as a mitigation for
+ * problems described in {IERC20-approve}.
+ *
+ * Emits an {Approval} event indicating the updated allowance.
+ *
+ * Requirements:
+ *
+ * - `spender` cannot be the zero address.
+ * - `spender` must have allowance for the caller of at least
+ * `subtractedValue`.
+ */
+func (c *ERC20Contract) DecreaseAllowance(spender common.Account, subtractedValue *common.SafeUint256) (bool, error) {
+	err := common.Atomic(c.sdk, c.SetSDK, func() error {
+		owner, err := c.sdk.GetTxSender()
+		if err != nil {
+			return fmt.Errorf("Get sender address failed, err:%s", err)
+		}
+		currentAllowance, err := c.dal.GetAllowance(owner, spender)
+		if err != nil {
+			return err
+		}
+		newAllowance, ok := common.SafeSub(currentAllowance, subtractedValue)
+		if !ok {
+			return errors.New("ERC20: decreased allowance below zero")
+		}
+		return c.baseApprove(owner, spender, newAllowance)
+	})
 	if err != nil {
 		return false, err
 	}
@@ -189,22 +391,14 @@ func (c *ERC20Contract) Decimals() (uint8, error) {
  * - `account` cannot be the zero address.
  */
 func (c *ERC20Contract) Mint(account common.Account, amount *common.SafeUint256) (bool, error) {
-
-	//check is admin
-	sender, err := c.sdk.GetTxSender()
-	if err != nil {
-		return false, fmt.Errorf("Get sender address failed, err:%s", err)
-	}
-
-	admin, err := c.dal.GetAdmin()
-	if err != nil {
-		return false, err
-	}
-	if !sender.Equal(admin) {
-		return false, errors.New("only admin can mint tokens")
-	}
-	//call base mint
-	err = c.baseMint(account, amount)
+	err := common.Atomic(c.sdk, c.SetSDK, func() error {
+		//check is minter
+		if err := c.OnlyRole(access.MinterRole); err != nil {
+			return err
+		}
+		//call base mint
+		return c.baseMint(account, amount)
+	})
 	if err != nil {
 		return false, err
 	}
@@ -212,12 +406,14 @@ func (c *ERC20Contract) Mint(account common.Account, amount *common.SafeUint256)
 }
 
 func (c *ERC20Contract) Burn(amount *common.SafeUint256) (bool, error) {
-	spender, err := c.sdk.GetTxSender()
-	if err != nil {
-		return false, fmt.Errorf("Get sender address failed, err:%s", err)
-	}
-	//call base burn
-	err = c.baseBurn(spender, amount)
+	err := common.Atomic(c.sdk, c.SetSDK, func() error {
+		spender, err := c.sdk.GetTxSender()
+		if err != nil {
+			return fmt.Errorf("Get sender address failed, err:%s", err)
+		}
+		//call base burn
+		return c.baseBurn(spender, amount)
+	})
 	if err != nil {
 		return false, err
 	}
@@ -225,16 +421,18 @@ func (c *ERC20Contract) Burn(amount *common.SafeUint256) (bool, error) {
 }
 
 func (c *ERC20Contract) BurnFrom(account common.Account, amount *common.SafeUint256) (bool, error) {
-	spender, err := c.sdk.GetTxSender()
-	if err != nil {
-		return false, fmt.Errorf("Get sender address failed, err:%s", err)
-	}
-	err = c.baseSpendAllowance(account, spender, amount)
-	if err != nil {
-		return false, err
-	}
-	//call base burn
-	err = c.baseBurn(account, amount)
+	err := common.Atomic(c.sdk, c.SetSDK, func() error {
+		spender, err := c.sdk.GetTxSender()
+		if err != nil {
+			return fmt.Errorf("Get sender address failed, err:%s", err)
+		}
+		err = c.baseSpendAllowance(account, spender, amount)
+		if err != nil {
+			return err
+		}
+		//call base burn
+		return c.baseBurn(account, amount)
+	})
 	if err != nil {
 		return false, err
 	}
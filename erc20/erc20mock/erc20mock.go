@@ -0,0 +1,119 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package erc20mock 在mock链上部署ERC20合约，供依赖其他ERC20合约的合约在测试中通过CallContract调用。
+*/
+package erc20mock
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/studyzy/openzeppelin-go/common"
+	"github.com/studyzy/openzeppelin-go/erc20"
+	"github.com/studyzy/openzeppelin-go/mock"
+)
+
+// Deploy 在chain上部署名为name的ERC20合约并以admin的身份初始化，
+// 返回的合约对象可以在chain.Invoke中直接调用
+func Deploy(chain *mock.Chain, name string, option erc20.Option, admin mock.Account) (*erc20.ERC20Contract, error) {
+	token := erc20.NewERC20Contract(option, name, name, chain.Deploy(name, Handler(option, name)))
+	err := chain.Invoke(admin, func() error {
+		return token.InitERC20("", "", 18, common.NewSafeUint256(0), nil, admin)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// Handler 把erc20.TokenCaller使用的方法分发到ERC20合约，方法名、参数名和返回值与示例合约一致
+func Handler(option erc20.Option, name string) mock.Handler {
+	return func(sdk *mock.SDK, method string, args []common.KeyValue) common.Response {
+		token := erc20.NewERC20Contract(option, name, name, sdk)
+		params := make(map[string][]byte, len(args))
+		for _, arg := range args {
+			params[arg.Key] = arg.Value
+		}
+		account := func(key string) common.Account {
+			return mock.NewAccount(string(params[key]))
+		}
+		switch method {
+		case "totalSupply":
+			return returnUint256(token.TotalSupply())
+		case "balanceOf":
+			return returnUint256(token.BalanceOf(account("account")))
+		case "transfer":
+			amount, err := requireAmount(params["amount"])
+			if err != nil {
+				return mock.Error(err.Error())
+			}
+			return returnBool(token.Transfer(account("to"), amount))
+		case "transferFrom":
+			amount, err := requireAmount(params["amount"])
+			if err != nil {
+				return mock.Error(err.Error())
+			}
+			return returnBool(token.TransferFrom(account("from"), account("to"), amount))
+		case "getPastVotes":
+			blockNumber, err := strconv.ParseUint(string(params["blockNumber"]), 10, 64)
+			if err != nil {
+				return mock.Error(err.Error())
+			}
+			return returnUint256(token.GetPastVotes(account("account"), blockNumber))
+		case "clock":
+			clock, err := token.Clock()
+			if err != nil {
+				return mock.Error(err.Error())
+			}
+			return mock.Success([]byte(strconv.FormatUint(clock, 10)))
+		case "clockMode":
+			mode, err := token.ClockMode()
+			if err != nil {
+				return mock.Error(err.Error())
+			}
+			return mock.Success([]byte(mode))
+		case "getPastTotalSupply":
+			blockNumber, err := strconv.ParseUint(string(params["blockNumber"]), 10, 64)
+			if err != nil {
+				return mock.Error(err.Error())
+			}
+			return returnUint256(token.GetPastTotalSupply(blockNumber))
+		}
+		return mock.Error("Invalid method")
+	}
+}
+
+func requireAmount(value []byte) (*common.SafeUint256, error) {
+	amount, ok := common.ParseSafeUint256(string(value))
+	if !ok {
+		return nil, errors.New("invalid uint256")
+	}
+	return amount, nil
+}
+
+func returnUint256(num *common.SafeUint256, err error) common.Response {
+	if err != nil {
+		return mock.Error(err.Error())
+	}
+	return mock.Success([]byte(num.ToString()))
+}
+
+func returnBool(b bool, err error) common.Response {
+	if err != nil {
+		return mock.Error(err.Error())
+	}
+	return mock.Success([]byte(strconv.FormatBool(b)))
+}
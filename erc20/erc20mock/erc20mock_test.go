@@ -0,0 +1,69 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erc20mock_test
+
+import (
+	"testing"
+
+	"github.com/studyzy/openzeppelin-go/common"
+	"github.com/studyzy/openzeppelin-go/erc20"
+	"github.com/studyzy/openzeppelin-go/erc20/erc20mock"
+	"github.com/studyzy/openzeppelin-go/mock"
+)
+
+func TestTokenCaller(t *testing.T) {
+	chain := mock.NewChain()
+	admin := mock.NewAccount("admin")
+	token, err := erc20mock.Deploy(chain, "token", erc20.Option{}, admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vault := chain.Deploy("vault", nil)
+	err = chain.Invoke(admin, func() error {
+		_, err := token.Mint(vault.Account(), common.NewSafeUint256(100))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	caller := erc20.NewTokenCaller(vault, mock.NewAccount("token"))
+	err = chain.Invoke(admin, func() error {
+		return caller.Transfer(admin, common.NewSafeUint256(30))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	balance, err := caller.BalanceOf(vault.Account())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !balance.Equal(common.NewSafeUint256(70)) {
+		t.Fatalf("balance = %s, want 70", balance.ToString())
+	}
+	//余额不足时被调合约返回错误，写集被回滚
+	err = chain.Invoke(admin, func() error {
+		return caller.Transfer(admin, common.NewSafeUint256(100))
+	})
+	if err == nil {
+		t.Fatal("expected transfer to fail")
+	}
+	balance, err = token.BalanceOf(admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !balance.Equal(common.NewSafeUint256(30)) {
+		t.Fatalf("balance = %s, want 30", balance.ToString())
+	}
+}
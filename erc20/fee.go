@@ -0,0 +1,188 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erc20
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/studyzy/openzeppelin-go/access"
+	"github.com/studyzy/openzeppelin-go/common"
+)
+
+// maxFeeBps 手续费率的分母，费率以万分之一为单位
+const maxFeeBps = 10000
+
+// FeeConfig 转账手续费配置
+type FeeConfig struct {
+	// Bps 手续费率，单位为万分之一
+	Bps uint64
+	// Recipient 手续费接收账户，为零地址时不收手续费
+	Recipient common.Account
+	// MaxFee 单笔转账的手续费上限，0表示不限制
+	MaxFee *common.SafeUint256
+}
+
+// GetFeeConfig 返回当前的转账手续费配置
+func (c *ERC20Contract) GetFeeConfig() (*FeeConfig, error) {
+	bps, err := c.dal.GetFeeBps()
+	if err != nil {
+		return nil, err
+	}
+	recipient, err := c.dal.GetFeeRecipient()
+	if err != nil {
+		return nil, err
+	}
+	maxFee, err := c.dal.GetMaxFee()
+	if err != nil {
+		return nil, err
+	}
+	return &FeeConfig{Bps: bps, Recipient: recipient, MaxFee: maxFee}, nil
+}
+
+/**
+ * @dev Sets the transfer fee to `bps` basis points of every transfer, paid to `recipient`
+ * and capped at `maxFee` per transfer. A nil or zero `maxFee` means no cap.
+ *
+ * A transfer that charges a fee is performed as two moves, `from` to `to` and
+ * `from` to `recipient`, so the {Option} hooks, snapshot and vote updates and the
+ * transfer event run once for each of them.
+ *
+ * Emits a {FeeConfigChanged} event.
+ *
+ * Requirements:
+ *
+ * - the caller must have the `AdminRole`.
+ * - `bps` cannot exceed 10000.
+ * - `recipient` cannot be the zero address unless `bps` is zero.
+ */
+func (c *ERC20Contract) SetFeeConfig(bps uint64, recipient common.Account, maxFee *common.SafeUint256) error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		if err := c.OnlyRole(access.AdminRole); err != nil {
+			return err
+		}
+		if maxFee == nil {
+			maxFee = common.NewSafeUint256(0)
+		}
+		if err := common.Require(bps <= maxFeeBps, "ERC20Fee: fee exceeds 100%"); err != nil {
+			return err
+		}
+		if err := common.Require(bps == 0 || !recipient.IsZero(), "ERC20Fee: fee recipient is the zero address"); err != nil {
+			return err
+		}
+		if err := c.dal.SetFeeBps(bps); err != nil {
+			return err
+		}
+		if err := c.dal.SetFeeRecipient(recipient); err != nil {
+			return err
+		}
+		if err := c.dal.SetMaxFee(maxFee); err != nil {
+			return err
+		}
+		c.sdk.EmitEvent("feeConfigChanged", strconv.FormatUint(bps, 10), recipient.ToString(), maxFee.ToString())
+		return nil
+	})
+}
+
+// IsFeeExempt 返回account转入或转出时是否免收手续费
+func (c *ERC20Contract) IsFeeExempt(account common.Account) (bool, error) {
+	return c.dal.IsFeeExempt(account)
+}
+
+/**
+ * @dev Exempts `account` from (or subjects it again to) the transfer fee,
+ * both when sending and when receiving.
+ *
+ * Emits a {FeeExemptionChanged} event.
+ *
+ * Requirements:
+ *
+ * - the caller must have the `AdminRole`.
+ */
+func (c *ERC20Contract) SetFeeExempt(account common.Account, exempt bool) error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		if err := c.OnlyRole(access.AdminRole); err != nil {
+			return err
+		}
+		if err := c.dal.SetFeeExempt(account, exempt); err != nil {
+			return err
+		}
+		c.sdk.EmitEvent("feeExemptionChanged", account.ToString(), strconv.FormatBool(exempt))
+		return nil
+	})
+}
+
+// CalculateFee 返回从from转给to数量为amount时收取的手续费和手续费接收账户，
+// 不收手续费时fee为0
+func (c *ERC20Contract) CalculateFee(from, to common.Account, amount *common.SafeUint256) (
+	fee *common.SafeUint256, recipient common.Account, err error) {
+	config, err := c.GetFeeConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	if config.Bps == 0 || config.Recipient.IsZero() {
+		return common.NewSafeUint256(0), config.Recipient, nil
+	}
+	for _, account := range []common.Account{from, to} {
+		exempt, err := c.dal.IsFeeExempt(account)
+		if err != nil {
+			return nil, nil, err
+		}
+		if exempt {
+			return common.NewSafeUint256(0), config.Recipient, nil
+		}
+	}
+	fee, ok := common.MulDiv(amount, common.NewSafeUint256(config.Bps), common.NewSafeUint256(maxFeeBps),
+		common.RoundDown)
+	if !ok {
+		return nil, nil, errors.New("ERC20Fee: calculate fee failed")
+	}
+	if !config.MaxFee.Equal(common.SafeUintZero) && fee.GTE(config.MaxFee) {
+		fee = config.MaxFee
+	}
+	return fee, config.Recipient, nil
+}
+
+// baseTransferWithFee 把amount拆成转给to的部分和转给手续费接收账户的部分，返回to实际收到的数量。
+// 手续费部分是第二次baseMove，所以BeforeTransfer、AfterTransfer、快照和投票权的更新以及transfer事件
+// 都会按两笔转账各执行一次，钩子中看到的数量是每一笔各自的数量
+func (c *ERC20Contract) baseTransferWithFee(from, to common.Account, amount *common.SafeUint256) (
+	*common.SafeUint256, error) {
+	fee, recipient, err := c.CalculateFee(from, to, amount)
+	if err != nil {
+		return nil, err
+	}
+	if fee.Equal(common.SafeUintZero) {
+		return amount, c.baseMove(from, to, amount)
+	}
+	if err = c.requireNotFrozen(recipient); err != nil {
+		return nil, err
+	}
+	//先检查余额，避免转出一半之后才失败
+	balance, err := c.dal.GetBalance(from)
+	if err != nil {
+		return nil, err
+	}
+	if !balance.GTE(amount) {
+		return nil, errors.New("ERC20: transfer amount exceeds balance")
+	}
+	//fee不会超过amount，所以这里不会失败；用SafeAdd复制一份amount，避免SafeSub修改调用方传入的值
+	net, _ := common.SafeAdd(amount, common.SafeUintZero)
+	net, _ = common.SafeSub(net, fee)
+	if err = c.baseMove(from, to, net); err != nil {
+		return nil, err
+	}
+	return net, c.baseMove(from, recipient, fee)
+}
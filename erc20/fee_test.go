@@ -0,0 +1,124 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erc20
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/studyzy/openzeppelin-go/common"
+	"github.com/studyzy/openzeppelin-go/mock"
+)
+
+func TestTransferFee(t *testing.T) {
+	chain, token := newToken(t, Option{})
+	treasury := mock.NewAccount("treasury")
+	mint(t, chain, token, alice, 10000)
+	mustFail(t, chain, alice, func() error {
+		return token.SetFeeConfig(100, treasury, nil)
+	}, "is missing role")
+	mustFail(t, chain, admin, func() error {
+		return token.SetFeeConfig(10001, treasury, nil)
+	}, "ERC20Fee: fee exceeds 100%")
+	//maxFee为nil时不设上限
+	mustInvoke(t, chain, admin, func() error {
+		return token.SetFeeConfig(100, treasury, nil)
+	})
+	config, err := token.GetFeeConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Bps != 100 || !treasury.Equal(config.Recipient) || !config.MaxFee.Equal(amount(0)) {
+		t.Fatalf("unexpected fee config %+v", config)
+	}
+	mustInvoke(t, chain, alice, func() error {
+		_, err := token.Transfer(bob, amount(5000))
+		return err
+	})
+	requireBalance(t, token, bob, 4950)
+	requireBalance(t, token, treasury, 50)
+	//手续费不超过maxFee
+	mustInvoke(t, chain, admin, func() error {
+		return token.SetFeeConfig(100, treasury, amount(10))
+	})
+	mustInvoke(t, chain, alice, func() error {
+		_, err := token.Transfer(bob, amount(2000))
+		return err
+	})
+	requireBalance(t, token, bob, 6940)
+	requireBalance(t, token, treasury, 60)
+	//免收手续费的账户
+	mustInvoke(t, chain, admin, func() error {
+		return token.SetFeeExempt(alice, true)
+	})
+	mustInvoke(t, chain, alice, func() error {
+		_, err := token.Transfer(bob, amount(1000))
+		return err
+	})
+	requireBalance(t, token, bob, 7940)
+	requireBalance(t, token, treasury, 60)
+}
+
+func TestFeeExemptKeyDoesNotCollide(t *testing.T) {
+	chain, token := newToken(t, Option{})
+	treasury := mock.NewAccount("treasury")
+	mint(t, chain, token, alice, 1000)
+	mustInvoke(t, chain, admin, func() error {
+		return token.SetFeeConfig(100, treasury, nil)
+	})
+	//前缀拼接时"fe"+"eBps"会覆盖手续费率
+	mustInvoke(t, chain, admin, func() error {
+		return token.SetFeeExempt(mock.NewAccount("eBps"), true)
+	})
+	mustInvoke(t, chain, alice, func() error {
+		_, err := token.Transfer(bob, amount(1000))
+		return err
+	})
+	requireBalance(t, token, bob, 990)
+	requireBalance(t, token, treasury, 10)
+}
+
+func TestTransferFeeRunsHooksPerLeg(t *testing.T) {
+	var before, after []string
+	chain, token := newToken(t, Option{
+		BeforeTransfer: func(from, to common.Account, value *common.SafeUint256) error {
+			before = append(before, to.ToString()+":"+value.ToString())
+			return nil
+		},
+		AfterTransfer: func(from, to common.Account, value *common.SafeUint256) error {
+			after = append(after, to.ToString()+":"+value.ToString())
+			return nil
+		},
+	})
+	treasury := mock.NewAccount("treasury")
+	mint(t, chain, token, alice, 1000)
+	mustInvoke(t, chain, admin, func() error {
+		return token.SetFeeConfig(100, treasury, nil)
+	})
+	before, after = nil, nil
+	events := len(chain.EventsByTopic("transfer"))
+	mustInvoke(t, chain, alice, func() error {
+		_, err := token.Transfer(bob, amount(500))
+		return err
+	})
+	//收取手续费的转账是两笔baseMove，钩子和transfer事件各执行两次
+	want := []string{"bob:495", "treasury:5"}
+	if !reflect.DeepEqual(before, want) || !reflect.DeepEqual(after, want) {
+		t.Fatalf("before = %v, after = %v, want %v", before, after, want)
+	}
+	if got := len(chain.EventsByTopic("transfer")) - events; got != 2 {
+		t.Fatalf("transfer events = %d, want 2", got)
+	}
+}
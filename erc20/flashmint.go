@@ -0,0 +1,152 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erc20
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/studyzy/openzeppelin-go/access"
+	"github.com/studyzy/openzeppelin-go/common"
+)
+
+// FlashLoanCallbackSuccess 接收方的onFlashLoan成功时必须在Payload中返回的值，对应ERC-3156中
+// keccak256("ERC3156FlashBorrower.onFlashLoan")，避免误把其他返回成功的方法当作已经处理了借款
+const FlashLoanCallbackSuccess = "ERC3156FlashBorrower.onFlashLoan"
+
+// InitERC20FlashMint 开启闪电贷。合约无法通过SDK获得自己的地址，所以需要传入本合约的地址self，
+// 接收方在回调中授权self收回本金和手续费
+func (c *ERC20Contract) InitERC20FlashMint(self common.Account) error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		if err := c.OnlyRole(access.AdminRole); err != nil {
+			return err
+		}
+		if err := checkAccount(self); err != nil {
+			return err
+		}
+		return c.setSelf(self)
+	})
+}
+
+/**
+ * @dev Returns the maximum amount of tokens available for loan, which is
+ * the remaining room below the cap.
+ */
+func (c *ERC20Contract) MaxFlashLoan() (*common.SafeUint256, error) {
+	capValue, err := c.dal.GetCap()
+	if err != nil {
+		return nil, err
+	}
+	totalSupply, err := c.dal.GetTotalSupply()
+	if err != nil {
+		return nil, err
+	}
+	maxLoan, ok := common.SafeSub(capValue, totalSupply)
+	if !ok {
+		return common.NewSafeUint256(0), nil
+	}
+	return maxLoan, nil
+}
+
+/**
+ * @dev Returns the fee applied when doing flash loans. By default it is 0,
+ * it can be customized through {Option.FlashFee}.
+ */
+func (c *ERC20Contract) FlashFee(amount *common.SafeUint256) (*common.SafeUint256, error) {
+	if c.option.FlashFee == nil {
+		return common.NewSafeUint256(0), nil
+	}
+	return c.option.FlashFee(amount)
+}
+
+/**
+ * @dev Performs a flash loan. New tokens are minted and sent to the
+ * `receiver`, who is required to implement `onFlashLoan`. After the
+ * callback returns, `amount + fee` is taken back through the allowance
+ * `receiver` gave this contract and burned.
+ *
+ * The `onFlashLoan` call receives the `initiator`, `amount`, `fee` and `data`
+ * arguments, and must return {FlashLoanCallbackSuccess} as payload to accept the loan.
+ *
+ * Requirements:
+ *
+ * - flash minting must be initialized with {InitERC20FlashMint}.
+ * - `amount` must not exceed {maxFlashLoan}.
+ * - `receiver` must be a contract implementing `onFlashLoan`.
+ * - `receiver` must approve this contract for `amount + fee` in the callback
+ *   and hold at least `amount + fee` when the callback returns.
+ */
+func (c *ERC20Contract) FlashLoan(receiver common.Account, amount *common.SafeUint256, data []byte) (bool, error) {
+	err := common.Atomic(c.sdk, c.SetSDK, func() error {
+		initiator, err := c.sdk.GetTxSender()
+		if err != nil {
+			return fmt.Errorf("Get sender address failed, err:%s", err)
+		}
+		self, err := c.dal.GetSelf()
+		if err != nil {
+			return err
+		}
+		if err = common.Require(!self.IsZero(), "ERC20FlashMint: not initialized"); err != nil {
+			return err
+		}
+		maxLoan, err := c.MaxFlashLoan()
+		if err != nil {
+			return err
+		}
+		if err = common.Require(maxLoan.GTE(amount), "ERC20FlashMint: amount exceeds maxFlashLoan"); err != nil {
+			return err
+		}
+		fee, err := c.FlashFee(amount)
+		if err != nil {
+			return err
+		}
+		repayment, ok := common.SafeAdd(amount, fee)
+		if !ok {
+			return errors.New("ERC20FlashMint: repayment overflow")
+		}
+		if err = c.baseMint(receiver, amount); err != nil {
+			return err
+		}
+		args := []common.KeyValue{
+			{Key: "initiator", Value: initiator.Bytes()},
+			{Key: "amount", Value: []byte(amount.ToString())},
+			{Key: "fee", Value: []byte(fee.ToString())},
+			{Key: "data", Value: data},
+		}
+		//归还失败时Atomic会把铸币一起回滚，接收方在回调中对本合约的写入仍需要链丢弃整个交易的写集来回滚
+		response := c.sdk.CallContract(receiver, "onFlashLoan", args)
+		if response.Status != common.OK {
+			if len(response.Message) == 0 {
+				return errors.New("ERC20FlashMint: invalid return value")
+			}
+			return errors.New(response.Message)
+		}
+		if string(response.Payload) != FlashLoanCallbackSuccess {
+			return errors.New("ERC20FlashMint: invalid return value")
+		}
+		//通过接收方给本合约的授权收回本金和手续费，授权或者余额不足时返回错误
+		if err = c.baseSpendAllowance(receiver, self, repayment); err != nil {
+			return fmt.Errorf("ERC20FlashMint: repay failed, err:%s", err)
+		}
+		if err = c.baseBurn(receiver, repayment); err != nil {
+			return fmt.Errorf("ERC20FlashMint: repay failed, err:%s", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
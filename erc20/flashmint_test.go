@@ -0,0 +1,205 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erc20
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/studyzy/openzeppelin-go/common"
+	"github.com/studyzy/openzeppelin-go/mock"
+)
+
+func requireTotalSupply(t *testing.T, token *ERC20Contract, want uint64) {
+	t.Helper()
+	supply, err := token.TotalSupply()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !supply.Equal(amount(want)) {
+		t.Fatalf("total supply = %s, want %d", supply.ToString(), want)
+	}
+}
+
+// onePercentFee 收取借款数量1%的闪电贷手续费，SafeDiv会修改被除数，所以先复制一份
+func onePercentFee(value *common.SafeUint256) (*common.SafeUint256, error) {
+	fee, ok := common.SafeAdd(value, common.SafeUintZero)
+	if !ok {
+		return nil, errors.New("fee overflow")
+	}
+	return common.SafeDiv(fee, amount(100)), nil
+}
+
+// newFlashToken 部署一个开启了闪电贷的代币，它可以被跨合约调用approve，接收方在回调中通过它授权归还
+func newFlashToken(t *testing.T, option Option, totalSupply, capValue *common.SafeUint256) (*mock.Chain, *ERC20Contract) {
+	t.Helper()
+	chain := mock.NewChain()
+	sdk := chain.Deploy("token", func(sdk *mock.SDK, method string, args []common.KeyValue) common.Response {
+		if method != "approve" {
+			return mock.Error("Invalid method")
+		}
+		params := make(map[string][]byte, len(args))
+		for _, arg := range args {
+			params[arg.Key] = arg.Value
+		}
+		value, ok := common.ParseSafeUint256(string(params["amount"]))
+		if !ok {
+			return mock.Error("invalid uint256")
+		}
+		callee := NewERC20Contract(option, "Token", "TKN", sdk)
+		if _, err := callee.Approve(mock.NewAccount(string(params["spender"])), value); err != nil {
+			return mock.Error(err.Error())
+		}
+		return mock.Success([]byte("true"))
+	})
+	token := NewERC20Contract(option, "Token", "TKN", sdk)
+	mustInvoke(t, chain, admin, func() error {
+		return token.InitERC20("", "", 18, totalSupply, capValue, admin)
+	})
+	mustInvoke(t, chain, admin, func() error {
+		return token.InitERC20FlashMint(sdk.Account())
+	})
+	return chain, token
+}
+
+// deployBorrower 部署一个闪电贷接收方，回调中授权token收回本金和手续费并返回FlashLoanCallbackSuccess。
+// data为"reject"时拒绝借款，为"noApprove"时不授权，为"badReturn"时不返回FlashLoanCallbackSuccess
+func deployBorrower(chain *mock.Chain, name string, token common.Account, received map[string]string) mock.Account {
+	chain.Deploy(name, func(sdk *mock.SDK, method string, args []common.KeyValue) common.Response {
+		params := make(map[string][]byte, len(args))
+		for _, arg := range args {
+			params[arg.Key] = arg.Value
+		}
+		received[method] = string(params["amount"])
+		switch string(params["data"]) {
+		case "reject":
+			return mock.Error("receiver: rejected")
+		case "badReturn":
+			return mock.Success(nil)
+		case "noApprove":
+			return mock.Success([]byte(FlashLoanCallbackSuccess))
+		}
+		value, _ := common.ParseSafeUint256(string(params["amount"]))
+		fee, _ := common.ParseSafeUint256(string(params["fee"]))
+		repayment, _ := common.SafeAdd(value, fee)
+		response := sdk.CallContract(token, "approve", []common.KeyValue{
+			{Key: "spender", Value: []byte(token.ToString())},
+			{Key: "amount", Value: []byte(repayment.ToString())},
+		})
+		if response.Status != common.OK {
+			return response
+		}
+		return mock.Success([]byte(FlashLoanCallbackSuccess))
+	})
+	return mock.NewAccount(name)
+}
+
+func TestFlashLoan(t *testing.T) {
+	chain, token := newFlashToken(t, Option{FlashFee: onePercentFee}, amount(0), nil)
+	self := mock.NewAccount("token")
+	received := make(map[string]string)
+	receiver := deployBorrower(chain, "receiver", self, received)
+	//接收方事先持有手续费，回调中授权本合约收回本金和手续费，之后一起被销毁
+	mint(t, chain, token, receiver, 10)
+	mustInvoke(t, chain, alice, func() error {
+		_, err := token.FlashLoan(receiver, amount(1000), nil)
+		return err
+	})
+	if received["onFlashLoan"] != "1000" {
+		t.Fatalf("onFlashLoan amount = %s, want 1000", received["onFlashLoan"])
+	}
+	requireBalance(t, token, receiver, 0)
+	requireTotalSupply(t, token, 0)
+	allowance, err := token.Allowance(receiver, self)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !allowance.Equal(common.SafeUintZero) {
+		t.Fatalf("allowance = %s, want 0", allowance.ToString())
+	}
+
+	//接收方拒绝、返回值不对、没有授权、余额不够归还或者不是合约时，铸币被一起回滚
+	mint(t, chain, token, receiver, 10)
+	mustFail(t, chain, alice, func() error {
+		_, err := token.FlashLoan(receiver, amount(1000), []byte("reject"))
+		return err
+	}, "receiver: rejected")
+	mustFail(t, chain, alice, func() error {
+		_, err := token.FlashLoan(receiver, amount(1000), []byte("badReturn"))
+		return err
+	}, "ERC20FlashMint: invalid return value")
+	mustFail(t, chain, alice, func() error {
+		_, err := token.FlashLoan(receiver, amount(1000), []byte("noApprove"))
+		return err
+	}, "ERC20FlashMint: repay failed")
+	mustFail(t, chain, alice, func() error {
+		_, err := token.FlashLoan(receiver, amount(2000), nil)
+		return err
+	}, "ERC20FlashMint: repay failed")
+	mustFail(t, chain, alice, func() error {
+		_, err := token.FlashLoan(bob, amount(1000), nil)
+		return err
+	}, "not found")
+	requireBalance(t, token, receiver, 10)
+	requireBalance(t, token, bob, 0)
+	requireTotalSupply(t, token, 10)
+}
+
+func TestInitERC20FlashMint(t *testing.T) {
+	chain, token := newToken(t, Option{})
+	receiver := deployBorrower(chain, "receiver", mock.NewAccount("token"), make(map[string]string))
+	mustFail(t, chain, alice, func() error {
+		_, err := token.FlashLoan(receiver, amount(1), nil)
+		return err
+	}, "ERC20FlashMint: not initialized")
+	mustFail(t, chain, alice, func() error {
+		return token.InitERC20FlashMint(mock.NewAccount("token"))
+	}, "is missing role")
+	mustInvoke(t, chain, admin, func() error {
+		return token.InitERC20FlashMint(mock.NewAccount("token"))
+	})
+	//包装代币和闪电贷共用本合约的地址，不能设置成别的地址
+	mustFail(t, chain, admin, func() error {
+		return token.InitERC20FlashMint(mock.NewAccount("other"))
+	}, "ERC20: self address mismatch")
+}
+
+func TestMaxFlashLoan(t *testing.T) {
+	chain, token := newFlashToken(t, Option{}, amount(400), amount(1000))
+	receiver := deployBorrower(chain, "receiver", mock.NewAccount("token"), make(map[string]string))
+	maxLoan, err := token.MaxFlashLoan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !maxLoan.Equal(amount(600)) {
+		t.Fatalf("max flash loan = %s, want 600", maxLoan.ToString())
+	}
+	fee, err := token.FlashFee(amount(600))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fee.Equal(common.SafeUintZero) {
+		t.Fatalf("flash fee = %s, want 0", fee.ToString())
+	}
+	mustFail(t, chain, alice, func() error {
+		_, err := token.FlashLoan(receiver, amount(601), nil)
+		return err
+	}, "ERC20FlashMint: amount exceeds maxFlashLoan")
+	mustInvoke(t, chain, alice, func() error {
+		_, err := token.FlashLoan(receiver, amount(600), nil)
+		return err
+	})
+	requireTotalSupply(t, token, 400)
+}
@@ -0,0 +1,97 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erc20
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/studyzy/openzeppelin-go/common"
+	"github.com/studyzy/openzeppelin-go/mock"
+)
+
+var (
+	admin = mock.NewAccount("admin")
+	alice = mock.NewAccount("alice")
+	bob   = mock.NewAccount("bob")
+)
+
+func amount(v uint64) *common.SafeUint256 {
+	return common.NewSafeUint256(v)
+}
+
+// newToken 在一条新的模拟链上部署并初始化一个由admin管理的ERC20合约
+func newToken(t *testing.T, option Option) (*mock.Chain, *ERC20Contract) {
+	t.Helper()
+	chain := mock.NewChain()
+	token := NewERC20Contract(option, "Token", "TKN", chain.Deploy("token", nil))
+	mustInvoke(t, chain, admin, func() error {
+		return token.InitERC20("", "", 18, amount(0), nil, admin)
+	})
+	return chain, token
+}
+
+func mustInvoke(t *testing.T, chain *mock.Chain, sender mock.Account, fn func() error) {
+	t.Helper()
+	if err := chain.Invoke(sender, fn); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mustFail(t *testing.T, chain *mock.Chain, sender mock.Account, fn func() error, msg string) {
+	t.Helper()
+	err := chain.Invoke(sender, fn)
+	if err == nil {
+		t.Fatalf("expected error containing %q", msg)
+	}
+	if !strings.Contains(err.Error(), msg) {
+		t.Fatalf("got error %q, want %q", err, msg)
+	}
+}
+
+func requireBalance(t *testing.T, token *ERC20Contract, account mock.Account, want uint64) {
+	t.Helper()
+	balance, err := token.BalanceOf(account)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !balance.Equal(amount(want)) {
+		t.Fatalf("balance of %s = %s, want %d", account, balance.ToString(), want)
+	}
+}
+
+func mint(t *testing.T, chain *mock.Chain, token *ERC20Contract, account mock.Account, value uint64) {
+	t.Helper()
+	mustInvoke(t, chain, admin, func() error {
+		_, err := token.Mint(account, amount(value))
+		return err
+	})
+}
+
+// deployReceiver 部署一个记录回调参数的接收方合约，data为"reject"时拒绝
+func deployReceiver(chain *mock.Chain, name string, received map[string]string) mock.Account {
+	chain.Deploy(name, func(sdk *mock.SDK, method string, args []common.KeyValue) common.Response {
+		for _, arg := range args {
+			if arg.Key == "data" && string(arg.Value) == "reject" {
+				return mock.Error("receiver: rejected")
+			}
+			if arg.Key == "amount" {
+				received[method] = string(arg.Value)
+			}
+		}
+		return mock.Success(nil)
+	})
+	return mock.NewAccount(name)
+}
@@ -0,0 +1,58 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erc20
+
+import "testing"
+
+func TestPauseBlocksTransfersAndApprovals(t *testing.T) {
+	chain, token := newToken(t, Option{Minable: true})
+	mint(t, chain, token, alice, 100)
+	mustFail(t, chain, alice, token.Pause, "missing role PAUSER")
+	mustInvoke(t, chain, admin, token.Pause)
+	if paused, _ := token.Paused(); !paused {
+		t.Fatal("token should be paused")
+	}
+	const msg = "ERC20Pausable: token operation while paused"
+	mustFail(t, chain, alice, func() error {
+		_, err := token.Transfer(bob, amount(1))
+		return err
+	}, msg)
+	mustFail(t, chain, alice, func() error {
+		_, err := token.Approve(bob, amount(1))
+		return err
+	}, msg)
+	mustFail(t, chain, alice, func() error {
+		_, err := token.IncreaseAllowance(bob, amount(1))
+		return err
+	}, msg)
+	mustFail(t, chain, admin, func() error {
+		_, err := token.Mint(bob, amount(1))
+		return err
+	}, msg)
+	mustFail(t, chain, alice, func() error {
+		_, err := token.Burn(amount(1))
+		return err
+	}, msg)
+	mustFail(t, chain, admin, token.Pause, "Pausable: paused")
+	mustInvoke(t, chain, admin, token.Unpause)
+	mustInvoke(t, chain, alice, func() error {
+		_, err := token.Transfer(bob, amount(1))
+		return err
+	})
+	requireBalance(t, token, bob, 1)
+	if len(chain.EventsByTopic("paused")) != 1 || len(chain.EventsByTopic("unpaused")) != 1 {
+		t.Fatal("missing paused/unpaused events")
+	}
+}
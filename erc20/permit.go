@@ -0,0 +1,118 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erc20
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/studyzy/openzeppelin-go/common"
+)
+
+const permitType = "Permit(address owner,address spender,uint256 value,uint256 nonce,uint256 deadline)"
+
+/**
+ * @dev Sets `value` as the allowance of `spender` over ``owner``'s tokens,
+ * given ``owner``'s signed approval.
+ *
+ * Emits an {Approval} event.
+ *
+ * Requirements:
+ *
+ * - `spender` cannot be the zero address.
+ * - `deadline` must be a timestamp in the future.
+ * - `signature` must be a valid signature from `owner` over the typed-data
+ * digest of the permit message.
+ * - the signature must use ``owner``'s current nonce (see {nonces}).
+ */
+func (c *ERC20Contract) Permit(owner, spender common.Account, value *common.SafeUint256, deadline int64,
+	signature []byte) error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		now, err := c.sdk.GetTxTimestamp()
+		if err != nil {
+			return err
+		}
+		if err = common.Require(now <= deadline, "ERC20Permit: expired deadline"); err != nil {
+			return err
+		}
+		nonce, err := c.dal.GetNonce(owner)
+		if err != nil {
+			return err
+		}
+		digest, err := c.PermitDigest(owner, spender, value, nonce, deadline)
+		if err != nil {
+			return err
+		}
+		if err = c.sdk.VerifySignature(owner, digest, signature); err != nil {
+			return fmt.Errorf("ERC20Permit: invalid signature, err:%s", err)
+		}
+		//签名只能使用一次
+		if err = c.dal.SetNonce(owner, nonce+1); err != nil {
+			return err
+		}
+		return c.baseApprove(owner, spender, value)
+	})
+}
+
+/**
+ * @dev Returns the current nonce for `owner`. This value must be
+ * included whenever a signature is generated for {permit}.
+ */
+func (c *ERC20Contract) Nonces(owner common.Account) (uint64, error) {
+	return c.dal.GetNonce(owner)
+}
+
+/**
+ * @dev Returns the domain separator used in the encoding of the signature for {permit}.
+ */
+func (c *ERC20Contract) DomainSeparator() ([]byte, error) {
+	domain, err := c.domain()
+	if err != nil {
+		return nil, err
+	}
+	return domain.Separator(), nil
+}
+
+// PermitDigest 计算owner需要签名的摘要，方便链下的钱包生成签名
+func (c *ERC20Contract) PermitDigest(owner, spender common.Account, value *common.SafeUint256, nonce uint64,
+	deadline int64) ([]byte, error) {
+	domain, err := c.domain()
+	if err != nil {
+		return nil, err
+	}
+	structHash := common.HashStruct(permitType, owner.ToString(), spender.ToString(), value.ToString(),
+		strconv.FormatUint(nonce, 10), strconv.FormatInt(deadline, 10))
+	return common.HashTypedData(domain, structHash), nil
+}
+
+func (c *ERC20Contract) domain() (common.TypedDataDomain, error) {
+	domain := c.option.Domain
+	if len(domain.Name) == 0 {
+		name, err := c.dal.GetName()
+		if err != nil {
+			return domain, err
+		}
+		domain.Name = name
+	}
+	if len(domain.Version) == 0 {
+		domain.Version = "1"
+	}
+	//没有链ID和合约地址的签名可以被重放到同名代币的其他部署上
+	if err := common.Require(len(domain.ChainId) > 0 && len(domain.VerifyingContract) > 0,
+		"ERC20Permit: domain chainId and verifyingContract must be set"); err != nil {
+		return domain, err
+	}
+	return domain, nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erc20
+
+import (
+	"testing"
+
+	"github.com/studyzy/openzeppelin-go/common"
+	"github.com/studyzy/openzeppelin-go/mock"
+)
+
+var testDomain = common.TypedDataDomain{ChainId: "chain1", VerifyingContract: "token"}
+
+func signPermit(t *testing.T, token *ERC20Contract, owner, spender mock.Account, value uint64, nonce uint64,
+	deadline int64) []byte {
+	t.Helper()
+	digest, err := token.PermitDigest(owner, spender, amount(value), nonce, deadline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mock.Sign(owner, digest)
+}
+
+func TestPermit(t *testing.T) {
+	chain, token := newToken(t, Option{Domain: testDomain})
+	deadline := int64(1672531200 + 3600)
+	signature := signPermit(t, token, alice, bob, 100, 0, deadline)
+	//任何人都可以提交owner的签名
+	mustInvoke(t, chain, bob, func() error {
+		return token.Permit(alice, bob, amount(100), deadline, signature)
+	})
+	allowance, err := token.Allowance(alice, bob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !allowance.Equal(amount(100)) {
+		t.Fatalf("allowance = %s, want 100", allowance.ToString())
+	}
+	nonce, err := token.Nonces(alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nonce != 1 {
+		t.Fatalf("nonce = %d, want 1", nonce)
+	}
+	//同一个签名不能使用两次
+	mustFail(t, chain, bob, func() error {
+		return token.Permit(alice, bob, amount(100), deadline, signature)
+	}, "ERC20Permit: invalid signature")
+	//签名者必须是owner
+	forged, err := token.PermitDigest(alice, bob, amount(200), 1, deadline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mustFail(t, chain, bob, func() error {
+		return token.Permit(alice, bob, amount(200), deadline, mock.Sign(bob, forged))
+	}, "ERC20Permit: invalid signature")
+	//过期的签名
+	chain.Mine(1, 7200)
+	mustFail(t, chain, bob, func() error {
+		return token.Permit(alice, bob, amount(200), deadline, mock.Sign(alice, forged))
+	}, "ERC20Permit: expired deadline")
+}
+
+func TestPermitDomain(t *testing.T) {
+	_, token := newToken(t, Option{Domain: testDomain})
+	_, other := newToken(t, Option{Domain: common.TypedDataDomain{ChainId: "chain1", VerifyingContract: "other"}})
+	a, err := token.PermitDigest(alice, bob, amount(1), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := other.PermitDigest(alice, bob, amount(1), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(a) == string(b) {
+		t.Fatal("digests of different contracts must differ")
+	}
+	//没有设置链ID和合约地址时拒绝计算摘要
+	_, unset := newToken(t, Option{})
+	if _, err = unset.DomainSeparator(); err == nil {
+		t.Fatal("expected error for domain without chainId and verifyingContract")
+	}
+	if _, err = unset.PermitDigest(alice, bob, amount(1), 0, 0); err == nil {
+		t.Fatal("expected error for domain without chainId and verifyingContract")
+	}
+}
+
+func TestPermitNonceKeyDoesNotCollide(t *testing.T) {
+	chain, token := newToken(t, Option{Domain: testDomain})
+	//前缀拼接时"n"+"ame"会覆盖代币名称
+	ame := mock.NewAccount("ame")
+	deadline := int64(1672531200 + 3600)
+	mustInvoke(t, chain, bob, func() error {
+		return token.Permit(ame, bob, amount(1), deadline, signPermit(t, token, ame, bob, 1, 0, deadline))
+	})
+	if nonce, err := token.Nonces(ame); err != nil || nonce != 1 {
+		t.Fatalf("nonce = %d, err:%v", nonce, err)
+	}
+	if name, err := token.Name(); err != nil || name != "Token" {
+		t.Fatalf("name = %s, err:%v", name, err)
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erc20
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/studyzy/openzeppelin-go/access"
+	"github.com/studyzy/openzeppelin-go/common"
+)
+
+const (
+	snapshotBalanceKind     = "sb"
+	snapshotTotalSupplyKind = "st"
+	snapshotTotalSupplyId   = "total"
+)
+
+/**
+ * @dev Creates a new snapshot and returns its snapshot id.
+ *
+ * Emits a {Snapshot} event that contains the same id.
+ *
+ * Requirements:
+ *
+ * - the caller must have the `AdminRole`.
+ */
+func (c *ERC20Contract) Snapshot() (uint64, error) {
+	var id uint64
+	err := common.Atomic(c.sdk, c.SetSDK, func() error {
+		if err := c.OnlyRole(access.AdminRole); err != nil {
+			return err
+		}
+		current, err := c.dal.GetCurrentSnapshotId()
+		if err != nil {
+			return err
+		}
+		id = current + 1
+		if err = c.dal.SetCurrentSnapshotId(id); err != nil {
+			return err
+		}
+		c.sdk.EmitEvent("snapshot", strconv.FormatUint(id, 10))
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetCurrentSnapshotId 返回最近一次快照的ID，从未做过快照时返回0
+func (c *ERC20Contract) GetCurrentSnapshotId() (uint64, error) {
+	return c.dal.GetCurrentSnapshotId()
+}
+
+/**
+ * @dev Retrieves the balance of `account` at the time `snapshotId` was created.
+ */
+func (c *ERC20Contract) BalanceOfAt(account common.Account, snapshotId uint64) (*common.SafeUint256, error) {
+	value, snapshotted, err := c.valueAt(snapshotId, c.checkpoints(snapshotBalanceKind, account.ToString()))
+	if err != nil || snapshotted {
+		return value, err
+	}
+	return c.dal.GetBalance(account)
+}
+
+/**
+ * @dev Retrieves the total supply at the time `snapshotId` was created.
+ */
+func (c *ERC20Contract) TotalSupplyAt(snapshotId uint64) (*common.SafeUint256, error) {
+	value, snapshotted, err := c.valueAt(snapshotId, c.checkpoints(snapshotTotalSupplyKind, snapshotTotalSupplyId))
+	if err != nil || snapshotted {
+		return value, err
+	}
+	return c.dal.GetTotalSupply()
+}
+
+// valueAt 查找快照snapshotId之后第一次变化前记录的值，
+// 快照之后一直没有变化时snapshotted为false，此时当前值就是快照时的值
+func (c *ERC20Contract) valueAt(snapshotId uint64, s checkpoints) (*common.SafeUint256, bool, error) {
+	if snapshotId == 0 {
+		return nil, false, errors.New("ERC20Snapshot: id is 0")
+	}
+	current, err := c.dal.GetCurrentSnapshotId()
+	if err != nil {
+		return nil, false, err
+	}
+	if snapshotId > current {
+		return nil, false, errors.New("ERC20Snapshot: nonexistent id")
+	}
+	index, err := s.lowerBound(snapshotId)
+	if err != nil {
+		return nil, false, err
+	}
+	n, err := s.length()
+	if err != nil {
+		return nil, false, err
+	}
+	if index == n {
+		return nil, false, nil
+	}
+	_, value, err := s.at(index)
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// updateSnapshot 在余额和总量发生变化之前调用，如果它们在最近一次快照之后还没有被记录过，
+// 就把变化前的值记录下来。from或to为零地址时表示铸币或销毁，需要同时记录总量
+func (c *ERC20Contract) updateSnapshot(from, to common.Account) error {
+	current, err := c.dal.GetCurrentSnapshotId()
+	if err != nil || current == 0 {
+		return err
+	}
+	if from.IsZero() || to.IsZero() {
+		totalSupply, err := c.dal.GetTotalSupply()
+		if err != nil {
+			return err
+		}
+		err = c.updateCheckpoint(current, c.checkpoints(snapshotTotalSupplyKind, snapshotTotalSupplyId), totalSupply)
+		if err != nil {
+			return err
+		}
+	}
+	for _, account := range []common.Account{from, to} {
+		if account.IsZero() {
+			continue
+		}
+		balance, err := c.dal.GetBalance(account)
+		if err != nil {
+			return err
+		}
+		err = c.updateCheckpoint(current, c.checkpoints(snapshotBalanceKind, account.ToString()), balance)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *ERC20Contract) updateCheckpoint(current uint64, s checkpoints, value *common.SafeUint256) error {
+	last, _, ok, err := s.latest()
+	if err != nil {
+		return err
+	}
+	if ok && last >= current {
+		return nil
+	}
+	return s.push(current, value)
+}
@@ -0,0 +1,89 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erc20
+
+import (
+	"testing"
+
+	"github.com/studyzy/openzeppelin-go/mock"
+)
+
+func TestSnapshot(t *testing.T) {
+	chain, token := newToken(t, Option{Minable: true})
+	mint(t, chain, token, alice, 100)
+	mustFail(t, chain, alice, func() error {
+		_, err := token.Snapshot()
+		return err
+	}, "is missing role")
+	mustInvoke(t, chain, admin, func() error {
+		id, err := token.Snapshot()
+		if id != 1 {
+			t.Fatalf("snapshot id = %d, want 1", id)
+		}
+		return err
+	})
+	mustInvoke(t, chain, alice, func() error {
+		_, err := token.Transfer(bob, amount(30))
+		return err
+	})
+	mint(t, chain, token, bob, 20)
+	mustInvoke(t, chain, admin, func() error {
+		_, err := token.Snapshot()
+		return err
+	})
+	mustInvoke(t, chain, bob, func() error {
+		_, err := token.Transfer(alice, amount(10))
+		return err
+	})
+	if id, _ := token.GetCurrentSnapshotId(); id != 2 {
+		t.Fatalf("current snapshot id = %d, want 2", id)
+	}
+
+	for _, c := range []struct {
+		account mock.Account
+		id      uint64
+		want    uint64
+	}{
+		{alice, 1, 100}, {bob, 1, 0},
+		{alice, 2, 70}, {bob, 2, 50},
+	} {
+		balance, err := token.BalanceOfAt(c.account, c.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !balance.Equal(amount(c.want)) {
+			t.Fatalf("balance of %s at %d = %s, want %d", c.account, c.id, balance.ToString(), c.want)
+		}
+	}
+	for id, want := range map[uint64]uint64{1: 100, 2: 120} {
+		supply, err := token.TotalSupplyAt(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !supply.Equal(amount(want)) {
+			t.Fatalf("total supply at %d = %s, want %d", id, supply.ToString(), want)
+		}
+	}
+	//快照之后的变化不影响快照中的值
+	requireBalance(t, token, alice, 80)
+	requireBalance(t, token, bob, 40)
+
+	if _, err := token.BalanceOfAt(alice, 0); err == nil || err.Error() != "ERC20Snapshot: id is 0" {
+		t.Fatalf("got error %v, want id is 0", err)
+	}
+	if _, err := token.TotalSupplyAt(3); err == nil || err.Error() != "ERC20Snapshot: nonexistent id" {
+		t.Fatalf("got error %v, want nonexistent id", err)
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erc20
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/studyzy/openzeppelin-go/common"
+)
+
+const (
+	votesKind            = "dv"
+	votesTotalSupplyKind = "vt"
+	votesTotalSupplyId   = "total"
+)
+
+/**
+ * @dev Returns the current amount of votes that `account` has.
+ */
+func (c *ERC20Contract) GetVotes(account common.Account) (*common.SafeUint256, error) {
+	_, value, _, err := c.checkpoints(votesKind, account.ToString()).latest()
+	return value, err
+}
+
+/**
+ * @dev Retrieve the number of votes for `account` at the end of `timepoint`,
+ * measured in the unit of {Clock}.
+ *
+ * Requirements:
+ *
+ * - `timepoint` must be in the past
+ */
+func (c *ERC20Contract) GetPastVotes(account common.Account, timepoint uint64) (*common.SafeUint256, error) {
+	if err := c.requireMined(timepoint); err != nil {
+		return nil, err
+	}
+	return c.checkpoints(votesKind, account.ToString()).upperLookup(timepoint)
+}
+
+/**
+ * @dev Retrieve the `totalSupply` at the end of `timepoint`, measured in the unit of {Clock}.
+ * Note, this value is the sum of all balances. It is NOT the sum of all the delegated votes!
+ *
+ * Requirements:
+ *
+ * - `timepoint` must be in the past
+ */
+func (c *ERC20Contract) GetPastTotalSupply(timepoint uint64) (*common.SafeUint256, error) {
+	if err := c.requireMined(timepoint); err != nil {
+		return nil, err
+	}
+	return c.checkpoints(votesTotalSupplyKind, votesTotalSupplyId).upperLookup(timepoint)
+}
+
+/**
+ * @dev Clock used for flagging checkpoints (ERC-6372). The SDK decides the unit
+ * through its ClockMode: the block height on chains that expose it, or the
+ * transaction timestamp in seconds, e.g. in Fabric chaincode where the block
+ * height is not available during endorsement.
+ */
+func (c *ERC20Contract) Clock() (uint64, error) {
+	return common.Clock(c.sdk)
+}
+
+/**
+ * @dev Machine-readable description of the clock as specified in ERC-6372.
+ */
+func (c *ERC20Contract) ClockMode() (string, error) {
+	return c.sdk.ClockMode(), nil
+}
+
+/**
+ * @dev Get the address `account` is currently delegating to.
+ */
+func (c *ERC20Contract) Delegates(account common.Account) (common.Account, error) {
+	return c.dal.GetDelegate(account)
+}
+
+/**
+ * @dev Delegate votes from the sender to `delegatee`.
+ */
+func (c *ERC20Contract) Delegate(delegatee common.Account) error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		sender, err := c.sdk.GetTxSender()
+		if err != nil {
+			return fmt.Errorf("Get sender address failed, err:%s", err)
+		}
+		return c.baseDelegate(sender, delegatee)
+	})
+}
+
+// requireMined 只能查询已经过去的时间点，当前时间点的值还可能变化
+func (c *ERC20Contract) requireMined(timepoint uint64) error {
+	now, err := c.Clock()
+	if err != nil {
+		return err
+	}
+	return common.Require(timepoint < now, "ERC20Votes: future lookup")
+}
+
+/**
+ * @dev Change delegation for `delegator` to `delegatee`.
+ *
+ * Emits events {DelegateChanged} and {DelegateVotesChanged}.
+ */
+func (c *ERC20Contract) baseDelegate(delegator, delegatee common.Account) error {
+	currentDelegate, err := c.dal.GetDelegate(delegator)
+	if err != nil {
+		return err
+	}
+	balance, err := c.dal.GetBalance(delegator)
+	if err != nil {
+		return err
+	}
+	if err = c.dal.SetDelegate(delegator, delegatee); err != nil {
+		return err
+	}
+	c.sdk.EmitEvent("delegateChanged", delegator.ToString(), currentDelegate.ToString(), delegatee.ToString())
+	return c.moveVotingPower(currentDelegate, delegatee, balance)
+}
+
+// updateVotes 在余额变化之后调用，把投票权从from的受托人转移到to的受托人，
+// 铸币和销毁时同时记录总量的检查点
+func (c *ERC20Contract) updateVotes(from, to common.Account, amount *common.SafeUint256) error {
+	if from.IsZero() || to.IsZero() {
+		totalSupply, err := c.dal.GetTotalSupply()
+		if err != nil {
+			return err
+		}
+		if err = c.writeVotesCheckpoint(c.checkpoints(votesTotalSupplyKind, votesTotalSupplyId), totalSupply); err != nil {
+			return err
+		}
+	}
+	fromDelegate, err := c.dal.GetDelegate(from)
+	if err != nil {
+		return err
+	}
+	toDelegate, err := c.dal.GetDelegate(to)
+	if err != nil {
+		return err
+	}
+	return c.moveVotingPower(fromDelegate, toDelegate, amount)
+}
+
+func (c *ERC20Contract) moveVotingPower(src, dst common.Account, amount *common.SafeUint256) error {
+	if src.Equal(dst) || amount.Equal(common.SafeUintZero) {
+		return nil
+	}
+	if !src.IsZero() {
+		s := c.checkpoints(votesKind, src.ToString())
+		_, oldWeight, _, err := s.latest()
+		if err != nil {
+			return err
+		}
+		//SafeSub会修改被减数，所以先记下原来的值
+		old := oldWeight.ToString()
+		newWeight, ok := common.SafeSub(oldWeight, amount)
+		if !ok {
+			return errors.New("ERC20Votes: votes underflow")
+		}
+		if err = c.writeVotesCheckpoint(s, newWeight); err != nil {
+			return err
+		}
+		c.sdk.EmitEvent("delegateVotesChanged", src.ToString(), old, newWeight.ToString())
+	}
+	if !dst.IsZero() {
+		s := c.checkpoints(votesKind, dst.ToString())
+		_, oldWeight, _, err := s.latest()
+		if err != nil {
+			return err
+		}
+		newWeight, ok := common.SafeAdd(oldWeight, amount)
+		if !ok {
+			return errors.New("ERC20Votes: votes overflow")
+		}
+		if err = c.writeVotesCheckpoint(s, newWeight); err != nil {
+			return err
+		}
+		c.sdk.EmitEvent("delegateVotesChanged", dst.ToString(), oldWeight.ToString(), newWeight.ToString())
+	}
+	return nil
+}
+
+// writeVotesCheckpoint 以Clock返回的当前时间点记录检查点，同一时间点内多次变化只保留最后的值
+func (c *ERC20Contract) writeVotesCheckpoint(s checkpoints, value *common.SafeUint256) error {
+	now, err := c.Clock()
+	if err != nil {
+		return err
+	}
+	return s.push(now, value)
+}
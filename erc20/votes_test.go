@@ -0,0 +1,130 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erc20
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/studyzy/openzeppelin-go/common"
+	"github.com/studyzy/openzeppelin-go/mock"
+)
+
+func requireVotes(t *testing.T, got *common.SafeUint256, err error, want uint64) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(amount(want)) {
+		t.Fatalf("votes = %s, want %d", got.ToString(), want)
+	}
+}
+
+func TestVotesCheckpoints(t *testing.T) {
+	chain, token := newToken(t, Option{Minable: true})
+	//height 1
+	mint(t, chain, token, alice, 100)
+	mustInvoke(t, chain, alice, func() error { return token.Delegate(alice) })
+	chain.Mine(1, 5)
+	//height 2
+	mustInvoke(t, chain, alice, func() error { return token.Delegate(bob) })
+	mustInvoke(t, chain, alice, func() error {
+		_, err := token.Transfer(bob, amount(40))
+		return err
+	})
+	mustInvoke(t, chain, bob, func() error { return token.Delegate(bob) })
+	chain.Mine(1, 5)
+	//height 3
+	votes, err := token.GetVotes(bob)
+	requireVotes(t, votes, err, 100)
+	votes, err = token.GetPastVotes(alice, 1)
+	requireVotes(t, votes, err, 100)
+	votes, err = token.GetPastVotes(bob, 1)
+	requireVotes(t, votes, err, 0)
+	votes, err = token.GetPastVotes(bob, 2)
+	requireVotes(t, votes, err, 100)
+	votes, err = token.GetPastVotes(alice, 2)
+	requireVotes(t, votes, err, 0)
+	supply, err := token.GetPastTotalSupply(2)
+	requireVotes(t, supply, err, 100)
+	if _, err = token.GetPastVotes(alice, 3); err == nil {
+		t.Fatal("lookup of the current block should fail")
+	}
+	if delegate, _ := token.Delegates(alice); !delegate.Equal(bob) {
+		t.Fatalf("alice delegates to %s, want bob", delegate.ToString())
+	}
+	if len(chain.EventsByTopic("delegateChanged")) != 3 {
+		t.Fatal("missing delegateChanged events")
+	}
+}
+
+func TestInitialSupplyCheckpoint(t *testing.T) {
+	chain := mock.NewChain()
+	token := NewERC20Contract(Option{}, "Token", "TKN", chain.Deploy("token", nil))
+	mustInvoke(t, chain, admin, func() error {
+		return token.InitERC20("", "", 18, amount(1000), nil, admin)
+	})
+	chain.Mine(1, 5)
+	supply, err := token.GetPastTotalSupply(1)
+	requireVotes(t, supply, err, 1000)
+	supply, err = token.GetPastTotalSupply(0)
+	requireVotes(t, supply, err, 0)
+}
+
+// noHeightSDK 使用区块高度时钟，但是获取区块高度失败
+type noHeightSDK struct {
+	*mock.SDK
+}
+
+func (s noHeightSDK) GetBlockHeight() (uint64, error) {
+	return 0, errors.New("block height is not available")
+}
+
+func TestVotesTimestampClock(t *testing.T) {
+	chain := mock.NewChain()
+	chain.SetClockMode(common.ClockModeTimestamp)
+	token := NewERC20Contract(Option{Minable: true}, "Token", "TKN", chain.Deploy("token", nil))
+	mustInvoke(t, chain, admin, func() error {
+		return token.InitERC20("", "", 18, amount(0), nil, admin)
+	})
+	if mode, _ := token.ClockMode(); mode != "mode=timestamp" {
+		t.Fatalf("clock mode = %s", mode)
+	}
+	mustInvoke(t, chain, alice, func() error { return token.Delegate(alice) })
+	mint(t, chain, token, alice, 100)
+	minted, _ := token.Clock()
+	chain.Mine(1, 10)
+	votes, err := token.GetPastVotes(alice, minted)
+	requireVotes(t, votes, err, 100)
+	votes, err = token.GetPastVotes(alice, minted-1)
+	requireVotes(t, votes, err, 0)
+	supply, err := token.GetPastTotalSupply(minted)
+	requireVotes(t, supply, err, 100)
+}
+
+func TestVotesClockErrorIsNotMasked(t *testing.T) {
+	chain := mock.NewChain()
+	token := NewERC20Contract(Option{Minable: true}, "Token", "TKN", noHeightSDK{chain.Deploy("token", nil)})
+	if mode, _ := token.ClockMode(); mode != "mode=blocknumber&from=default" {
+		t.Fatalf("clock mode = %s", mode)
+	}
+	//区块高度时钟出错时不能悄悄换成时间戳，否则检查点会混用两种单位
+	if _, err := token.Clock(); err == nil || err.Error() != "block height is not available" {
+		t.Fatalf("clock err = %v", err)
+	}
+	if _, err := token.GetPastVotes(alice, 0); err == nil {
+		t.Fatal("GetPastVotes should fail when the clock fails")
+	}
+}
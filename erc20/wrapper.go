@@ -0,0 +1,164 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erc20
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/studyzy/openzeppelin-go/access"
+	"github.com/studyzy/openzeppelin-go/common"
+)
+
+// InitERC20Wrapper 把本合约初始化为underlying的包装代币，只能初始化一次。
+// 合约无法通过SDK获得自己的地址，所以需要同时传入本合约的地址self，用来接收存入的底层代币
+func (c *ERC20Contract) InitERC20Wrapper(underlying, self common.Account) error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		if err := c.OnlyRole(access.AdminRole); err != nil {
+			return err
+		}
+		if err := checkAccount(underlying, self); err != nil {
+			return err
+		}
+		if err := common.Require(!underlying.Equal(self), "ERC20Wrapper: cannot self wrap"); err != nil {
+			return err
+		}
+		current, err := c.dal.GetUnderlying()
+		if err != nil {
+			return err
+		}
+		if err = common.Require(current.IsZero(), "ERC20Wrapper: already initialized"); err != nil {
+			return err
+		}
+		if err = c.dal.SetUnderlying(underlying); err != nil {
+			return err
+		}
+		return c.setSelf(self)
+	})
+}
+
+/**
+ * @dev Returns the address of the underlying ERC-20 token that is being wrapped.
+ */
+func (c *ERC20Contract) Underlying() (common.Account, error) {
+	return c.dal.GetUnderlying()
+}
+
+/**
+ * @dev Allow a user to deposit underlying tokens and mint the corresponding number of wrapped tokens.
+ *
+ * The caller must have approved this contract to spend `amount` of its underlying tokens.
+ */
+func (c *ERC20Contract) DepositFor(account common.Account, amount *common.SafeUint256) (bool, error) {
+	err := common.Atomic(c.sdk, c.SetSDK, func() error {
+		sender, err := c.sdk.GetTxSender()
+		if err != nil {
+			return fmt.Errorf("Get sender address failed, err:%s", err)
+		}
+		underlying, self, err := c.wrapperAccounts()
+		if err != nil {
+			return err
+		}
+		if err = common.Require(!sender.Equal(self), "ERC20Wrapper: wrapper can't deposit"); err != nil {
+			return err
+		}
+		//先把底层代币转入本合约，再1:1铸造包装代币
+		if err = NewTokenCaller(c.sdk, underlying).TransferFrom(sender, self, amount); err != nil {
+			return err
+		}
+		return c.baseMint(account, amount)
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+/**
+ * @dev Allow a user to burn a number of wrapped tokens and withdraw the corresponding number of underlying tokens.
+ */
+func (c *ERC20Contract) WithdrawTo(account common.Account, amount *common.SafeUint256) (bool, error) {
+	err := common.Atomic(c.sdk, c.SetSDK, func() error {
+		sender, err := c.sdk.GetTxSender()
+		if err != nil {
+			return fmt.Errorf("Get sender address failed, err:%s", err)
+		}
+		underlying, _, err := c.wrapperAccounts()
+		if err != nil {
+			return err
+		}
+		if err = c.baseBurn(sender, amount); err != nil {
+			return err
+		}
+		return NewTokenCaller(c.sdk, underlying).Transfer(account, amount)
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+/**
+ * @dev Mint wrapped token to cover any underlyingTokens that would have been transferred by mistake.
+ * Returns the amount of wrapped tokens minted to `account`.
+ *
+ * Requirements:
+ *
+ * - the caller must have the `AdminRole`.
+ */
+func (c *ERC20Contract) Recover(account common.Account) (*common.SafeUint256, error) {
+	value := common.NewSafeUint256(0)
+	err := common.Atomic(c.sdk, c.SetSDK, func() error {
+		if err := c.OnlyRole(access.AdminRole); err != nil {
+			return err
+		}
+		underlying, self, err := c.wrapperAccounts()
+		if err != nil {
+			return err
+		}
+		balance, err := NewTokenCaller(c.sdk, underlying).BalanceOf(self)
+		if err != nil {
+			return err
+		}
+		totalSupply, err := c.dal.GetTotalSupply()
+		if err != nil {
+			return err
+		}
+		//底层代币余额超出包装代币总量的部分就是误转入的代币
+		excess, ok := common.SafeSub(balance, totalSupply)
+		if !ok || excess.Equal(common.SafeUintZero) {
+			return nil
+		}
+		value = excess
+		return c.baseMint(account, value)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func (c *ERC20Contract) wrapperAccounts() (underlying, self common.Account, err error) {
+	if underlying, err = c.dal.GetUnderlying(); err != nil {
+		return nil, nil, err
+	}
+	if underlying.IsZero() {
+		return nil, nil, errors.New("ERC20Wrapper: not initialized")
+	}
+	if self, err = c.dal.GetSelf(); err != nil {
+		return nil, nil, err
+	}
+	return underlying, self, nil
+}
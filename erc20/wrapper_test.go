@@ -0,0 +1,154 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erc20_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/studyzy/openzeppelin-go/common"
+	"github.com/studyzy/openzeppelin-go/erc20"
+	"github.com/studyzy/openzeppelin-go/erc20/erc20mock"
+	"github.com/studyzy/openzeppelin-go/mock"
+)
+
+// 包装代币需要通过CallContract调用真实的底层代币合约，erc20mock依赖erc20，所以放在外部测试包中
+
+var (
+	admin   = mock.NewAccount("admin")
+	alice   = mock.NewAccount("alice")
+	bob     = mock.NewAccount("bob")
+	wrapper = mock.NewAccount("wrapper")
+)
+
+func mustInvoke(t *testing.T, chain *mock.Chain, sender mock.Account, fn func() error) {
+	t.Helper()
+	if err := chain.Invoke(sender, fn); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mustFail(t *testing.T, chain *mock.Chain, sender mock.Account, fn func() error, msg string) {
+	t.Helper()
+	err := chain.Invoke(sender, fn)
+	if err == nil {
+		t.Fatalf("expected error containing %q", msg)
+	}
+	if !strings.Contains(err.Error(), msg) {
+		t.Fatalf("got error %q, want %q", err, msg)
+	}
+}
+
+func requireBalance(t *testing.T, token *erc20.ERC20Contract, account mock.Account, want uint64) {
+	t.Helper()
+	balance, err := token.BalanceOf(account)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !balance.Equal(common.NewSafeUint256(want)) {
+		t.Fatalf("balance of %s = %s, want %d", account, balance.ToString(), want)
+	}
+}
+
+// newWrapper 部署底层代币和包装代币，alice持有100个底层代币
+func newWrapper(t *testing.T) (*mock.Chain, *erc20.ERC20Contract, *erc20.ERC20Contract) {
+	t.Helper()
+	chain := mock.NewChain()
+	underlying, err := erc20mock.Deploy(chain, "underlying", erc20.Option{Minable: true}, admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mustInvoke(t, chain, admin, func() error {
+		_, err := underlying.Mint(alice, common.NewSafeUint256(100))
+		return err
+	})
+	wrapped := erc20.NewERC20Contract(erc20.Option{}, "Wrapped", "WTKN", chain.Deploy(wrapper.ToString(), nil))
+	mustInvoke(t, chain, admin, func() error {
+		return wrapped.InitERC20("", "", 18, common.NewSafeUint256(0), nil, admin)
+	})
+	init := func() error {
+		return wrapped.InitERC20Wrapper(mock.NewAccount("underlying"), wrapper)
+	}
+	mustFail(t, chain, alice, init, "is missing role")
+	mustFail(t, chain, admin, func() error {
+		return wrapped.InitERC20Wrapper(wrapper, wrapper)
+	}, "ERC20Wrapper: cannot self wrap")
+	mustInvoke(t, chain, admin, init)
+	mustFail(t, chain, admin, init, "ERC20Wrapper: already initialized")
+	return chain, underlying, wrapped
+}
+
+func TestWrapperDepositAndWithdraw(t *testing.T) {
+	chain, underlying, wrapped := newWrapper(t)
+	mustInvoke(t, chain, alice, func() error {
+		_, err := underlying.Approve(wrapper, common.NewSafeUint256(60))
+		return err
+	})
+	mustInvoke(t, chain, alice, func() error {
+		_, err := wrapped.DepositFor(alice, common.NewSafeUint256(60))
+		return err
+	})
+	requireBalance(t, wrapped, alice, 60)
+	requireBalance(t, underlying, wrapper, 60)
+	//授权用完之后底层代币转账失败，不会铸造包装代币
+	mustFail(t, chain, alice, func() error {
+		_, err := wrapped.DepositFor(alice, common.NewSafeUint256(10))
+		return err
+	}, "ERC20: insufficient allowance")
+	requireBalance(t, wrapped, alice, 60)
+
+	mustInvoke(t, chain, alice, func() error {
+		_, err := wrapped.WithdrawTo(bob, common.NewSafeUint256(20))
+		return err
+	})
+	requireBalance(t, wrapped, alice, 40)
+	requireBalance(t, underlying, bob, 20)
+	requireBalance(t, underlying, wrapper, 40)
+	mustFail(t, chain, alice, func() error {
+		_, err := wrapped.WithdrawTo(bob, common.NewSafeUint256(41))
+		return err
+	}, "ERC20: burn amount exceeds balance")
+	requireBalance(t, underlying, bob, 20)
+}
+
+func TestWrapperRecover(t *testing.T) {
+	chain, underlying, wrapped := newWrapper(t)
+	//直接转给包装合约的底层代币没有对应的包装代币
+	mustInvoke(t, chain, alice, func() error {
+		_, err := underlying.Transfer(wrapper, common.NewSafeUint256(15))
+		return err
+	})
+	mustFail(t, chain, alice, func() error {
+		_, err := wrapped.Recover(alice)
+		return err
+	}, "is missing role")
+	mustInvoke(t, chain, admin, func() error {
+		recovered, err := wrapped.Recover(admin)
+		if err == nil && !recovered.Equal(common.NewSafeUint256(15)) {
+			t.Fatalf("recovered = %s, want 15", recovered.ToString())
+		}
+		return err
+	})
+	requireBalance(t, wrapped, admin, 15)
+	//没有多余的底层代币时不再铸造
+	mustInvoke(t, chain, admin, func() error {
+		recovered, err := wrapped.Recover(admin)
+		if err == nil && !recovered.Equal(common.SafeUintZero) {
+			t.Fatalf("recovered = %s, want 0", recovered.ToString())
+		}
+		return err
+	})
+	requireBalance(t, wrapped, admin, 15)
+}
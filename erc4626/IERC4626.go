@@ -0,0 +1,40 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erc4626
+
+import (
+	"github.com/studyzy/openzeppelin-go/common"
+	"github.com/studyzy/openzeppelin-go/erc20"
+)
+
+type IERC4626 interface {
+	erc20.IERC20
+	Asset() (common.Account, error)
+	TotalAssets() (*common.SafeUint256, error)
+	ConvertToShares(assets *common.SafeUint256) (*common.SafeUint256, error)
+	ConvertToAssets(shares *common.SafeUint256) (*common.SafeUint256, error)
+	MaxDeposit(receiver common.Account) (*common.SafeUint256, error)
+	PreviewDeposit(assets *common.SafeUint256) (*common.SafeUint256, error)
+	Deposit(assets *common.SafeUint256, receiver common.Account) (*common.SafeUint256, error)
+	MaxMint(receiver common.Account) (*common.SafeUint256, error)
+	PreviewMint(shares *common.SafeUint256) (*common.SafeUint256, error)
+	Mint(shares *common.SafeUint256, receiver common.Account) (*common.SafeUint256, error)
+	MaxWithdraw(owner common.Account) (*common.SafeUint256, error)
+	PreviewWithdraw(assets *common.SafeUint256) (*common.SafeUint256, error)
+	Withdraw(assets *common.SafeUint256, receiver, owner common.Account) (*common.SafeUint256, error)
+	MaxRedeem(owner common.Account) (*common.SafeUint256, error)
+	PreviewRedeem(shares *common.SafeUint256) (*common.SafeUint256, error)
+	Redeem(shares *common.SafeUint256, receiver, owner common.Account) (*common.SafeUint256, error)
+}
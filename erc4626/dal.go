@@ -0,0 +1,59 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erc4626
+
+import (
+	"github.com/studyzy/openzeppelin-go/common"
+)
+
+const (
+	assetKey = "asset"
+	vaultKey = "vault"
+)
+
+type ERC4626ContractDAL struct {
+	sdk common.StateOperator
+}
+
+func NewERC4626ContractDAL(sdk common.StateOperator) *ERC4626ContractDAL {
+	return &ERC4626ContractDAL{sdk: sdk}
+}
+
+// GetAsset 获得底层资产代币的合约地址，没有设置时返回零地址
+func (c *ERC4626ContractDAL) GetAsset() (common.Account, error) {
+	return c.getAccount(assetKey)
+}
+func (c *ERC4626ContractDAL) SetAsset(asset common.Account) error {
+	return c.sdk.PutState(assetKey, []byte(asset.ToString()))
+}
+
+// GetVault 获得初始化时记录的本合约地址
+func (c *ERC4626ContractDAL) GetVault() (common.Account, error) {
+	return c.getAccount(vaultKey)
+}
+func (c *ERC4626ContractDAL) SetVault(vault common.Account) error {
+	return c.sdk.PutState(vaultKey, []byte(vault.ToString()))
+}
+
+func (c *ERC4626ContractDAL) getAccount(key string) (common.Account, error) {
+	b, err := c.sdk.GetState(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return c.sdk.NewZeroAccount(), nil
+	}
+	return c.sdk.NewAccountFromString(string(b))
+}
@@ -0,0 +1,389 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+ERC4626 Tokenized Vault Standard:
+https://eips.ethereum.org/EIPS/eip-4626
+*/
+
+package erc4626
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/studyzy/openzeppelin-go/common"
+	"github.com/studyzy/openzeppelin-go/erc20"
+)
+
+var _ IERC4626 = (*ERC4626Contract)(nil)
+
+// ERC4626Contract 资产金库合约，份额本身是一个ERC20代币，底层资产是另一个通过CallContract调用的ERC20合约
+type ERC4626Contract struct {
+	*erc20.ERC20Contract
+	dal *ERC4626ContractDAL
+	sdk common.ContractSDK
+}
+
+// NewERC4626Contract ERC4626Contract
+// @param option 份额代币的选项
+// @param name
+// @param symbol
+// @return *ERC4626Contract
+func NewERC4626Contract(option erc20.Option, name, symbol string, sdk common.ContractSDK) *ERC4626Contract {
+	return &ERC4626Contract{
+		ERC20Contract: erc20.NewERC20Contract(option, name, symbol, sdk),
+		dal:           NewERC4626ContractDAL(sdk),
+		sdk:           sdk,
+	}
+}
+
+// InitERC4626 初始化份额代币和底层资产。合约无法通过SDK获得自己的地址，
+// 所以需要传入本合约的地址vault，用来持有存入的资产
+func (c *ERC4626Contract) InitERC4626(name, symbol string, decimals uint8, asset, vault, admin common.Account) error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		if err := c.InitERC20(name, symbol, decimals, common.NewSafeUint256(0), nil, admin); err != nil {
+			return err
+		}
+		if asset.IsZero() || vault.IsZero() {
+			return errors.New("ERC4626: the zero address")
+		}
+		if err := c.dal.SetAsset(asset); err != nil {
+			return err
+		}
+		return c.dal.SetVault(vault)
+	})
+}
+
+func (c *ERC4626Contract) SetSDK(sdk common.ContractSDK) {
+	c.ERC20Contract.SetSDK(sdk)
+	c.sdk = sdk
+	c.dal = NewERC4626ContractDAL(sdk)
+}
+
+/**
+ * @dev See {IERC4626-asset}.
+ */
+func (c *ERC4626Contract) Asset() (common.Account, error) {
+	return c.dal.GetAsset()
+}
+
+/**
+ * @dev See {IERC4626-totalAssets}.
+ */
+func (c *ERC4626Contract) TotalAssets() (*common.SafeUint256, error) {
+	asset, err := c.asset()
+	if err != nil {
+		return nil, err
+	}
+	vault, err := c.dal.GetVault()
+	if err != nil {
+		return nil, err
+	}
+	return asset.BalanceOf(vault)
+}
+
+/**
+ * @dev See {IERC4626-convertToShares}.
+ */
+func (c *ERC4626Contract) ConvertToShares(assets *common.SafeUint256) (*common.SafeUint256, error) {
+	return c.convertToShares(assets, common.RoundDown)
+}
+
+/**
+ * @dev See {IERC4626-convertToAssets}.
+ */
+func (c *ERC4626Contract) ConvertToAssets(shares *common.SafeUint256) (*common.SafeUint256, error) {
+	return c.convertToAssets(shares, common.RoundDown)
+}
+
+/**
+ * @dev See {IERC4626-maxDeposit}.
+ */
+func (c *ERC4626Contract) MaxDeposit(receiver common.Account) (*common.SafeUint256, error) {
+	//SafeAdd返回新的对象，避免调用方修改全局的MaxSafeUint256
+	maxAssets, _ := common.SafeAdd(common.MaxSafeUint256, common.SafeUintZero)
+	return maxAssets, nil
+}
+
+/**
+ * @dev See {IERC4626-maxMint}.
+ */
+func (c *ERC4626Contract) MaxMint(receiver common.Account) (*common.SafeUint256, error) {
+	return c.MaxDeposit(receiver)
+}
+
+/**
+ * @dev See {IERC4626-maxWithdraw}.
+ */
+func (c *ERC4626Contract) MaxWithdraw(owner common.Account) (*common.SafeUint256, error) {
+	balance, err := c.BalanceOf(owner)
+	if err != nil {
+		return nil, err
+	}
+	return c.convertToAssets(balance, common.RoundDown)
+}
+
+/**
+ * @dev See {IERC4626-maxRedeem}.
+ */
+func (c *ERC4626Contract) MaxRedeem(owner common.Account) (*common.SafeUint256, error) {
+	return c.BalanceOf(owner)
+}
+
+/**
+ * @dev See {IERC4626-previewDeposit}.
+ */
+func (c *ERC4626Contract) PreviewDeposit(assets *common.SafeUint256) (*common.SafeUint256, error) {
+	return c.convertToShares(assets, common.RoundDown)
+}
+
+/**
+ * @dev See {IERC4626-previewMint}.
+ */
+func (c *ERC4626Contract) PreviewMint(shares *common.SafeUint256) (*common.SafeUint256, error) {
+	return c.convertToAssets(shares, common.RoundUp)
+}
+
+/**
+ * @dev See {IERC4626-previewWithdraw}.
+ */
+func (c *ERC4626Contract) PreviewWithdraw(assets *common.SafeUint256) (*common.SafeUint256, error) {
+	return c.convertToShares(assets, common.RoundUp)
+}
+
+/**
+ * @dev See {IERC4626-previewRedeem}.
+ */
+func (c *ERC4626Contract) PreviewRedeem(shares *common.SafeUint256) (*common.SafeUint256, error) {
+	return c.convertToAssets(shares, common.RoundDown)
+}
+
+/**
+ * @dev See {IERC4626-deposit}.
+ */
+func (c *ERC4626Contract) Deposit(assets *common.SafeUint256, receiver common.Account) (*common.SafeUint256, error) {
+	var shares *common.SafeUint256
+	err := common.Atomic(c.sdk, c.SetSDK, func() error {
+		maxAssets, err := c.MaxDeposit(receiver)
+		if err != nil {
+			return err
+		}
+		if err = common.Require(maxAssets.GTE(assets), "ERC4626: deposit more than max"); err != nil {
+			return err
+		}
+		if shares, err = c.PreviewDeposit(assets); err != nil {
+			return err
+		}
+		return c.baseDeposit(receiver, assets, shares)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+/**
+ * @dev See {IERC4626-mint}.
+ *
+ * As opposed to {deposit}, minting is allowed even if the vault is in a state where the price of a share is zero.
+ * In this case, the shares will be minted without requiring any assets to be deposited.
+ */
+func (c *ERC4626Contract) Mint(shares *common.SafeUint256, receiver common.Account) (*common.SafeUint256, error) {
+	var assets *common.SafeUint256
+	err := common.Atomic(c.sdk, c.SetSDK, func() error {
+		maxShares, err := c.MaxMint(receiver)
+		if err != nil {
+			return err
+		}
+		if err = common.Require(maxShares.GTE(shares), "ERC4626: mint more than max"); err != nil {
+			return err
+		}
+		if assets, err = c.PreviewMint(shares); err != nil {
+			return err
+		}
+		return c.baseDeposit(receiver, assets, shares)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return assets, nil
+}
+
+/**
+ * @dev See {IERC4626-withdraw}.
+ */
+func (c *ERC4626Contract) Withdraw(assets *common.SafeUint256, receiver, owner common.Account) (*common.SafeUint256, error) {
+	var shares *common.SafeUint256
+	err := common.Atomic(c.sdk, c.SetSDK, func() error {
+		maxAssets, err := c.MaxWithdraw(owner)
+		if err != nil {
+			return err
+		}
+		if err = common.Require(maxAssets.GTE(assets), "ERC4626: withdraw more than max"); err != nil {
+			return err
+		}
+		if shares, err = c.PreviewWithdraw(assets); err != nil {
+			return err
+		}
+		return c.baseWithdraw(receiver, owner, assets, shares)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+/**
+ * @dev See {IERC4626-redeem}.
+ */
+func (c *ERC4626Contract) Redeem(shares *common.SafeUint256, receiver, owner common.Account) (*common.SafeUint256, error) {
+	var assets *common.SafeUint256
+	err := common.Atomic(c.sdk, c.SetSDK, func() error {
+		maxShares, err := c.MaxRedeem(owner)
+		if err != nil {
+			return err
+		}
+		if err = common.Require(maxShares.GTE(shares), "ERC4626: redeem more than max"); err != nil {
+			return err
+		}
+		if assets, err = c.PreviewRedeem(shares); err != nil {
+			return err
+		}
+		return c.baseWithdraw(receiver, owner, assets, shares)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return assets, nil
+}
+
+/**
+ * @dev Internal conversion function (from assets to shares) with support for rounding direction.
+ * One virtual share and one virtual asset are added to make the donation (inflation) attack unprofitable.
+ */
+func (c *ERC4626Contract) convertToShares(assets *common.SafeUint256, rounding common.Rounding) (*common.SafeUint256, error) {
+	totalSupply, totalAssets, err := c.virtualTotals()
+	if err != nil {
+		return nil, err
+	}
+	shares, ok := common.MulDiv(assets, totalSupply, totalAssets, rounding)
+	if !ok {
+		return nil, errors.New("ERC4626: convert to shares overflow")
+	}
+	return shares, nil
+}
+
+/**
+ * @dev Internal conversion function (from shares to assets) with support for rounding direction.
+ */
+func (c *ERC4626Contract) convertToAssets(shares *common.SafeUint256, rounding common.Rounding) (*common.SafeUint256, error) {
+	totalSupply, totalAssets, err := c.virtualTotals()
+	if err != nil {
+		return nil, err
+	}
+	assets, ok := common.MulDiv(shares, totalAssets, totalSupply, rounding)
+	if !ok {
+		return nil, errors.New("ERC4626: convert to assets overflow")
+	}
+	return assets, nil
+}
+
+// virtualTotals 返回加上一个虚拟份额和一个虚拟资产之后的份额总量和资产总量
+func (c *ERC4626Contract) virtualTotals() (*common.SafeUint256, *common.SafeUint256, error) {
+	totalSupply, err := c.TotalSupply()
+	if err != nil {
+		return nil, nil, err
+	}
+	totalAssets, err := c.TotalAssets()
+	if err != nil {
+		return nil, nil, err
+	}
+	virtualSupply, ok := common.SafeAdd(totalSupply, common.SafeUintOne)
+	if !ok {
+		return nil, nil, errors.New("ERC4626: total supply overflow")
+	}
+	virtualAssets, ok := common.SafeAdd(totalAssets, common.SafeUintOne)
+	if !ok {
+		return nil, nil, errors.New("ERC4626: total assets overflow")
+	}
+	return virtualSupply, virtualAssets, nil
+}
+
+/**
+ * @dev Deposit/mint common workflow.
+ */
+func (c *ERC4626Contract) baseDeposit(receiver common.Account, assets, shares *common.SafeUint256) error {
+	caller, err := c.sdk.GetTxSender()
+	if err != nil {
+		return fmt.Errorf("Get sender address failed, err:%s", err)
+	}
+	asset, err := c.asset()
+	if err != nil {
+		return err
+	}
+	vault, err := c.dal.GetVault()
+	if err != nil {
+		return err
+	}
+	//先把资产转入金库再铸造份额，底层资产合约回调金库时看到的是转入后的状态
+	if err = asset.TransferFrom(caller, vault, assets); err != nil {
+		return err
+	}
+	if err = c.BaseMint(receiver, shares); err != nil {
+		return err
+	}
+	c.sdk.EmitEvent("deposit", caller.ToString(), receiver.ToString(), assets.ToString(), shares.ToString())
+	return nil
+}
+
+/**
+ * @dev Withdraw/redeem common workflow.
+ */
+func (c *ERC4626Contract) baseWithdraw(receiver, owner common.Account, assets, shares *common.SafeUint256) error {
+	caller, err := c.sdk.GetTxSender()
+	if err != nil {
+		return fmt.Errorf("Get sender address failed, err:%s", err)
+	}
+	if !caller.Equal(owner) {
+		if err = c.BaseSpendAllowance(owner, caller, shares); err != nil {
+			return err
+		}
+	}
+	//先销毁份额再转出资产
+	if err = c.BaseBurn(owner, shares); err != nil {
+		return err
+	}
+	asset, err := c.asset()
+	if err != nil {
+		return err
+	}
+	if err = asset.Transfer(receiver, assets); err != nil {
+		return err
+	}
+	c.sdk.EmitEvent("withdraw", caller.ToString(), receiver.ToString(), owner.ToString(), assets.ToString(),
+		shares.ToString())
+	return nil
+}
+
+func (c *ERC4626Contract) asset() (*erc20.TokenCaller, error) {
+	asset, err := c.dal.GetAsset()
+	if err != nil {
+		return nil, err
+	}
+	if asset.IsZero() {
+		return nil, errors.New("ERC4626: not initialized")
+	}
+	return erc20.NewTokenCaller(c.sdk, asset), nil
+}
@@ -0,0 +1,198 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erc4626
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/studyzy/openzeppelin-go/common"
+	"github.com/studyzy/openzeppelin-go/erc20"
+	"github.com/studyzy/openzeppelin-go/erc20/erc20mock"
+	"github.com/studyzy/openzeppelin-go/mock"
+)
+
+var (
+	admin = mock.NewAccount("admin")
+	alice = mock.NewAccount("alice")
+	bob   = mock.NewAccount("bob")
+	vault = mock.NewAccount("vault")
+)
+
+func amount(v uint64) *common.SafeUint256 {
+	return common.NewSafeUint256(v)
+}
+
+func mustInvoke(t *testing.T, chain *mock.Chain, sender mock.Account, fn func() error) {
+	t.Helper()
+	if err := chain.Invoke(sender, fn); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mustFail(t *testing.T, chain *mock.Chain, sender mock.Account, fn func() error, msg string) {
+	t.Helper()
+	err := chain.Invoke(sender, fn)
+	if err == nil {
+		t.Fatalf("expected error containing %q", msg)
+	}
+	if !strings.Contains(err.Error(), msg) {
+		t.Fatalf("got error %q, want %q", err, msg)
+	}
+}
+
+func requireAmount(t *testing.T, name string, got *common.SafeUint256, err error, want uint64) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(amount(want)) {
+		t.Fatalf("%s = %s, want %d", name, got.ToString(), want)
+	}
+}
+
+// newVault 部署底层资产和金库，alice和bob各持有1000个资产，alice授权金库使用她的全部资产
+func newVault(t *testing.T) (*mock.Chain, *erc20.ERC20Contract, *ERC4626Contract) {
+	t.Helper()
+	chain := mock.NewChain()
+	asset, err := erc20mock.Deploy(chain, "asset", erc20.Option{Minable: true}, admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, account := range []mock.Account{alice, bob} {
+		holder := account
+		mustInvoke(t, chain, admin, func() error {
+			_, err := asset.Mint(holder, amount(1000))
+			return err
+		})
+	}
+	mustInvoke(t, chain, alice, func() error {
+		_, err := asset.Approve(vault, common.MaxSafeUint256)
+		return err
+	})
+	shares := NewERC4626Contract(erc20.Option{}, "Vault", "vTKN", chain.Deploy(vault.ToString(), nil))
+	mustInvoke(t, chain, admin, func() error {
+		return shares.InitERC4626("", "", 18, mock.NewAccount("asset"), vault, admin)
+	})
+	return chain, asset, shares
+}
+
+func TestDepositAndRedeem(t *testing.T) {
+	chain, asset, shares := newVault(t)
+	mustInvoke(t, chain, alice, func() error {
+		minted, err := shares.Deposit(amount(100), alice)
+		requireAmount(t, "deposit shares", minted, err, 100)
+		return err
+	})
+	balance, err := shares.BalanceOf(alice)
+	requireAmount(t, "alice shares", balance, err, 100)
+	total, err := shares.TotalAssets()
+	requireAmount(t, "total assets", total, err, 100)
+	//没有授权的资产转入失败时不会铸造份额
+	mustFail(t, chain, bob, func() error {
+		_, err := shares.Deposit(amount(100), bob)
+		return err
+	}, "ERC20: insufficient allowance")
+	supply, err := shares.TotalSupply()
+	requireAmount(t, "total supply", supply, err, 100)
+
+	mustInvoke(t, chain, alice, func() error {
+		assets, err := shares.Redeem(amount(100), alice, alice)
+		requireAmount(t, "redeemed assets", assets, err, 100)
+		return err
+	})
+	balance, err = asset.BalanceOf(alice)
+	requireAmount(t, "alice assets", balance, err, 1000)
+}
+
+// TestRounding 有人直接向金库转入资产后每个份额不再对应整数个资产，
+// 各种换算都要向有利于金库的方向取整
+func TestRounding(t *testing.T) {
+	chain, asset, shares := newVault(t)
+	mustInvoke(t, chain, alice, func() error {
+		_, err := shares.Deposit(amount(100), alice)
+		return err
+	})
+	mustInvoke(t, chain, bob, func() error {
+		_, err := asset.Transfer(vault, amount(50))
+		return err
+	})
+	//份额总量100+1，资产总量150+1
+	preview, err := shares.PreviewDeposit(amount(10))
+	requireAmount(t, "preview deposit", preview, err, 6)
+	preview, err = shares.PreviewMint(amount(6))
+	requireAmount(t, "preview mint", preview, err, 9)
+	preview, err = shares.PreviewWithdraw(amount(10))
+	requireAmount(t, "preview withdraw", preview, err, 7)
+	preview, err = shares.PreviewRedeem(amount(7))
+	requireAmount(t, "preview redeem", preview, err, 10)
+
+	mustInvoke(t, chain, alice, func() error {
+		assets, err := shares.Mint(amount(6), alice)
+		requireAmount(t, "mint assets", assets, err, 9)
+		return err
+	})
+	//份额总量106+1，资产总量159+1
+	mustInvoke(t, chain, alice, func() error {
+		burned, err := shares.Withdraw(amount(10), alice, alice)
+		requireAmount(t, "withdraw shares", burned, err, 7)
+		return err
+	})
+	balance, err := shares.BalanceOf(alice)
+	requireAmount(t, "alice shares", balance, err, 99)
+	//份额总量99+1，资产总量149+1，赎回全部份额时零头留在金库中
+	mustInvoke(t, chain, alice, func() error {
+		assets, err := shares.Redeem(amount(99), alice, alice)
+		requireAmount(t, "redeemed assets", assets, err, 148)
+		return err
+	})
+	balance, err = asset.BalanceOf(alice)
+	requireAmount(t, "alice assets", balance, err, 1049)
+	total, err := shares.TotalAssets()
+	requireAmount(t, "total assets", total, err, 1)
+}
+
+func TestWithdrawRequirements(t *testing.T) {
+	chain, _, shares := newVault(t)
+	mustInvoke(t, chain, alice, func() error {
+		_, err := shares.Deposit(amount(100), alice)
+		return err
+	})
+	mustFail(t, chain, alice, func() error {
+		_, err := shares.Withdraw(amount(101), alice, alice)
+		return err
+	}, "ERC4626: withdraw more than max")
+	mustFail(t, chain, alice, func() error {
+		_, err := shares.Redeem(amount(101), alice, alice)
+		return err
+	}, "ERC4626: redeem more than max")
+	//替别人赎回需要份额的授权
+	mustFail(t, chain, bob, func() error {
+		_, err := shares.Redeem(amount(50), bob, alice)
+		return err
+	}, "ERC20: insufficient allowance")
+	mustInvoke(t, chain, alice, func() error {
+		_, err := shares.Approve(bob, amount(50))
+		return err
+	})
+	mustInvoke(t, chain, bob, func() error {
+		_, err := shares.Redeem(amount(50), bob, alice)
+		return err
+	})
+	balance, err := shares.BalanceOf(alice)
+	requireAmount(t, "alice shares", balance, err, 50)
+	allowance, err := shares.Allowance(alice, bob)
+	requireAmount(t, "allowance", allowance, err, 0)
+}
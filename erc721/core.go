@@ -17,7 +17,9 @@ package erc721
 import (
 	"errors"
 
+	"github.com/studyzy/openzeppelin-go/access"
 	"github.com/studyzy/openzeppelin-go/common"
+	"github.com/studyzy/openzeppelin-go/security"
 )
 
 // Option 初始化ERC20合约的选项
@@ -34,6 +36,10 @@ type Option struct {
 
 func (c *ERC721Contract) SetSDK(sdk common.ContractSDK) {
 	c.sdk = sdk
+	c.dal = NewERC20ContractDAL(sdk)
+	c.AccessControl = access.NewAccessControl(sdk)
+	c.Ownable = access.NewOwnableWithRoles(sdk, c.AccessControl)
+	c.RolePausable = security.NewRolePausable(sdk, c.AccessControl, access.PauserRole, "ERC721")
 }
 
 /**
@@ -48,6 +54,9 @@ func (c *ERC721Contract) SetSDK(sdk common.ContractSDK) {
  * Emits a {Transfer} event.
  */
 func (c *ERC721Contract) baseTransfer(from, to common.Account, tokenId *common.SafeUint256) error {
+	if err := c.RequireNotPaused(); err != nil {
+		return err
+	}
 	tokenOwner, err := c.dal.GetTokenOwner(tokenId)
 	if err != nil {
 		return err
@@ -56,26 +65,34 @@ func (c *ERC721Contract) baseTransfer(from, to common.Account, tokenId *common.S
 	if err != nil {
 		return err
 	}
-	if err = common.Require(to.IsZero(), "ERC721: transfer to the zero address"); err != nil {
+	if err = common.Require(!to.IsZero(), "ERC721: transfer to the zero address"); err != nil {
 		return err
 	}
 
 	if c.option.BeforeTransfer != nil {
-		err = c.option.BeforeTransfer(from, to, tokenId)
+		if err = c.option.BeforeTransfer(from, to, tokenId); err != nil {
+			return err
+		}
 	}
 	// Clear approvals from the previous owner
-	err = c.dal.DeleteTokenApproval(tokenId)
-	fromBal, err := c.dal.GetBalance(from)
-	newFromBal, _ := common.SafeSub(fromBal, common.NewSafeUint256(1))
-	err = c.dal.SetBalance(from, newFromBal)
-	toBal, err := c.dal.GetBalance(to)
-	newToBal, _ := common.SafeAdd(toBal, common.NewSafeUint256(1))
-	err = c.dal.SetBalance(to, newToBal)
-	err = c.dal.SetTokenOwner(tokenId, to)
-	c.sdk.EmitEvent("transfer", from.ToString(), to.ToString(), tokenId.ToString())
+	if err = c.dal.DeleteTokenApproval(tokenId); err != nil {
+		return err
+	}
+	if err = c.dal.DecreaseBalance(from); err != nil {
+		return err
+	}
+	if err = c.dal.IncreaseBalance(to); err != nil {
+		return err
+	}
+	if err = c.dal.SetTokenOwner(tokenId, to); err != nil {
+		return err
+	}
+	if err = c.sdk.EmitEvent("transfer", from.ToString(), to.ToString(), tokenId.ToString()); err != nil {
+		return err
+	}
 
 	if c.option.AfterTransfer != nil {
-		err = c.option.AfterTransfer(from, to, tokenId)
+		return c.option.AfterTransfer(from, to, tokenId)
 	}
 	return nil
 }
@@ -93,6 +110,9 @@ func (c *ERC721Contract) baseTransfer(from, to common.Account, tokenId *common.S
  * Emits a {Transfer} event.
  */
 func (c *ERC721Contract) baseMint(to common.Account, tokenId *common.SafeUint256) error {
+	if err := c.RequireNotPaused(); err != nil {
+		return err
+	}
 	if err := common.Require(!to.IsZero(), "ERC721: mint to the zero address"); err != nil {
 		return err
 	}
@@ -101,17 +121,25 @@ func (c *ERC721Contract) baseMint(to common.Account, tokenId *common.SafeUint256
 	}
 	from := c.sdk.NewZeroAccount()
 	if c.option.BeforeTransfer != nil {
-		c.option.BeforeTransfer(from, to, tokenId)
+		if err := c.option.BeforeTransfer(from, to, tokenId); err != nil {
+			return err
+		}
 	}
-	c.dal.IncreaseBalance(to)
 	//_balances[to] += 1;
-	c.dal.SetTokenOwner(tokenId, to)
+	if err := c.dal.IncreaseBalance(to); err != nil {
+		return err
+	}
 	//_owners[tokenId] = to;
-	c.sdk.EmitEvent("transfer", from.ToString(), to.ToString(), tokenId.ToString())
+	if err := c.dal.SetTokenOwner(tokenId, to); err != nil {
+		return err
+	}
 	//emit Transfer(address(0), to, tokenId);
+	if err := c.sdk.EmitEvent("transfer", from.ToString(), to.ToString(), tokenId.ToString()); err != nil {
+		return err
+	}
 	if c.option.AfterTransfer != nil {
-		c.option.AfterTransfer(from, to, tokenId)
 		//_afterTokenTransfer(address(0), to, tokenId);
+		return c.option.AfterTransfer(from, to, tokenId)
 	}
 	return nil
 }
@@ -127,6 +155,9 @@ func (c *ERC721Contract) baseMint(to common.Account, tokenId *common.SafeUint256
  * Emits a {Transfer} event.
  */
 func (c *ERC721Contract) baseBurn(tokenId *common.SafeUint256) error {
+	if err := c.RequireNotPaused(); err != nil {
+		return err
+	}
 	//address owner = ERC721.ownerOf(tokenId);
 	owner, err := c.dal.GetTokenOwner(tokenId)
 	if err != nil {
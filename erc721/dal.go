@@ -27,9 +27,9 @@ const (
 	operatorApprovalKey = "o"
 	nameKey             = "name"
 	symbolKey           = "symbol"
-	adminKey            = "admin"
 	tokenOwnerKey       = "t"
 	baseURIKey          = "uri"
+	adminKey            = "admin"
 )
 
 type ERC721DAL struct {
@@ -103,16 +103,20 @@ func (c *ERC721DAL) SetSymbol(symbol string) error {
 	return c.sdk.PutState(symbolKey, []byte(symbol))
 }
 
-func (c *ERC721DAL) GetAdmin() (common.Account, error) {
+// GetLegacyAdmin 获得角色权限上线之前保存的管理员，没有保存时返回nil
+func (c *ERC721DAL) GetLegacyAdmin() (common.Account, error) {
 	b, err := c.sdk.GetState(adminKey)
-	if err != nil {
+	if err != nil || len(b) == 0 {
 		return nil, err
 	}
 	return c.sdk.NewAccountFromBytes(b)
 }
-func (c *ERC721DAL) SetAdmin(admin common.Account) error {
-	return c.sdk.PutState(adminKey, admin.Bytes())
+
+// DelLegacyAdmin 迁移完成后删除旧的管理员记录
+func (c *ERC721DAL) DelLegacyAdmin() error {
+	return c.sdk.DelState(adminKey)
 }
+
 func bytes2String(b []byte, err error) (string, error) {
 	return string(b), err
 }
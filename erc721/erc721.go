@@ -15,15 +15,19 @@
 package erc721
 
 import (
-	"errors"
 	"fmt"
 
+	"github.com/studyzy/openzeppelin-go/access"
 	"github.com/studyzy/openzeppelin-go/common"
+	"github.com/studyzy/openzeppelin-go/security"
 )
 
 var _ IERC721 = (*ERC721Contract)(nil)
 
 type ERC721Contract struct {
+	*access.AccessControl
+	*access.Ownable
+	*security.RolePausable
 	option  Option
 	_name   string
 	_symbol string
@@ -32,39 +36,77 @@ type ERC721Contract struct {
 }
 
 func NewERC721Contract(option Option, name, symbol string, sdk common.ContractSDK) *ERC721Contract {
+	roles := access.NewAccessControl(sdk)
 	erc721 := &ERC721Contract{
-		option:  option,
-		_name:   name,
-		_symbol: symbol,
-		sdk:     sdk,
-		dal:     NewERC20ContractDAL(sdk),
+		AccessControl: roles,
+		Ownable:       access.NewOwnableWithRoles(sdk, roles),
+		RolePausable:  security.NewRolePausable(sdk, roles, access.PauserRole, "ERC721"),
+		option:        option,
+		_name:         name,
+		_symbol:       symbol,
+		sdk:           sdk,
+		dal:           NewERC20ContractDAL(sdk),
 	}
 	return erc721
 }
 
 func (c *ERC721Contract) InitERC721(name, symbol string, admin common.Account) error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		//此处支持在安装合约的时候指定name,symbol
+		//如果没有参数指定，那么就使用NewERC20Contract构造的时候的值
+		if len(name) > 0 {
+			c._name = name
+		}
+		if err := c.dal.SetName(c._name); err != nil {
+			return err
+		}
+		if len(symbol) > 0 {
+			c._symbol = symbol
 
-	//此处支持在安装合约的时候指定name,symbol
-	//如果没有参数指定，那么就使用NewERC20Contract构造的时候的值
-	if len(name) > 0 {
-		c._name = name
-	}
-	if err := c.dal.SetName(c._name); err != nil {
-		return err
-	}
-	if len(symbol) > 0 {
-		c._symbol = symbol
+		}
+		if err := c.dal.SetSymbol(c._symbol); err != nil {
+			return err
+		}
 
-	}
-	if err := c.dal.SetSymbol(c._symbol); err != nil {
-		return err
-	}
+		//admin同时是合约的owner，之后可以通过两步转移修改owner
+		if err := c.SetupOwner(admin); err != nil {
+			return fmt.Errorf("set owner failed, err:%s", err)
+		}
+		//给admin授予管理员、铸币和暂停角色，方便后面mint的时候判断权限
+		if err := c.SetupRole(access.AdminRole, admin); err != nil {
+			return fmt.Errorf("set admin failed, err:%s", err)
+		}
+		if err := c.SetupRole(access.MinterRole, admin); err != nil {
+			return fmt.Errorf("set minter failed, err:%s", err)
+		}
+		if err := c.SetupRole(access.PauserRole, admin); err != nil {
+			return fmt.Errorf("set pauser failed, err:%s", err)
+		}
+		return nil
+	})
+}
 
-	//set Admin，方便后面mint的时候判断权限
-	if err := c.dal.SetAdmin(admin); err != nil {
-		return fmt.Errorf("set admin failed, err:%s", err)
-	}
-	return nil
+/**
+ * @dev Grants `AdminRole` and `MinterRole` to the admin stored by versions
+ * before role-based access control and removes the legacy record, so the
+ * admin of an upgraded contract can still mint and manage roles.
+ *
+ * Does nothing if no legacy admin is stored, so it is safe to call on every upgrade.
+ */
+func (c *ERC721Contract) MigrateLegacyAdmin() error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		admin, err := c.dal.GetLegacyAdmin()
+		if err != nil || admin == nil {
+			return err
+		}
+		if err = c.SetupRole(access.AdminRole, admin); err != nil {
+			return fmt.Errorf("set admin failed, err:%s", err)
+		}
+		if err = c.SetupRole(access.MinterRole, admin); err != nil {
+			return fmt.Errorf("set minter failed, err:%s", err)
+		}
+		return c.dal.DelLegacyAdmin()
+	})
 }
 func (c *ERC721Contract) BalanceOf(owner common.Account) (*common.SafeUint256, error) {
 	err := common.Require(!owner.IsZero(), "ERC721: address zero is not a valid owner")
@@ -88,19 +130,21 @@ func (c *ERC721Contract) OwnerOf(tokenId *common.SafeUint256) (common.Account, e
 }
 
 func (c *ERC721Contract) SafeTransferFrom2(from, to common.Account, tokenId *common.SafeUint256, data []byte) error {
-	sender, err := c.sdk.GetTxSender()
-	if err != nil {
-		return err
-	}
-	_isApprovedOrOwner, err := c.baseIsApprovedOrOwner(sender, tokenId)
-	if err != nil {
-		return err
-	}
-	err = common.Require(_isApprovedOrOwner, "ERC721: caller is not token owner or approved")
-	if err != nil {
-		return err
-	}
-	return c.baseSafeTransfer(from, to, tokenId, data)
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		sender, err := c.sdk.GetTxSender()
+		if err != nil {
+			return err
+		}
+		_isApprovedOrOwner, err := c.baseIsApprovedOrOwner(sender, tokenId)
+		if err != nil {
+			return err
+		}
+		err = common.Require(_isApprovedOrOwner, "ERC721: caller is not token owner or approved")
+		if err != nil {
+			return err
+		}
+		return c.baseSafeTransfer(from, to, tokenId, data)
+	})
 }
 
 func (c *ERC721Contract) SafeTransferFrom(from, to common.Account, tokenId *common.SafeUint256) error {
@@ -108,48 +152,60 @@ func (c *ERC721Contract) SafeTransferFrom(from, to common.Account, tokenId *comm
 }
 
 func (c *ERC721Contract) TransferFrom(from, to common.Account, tokenId *common.SafeUint256) error {
-	sender, err := c.sdk.GetTxSender()
-	if err != nil {
-		return err
-	}
-	_isApprovedOrOwner, err := c.baseIsApprovedOrOwner(sender, tokenId)
-	if err != nil {
-		return err
-	}
-	err = common.Require(_isApprovedOrOwner, "ERC721: caller is not token owner or approved")
-	if err != nil {
-		return err
-	}
-	return c.baseTransfer(from, to, tokenId)
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		sender, err := c.sdk.GetTxSender()
+		if err != nil {
+			return err
+		}
+		_isApprovedOrOwner, err := c.baseIsApprovedOrOwner(sender, tokenId)
+		if err != nil {
+			return err
+		}
+		err = common.Require(_isApprovedOrOwner, "ERC721: caller is not token owner or approved")
+		if err != nil {
+			return err
+		}
+		return c.baseTransfer(from, to, tokenId)
+	})
 }
 
 func (c *ERC721Contract) Approve(to common.Account, tokenId *common.SafeUint256) error {
-	//address owner = ERC721.ownerOf(tokenId);
-	owner, err := c.dal.GetTokenOwner(tokenId)
-	if err != nil {
-		return err
-	}
-	common.Require(!to.Equal(owner), "ERC721: approval to current owner")
-	sender, err := c.sdk.GetTxSender()
-	if err != nil {
-		return err
-	}
-	isApprovedForAll, err := c.IsApprovedForAll(owner, sender)
-	err = common.Require(sender.Equal(owner) || isApprovedForAll,
-		"ERC721: approve caller is not token owner or approved for all")
-	if err != nil {
-		return err
-	}
-	return c.baseApprove(to, tokenId)
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		if err := c.RequireNotPaused(); err != nil {
+			return err
+		}
+		//address owner = ERC721.ownerOf(tokenId);
+		owner, err := c.dal.GetTokenOwner(tokenId)
+		if err != nil {
+			return err
+		}
+		common.Require(!to.Equal(owner), "ERC721: approval to current owner")
+		sender, err := c.sdk.GetTxSender()
+		if err != nil {
+			return err
+		}
+		isApprovedForAll, err := c.IsApprovedForAll(owner, sender)
+		err = common.Require(sender.Equal(owner) || isApprovedForAll,
+			"ERC721: approve caller is not token owner or approved for all")
+		if err != nil {
+			return err
+		}
+		return c.baseApprove(to, tokenId)
+	})
 }
 
 func (c *ERC721Contract) SetApprovalForAll(operator common.Account, approved bool) error {
-	//_setApprovalForAll(_msgSender(), operator, approved);
-	sender, err := c.sdk.GetTxSender()
-	if err != nil {
-		return err
-	}
-	return c.dal.SetOperatorApproval(sender, operator, approved)
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		if err := c.RequireNotPaused(); err != nil {
+			return err
+		}
+		//_setApprovalForAll(_msgSender(), operator, approved);
+		sender, err := c.sdk.GetTxSender()
+		if err != nil {
+			return err
+		}
+		return c.dal.SetOperatorApproval(sender, operator, approved)
+	})
 }
 
 func (c *ERC721Contract) GetApproved(tokenId *common.SafeUint256) (common.Account, error) {
@@ -199,37 +255,30 @@ func (c *ERC721Contract) TokenURI(tokenId *common.SafeUint256) (string, error) {
 }
 
 func (c *ERC721Contract) Mint(to common.Account, tokenId *common.SafeUint256) error {
-
-	//check is admin
-	sender, err := c.sdk.GetTxSender()
-	if err != nil {
-		return fmt.Errorf("Get sender address failed, err:%s", err)
-	}
-
-	admin, err := c.dal.GetAdmin()
-	if err != nil {
-		return err
-	}
-	if !sender.Equal(admin) {
-		return errors.New("only admin can mint tokens")
-	}
-	//call base mint
-	return c.baseMint(to, tokenId)
-
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		//check is minter
+		if err := c.OnlyRole(access.MinterRole); err != nil {
+			return err
+		}
+		//call base mint
+		return c.baseMint(to, tokenId)
+	})
 }
 
 func (c *ERC721Contract) Burn(tokenId *common.SafeUint256) error {
-	sender, err := c.sdk.GetTxSender()
-	if err != nil {
-		return err
-	}
-	_isApprovedOrOwner, err := c.baseIsApprovedOrOwner(sender, tokenId)
-	if err != nil {
-		return err
-	}
-	err = common.Require(_isApprovedOrOwner, "ERC721: caller is not token owner or approved")
-	if err != nil {
-		return err
-	}
-	return c.baseBurn(tokenId)
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		sender, err := c.sdk.GetTxSender()
+		if err != nil {
+			return err
+		}
+		_isApprovedOrOwner, err := c.baseIsApprovedOrOwner(sender, tokenId)
+		if err != nil {
+			return err
+		}
+		err = common.Require(_isApprovedOrOwner, "ERC721: caller is not token owner or approved")
+		if err != nil {
+			return err
+		}
+		return c.baseBurn(tokenId)
+	})
 }
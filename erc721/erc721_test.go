@@ -0,0 +1,62 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erc721
+
+import (
+	"testing"
+
+	"github.com/studyzy/openzeppelin-go/access"
+	"github.com/studyzy/openzeppelin-go/common"
+	"github.com/studyzy/openzeppelin-go/mock"
+)
+
+func TestMigrateLegacyAdmin(t *testing.T) {
+	admin := mock.NewAccount("admin")
+	bob := mock.NewAccount("bob")
+	chain := mock.NewChain()
+	sdk := chain.Deploy("token", nil)
+	token := NewERC721Contract(Option{}, "NFT", "NFT", sdk)
+	id := common.NewSafeUint256(1)
+	mint := func() error { return token.Mint(bob, id) }
+	//旧版本的初始化只保存了管理员，没有授予任何角色
+	if err := chain.Invoke(admin, func() error { return sdk.PutState(adminKey, admin.Bytes()) }); err != nil {
+		t.Fatal(err)
+	}
+	if err := chain.Invoke(admin, mint); err == nil {
+		t.Fatal("legacy admin should not mint before the migration")
+	}
+	//任何人都可以触发迁移，角色只会授予旧的管理员
+	if err := chain.Invoke(bob, token.MigrateLegacyAdmin); err != nil {
+		t.Fatal(err)
+	}
+	for _, role := range []string{access.AdminRole, access.MinterRole} {
+		if has, err := token.HasRole(role, admin); err != nil || !has {
+			t.Fatalf("legacy admin should have %s, err:%v", role, err)
+		}
+	}
+	if has, _ := token.HasRole(access.AdminRole, bob); has {
+		t.Fatal("caller of the migration should not get the admin role")
+	}
+	if err := chain.Invoke(admin, mint); err != nil {
+		t.Fatal(err)
+	}
+	//旧记录已经删除，再次迁移什么也不做
+	if b, _ := sdk.GetState(adminKey); len(b) != 0 {
+		t.Fatal("legacy admin record should be removed")
+	}
+	if err := chain.Invoke(bob, token.MigrateLegacyAdmin); err != nil {
+		t.Fatal(err)
+	}
+}
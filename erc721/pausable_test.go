@@ -0,0 +1,69 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erc721
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/studyzy/openzeppelin-go/common"
+	"github.com/studyzy/openzeppelin-go/mock"
+)
+
+func TestPauseBlocksTransfersAndApprovals(t *testing.T) {
+	admin := mock.NewAccount("admin")
+	alice := mock.NewAccount("alice")
+	bob := mock.NewAccount("bob")
+	chain := mock.NewChain()
+	token := NewERC721Contract(Option{}, "NFT", "NFT", chain.Deploy("nft", nil))
+	tokenId := common.NewSafeUint256(1)
+	invoke := func(sender mock.Account, fn func() error) error {
+		return chain.Invoke(sender, fn)
+	}
+	if err := invoke(admin, func() error { return token.InitERC721("", "", admin) }); err != nil {
+		t.Fatal(err)
+	}
+	if err := invoke(admin, func() error { return token.Mint(alice, tokenId) }); err != nil {
+		t.Fatal(err)
+	}
+	if err := invoke(alice, token.Pause); err == nil {
+		t.Fatal("account without pauser role should not pause")
+	}
+	if err := invoke(admin, token.Pause); err != nil {
+		t.Fatal(err)
+	}
+	for name, fn := range map[string]func() error{
+		"transferFrom":      func() error { return token.TransferFrom(alice, bob, tokenId) },
+		"approve":           func() error { return token.Approve(bob, tokenId) },
+		"setApprovalForAll": func() error { return token.SetApprovalForAll(bob, true) },
+	} {
+		err := invoke(alice, fn)
+		if err == nil || !strings.Contains(err.Error(), "ERC721Pausable: token operation while paused") {
+			t.Fatalf("%s while paused: got %v", name, err)
+		}
+	}
+	if err := invoke(admin, token.Unpause); err != nil {
+		t.Fatal(err)
+	}
+	if err := invoke(alice, func() error { return token.Approve(bob, tokenId) }); err != nil {
+		t.Fatal(err)
+	}
+	if err := invoke(bob, func() error { return token.TransferFrom(alice, bob, tokenId) }); err != nil {
+		t.Fatal(err)
+	}
+	if owner, _ := token.OwnerOf(tokenId); !owner.Equal(bob) {
+		t.Fatalf("owner = %s, want bob", owner.ToString())
+	}
+}
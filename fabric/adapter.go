@@ -15,6 +15,10 @@
 package fabric
 
 import (
+	"errors"
+	"unicode/utf8"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
 	"github.com/hyperledger/fabric-contract-api-go/contractapi"
 	"github.com/studyzy/openzeppelin-go/common"
 )
@@ -46,10 +50,34 @@ func (m MspUser) Equal(account common.Account) bool {
 
 var _ common.ContractSDK = (*SdkAdapter)(nil)
 
+var _ common.StateIterator = (*queryIterator)(nil)
+
+// queryIterator 把Fabric的StateQueryIteratorInterface包装为common.StateIterator
+type queryIterator struct {
+	it shim.StateQueryIteratorInterface
+}
+
+func (q *queryIterator) HasNext() bool {
+	return q.it.HasNext()
+}
+
+func (q *queryIterator) Next() (string, []byte, error) {
+	kv, err := q.it.Next()
+	if err != nil {
+		return "", nil, err
+	}
+	return kv.Key, kv.Value, nil
+}
+
+func (q *queryIterator) Close() error {
+	return q.it.Close()
+}
+
 type SdkAdapter struct {
 	ctx           contractapi.TransactionContextInterface
 	eventEncoder  func(string, ...string) ([]byte, error)
 	contractExist func(string) (bool, error)
+	verifier      common.SignatureVerifier
 }
 
 func (s SdkAdapter) NewAccountFromBytes(b []byte) (common.Account, error) {
@@ -73,6 +101,27 @@ func (s SdkAdapter) CreateCompositeKey(prefix string, data ...string) (string, e
 	return s.ctx.GetStub().CreateCompositeKey(prefix, data)
 }
 
+func (s SdkAdapter) SplitCompositeKey(compositeKey string) (string, []string, error) {
+	return s.ctx.GetStub().SplitCompositeKey(compositeKey)
+}
+
+// NewIteratorWithPrefix 通过GetStateByRange实现前缀遍历，Fabric的范围查询不会返回组合键
+func (s SdkAdapter) NewIteratorWithPrefix(prefix string) (common.StateIterator, error) {
+	it, err := s.ctx.GetStub().GetStateByRange(prefix, prefix+string(utf8.MaxRune))
+	if err != nil {
+		return nil, err
+	}
+	return &queryIterator{it: it}, nil
+}
+
+func (s SdkAdapter) NewIteratorWithCompositeKey(prefix string, data ...string) (common.StateIterator, error) {
+	it, err := s.ctx.GetStub().GetStateByPartialCompositeKey(prefix, data)
+	if err != nil {
+		return nil, err
+	}
+	return &queryIterator{it: it}, nil
+}
+
 func (s SdkAdapter) IsContract(account common.Account) bool {
 	exist, err := s.contractExist(account.ToString())
 	if err != nil {
@@ -118,6 +167,78 @@ func (s SdkAdapter) GetTxSender() (common.Account, error) {
 	return NewMspUser(id), nil
 }
 
+// creatorKey 保存部署链码账户的状态键
+const creatorKey = "fabricCreator"
+
+// InitCreator 把当前交易的发送者记录为部署链码的账户。Fabric的链码无法获得部署者，
+// 而SdkAdapter在每次调用时都会重新创建，所以部署者保存在状态中，需要在实例化链码时调用一次，
+// 否则IsTxSenderCreator会返回错误
+func (s SdkAdapter) InitCreator() error {
+	creator, err := s.GetState(creatorKey)
+	if err != nil {
+		return err
+	}
+	if len(creator) != 0 {
+		return errors.New("fabric: contract creator already set")
+	}
+	sender, err := s.GetTxSender()
+	if err != nil {
+		return err
+	}
+	return s.PutState(creatorKey, sender.Bytes())
+}
+
+// IsTxSenderCreator 比较交易发送者和InitCreator记录的部署者
+func (s SdkAdapter) IsTxSenderCreator() (bool, error) {
+	creator, err := s.GetState(creatorKey)
+	if err != nil {
+		return false, err
+	}
+	if len(creator) == 0 {
+		return false, errors.New("fabric: contract creator not set, call InitCreator when instantiating the chaincode")
+	}
+	sender, err := s.GetTxSender()
+	if err != nil {
+		return false, err
+	}
+	return NewMspUser(string(creator)).Equal(sender), nil
+}
+
+func (s SdkAdapter) GetTxId() (string, error) {
+	return s.ctx.GetStub().GetTxID(), nil
+}
+
+// GetTxTimestamp 返回客户端在交易提案中设置的时间戳
+func (s SdkAdapter) GetTxTimestamp() (int64, error) {
+	ts, err := s.ctx.GetStub().GetTxTimestamp()
+	if err != nil {
+		return 0, err
+	}
+	return ts.GetSeconds(), nil
+}
+
+// GetBlockHeight Fabric的链码在背书时无法获得区块高度，所以总是返回错误
+func (s SdkAdapter) GetBlockHeight() (uint64, error) {
+	return 0, errors.New("fabric: block height is not available in chaincode")
+}
+
+// ClockMode Fabric的链码无法获得区块高度，时间点使用交易时间戳
+func (s SdkAdapter) ClockMode() string {
+	return common.ClockModeTimestamp
+}
+
+// SetSignatureVerifier 设置VerifySignature使用的验签器，一般为NewX509Verifier
+func (s *SdkAdapter) SetSignatureVerifier(verifier common.SignatureVerifier) {
+	s.verifier = verifier
+}
+
+func (s SdkAdapter) VerifySignature(account common.Account, digest []byte, signature []byte) error {
+	if s.verifier == nil {
+		return errors.New("fabric: signature verifier not set")
+	}
+	return s.verifier.VerifySignature(account, digest, signature)
+}
+
 func (s SdkAdapter) EmitEvent(topic string, data ...string) error {
 	payload, err := s.eventEncoder(topic, data...)
 	if err != nil {
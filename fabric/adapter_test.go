@@ -0,0 +1,67 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fabric
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// clientIdentity 只实现GetID的客户端身份
+type clientIdentity struct {
+	cid.ClientIdentity
+	id string
+}
+
+func (c clientIdentity) GetID() (string, error) {
+	return c.id, nil
+}
+
+func newAdapter(stub *shimtest.MockStub, sender string) *SdkAdapter {
+	ctx := &contractapi.TransactionContext{}
+	ctx.SetStub(stub)
+	ctx.SetClientIdentity(clientIdentity{id: sender})
+	return NewSDkAdapter(ctx, nil, nil)
+}
+
+func TestIsTxSenderCreator(t *testing.T) {
+	stub := shimtest.NewMockStub("token", nil)
+	stub.MockTransactionStart("tx0")
+	if _, err := newAdapter(stub, "alice").IsTxSenderCreator(); err == nil {
+		t.Fatal("IsTxSenderCreator should fail before InitCreator")
+	}
+	if err := newAdapter(stub, "alice").InitCreator(); err != nil {
+		t.Fatal(err)
+	}
+	stub.MockTransactionEnd("tx0")
+
+	//部署者保存在状态中，新创建的adapter也能读到
+	stub.MockTransactionStart("tx1")
+	defer stub.MockTransactionEnd("tx1")
+	isCreator, err := newAdapter(stub, "alice").IsTxSenderCreator()
+	if err != nil || !isCreator {
+		t.Fatalf("IsTxSenderCreator(alice) = %v, %v, want true", isCreator, err)
+	}
+	isCreator, err = newAdapter(stub, "bob").IsTxSenderCreator()
+	if err != nil || isCreator {
+		t.Fatalf("IsTxSenderCreator(bob) = %v, %v, want false", isCreator, err)
+	}
+	if err = newAdapter(stub, "bob").InitCreator(); err == nil {
+		t.Fatal("InitCreator should fail once the creator is set")
+	}
+}
@@ -64,6 +64,15 @@ func encodeEvent(topic string, data ...string) ([]byte, error) {
 	return payload, err
 }
 
+// InitCreator records the submitting client as the creator of the chaincode
+// It must be called once when the chaincode is instantiated, methods restricted to the creator fail until then
+func (s *SmartContract) InitCreator(ctx contractapi.TransactionContextInterface) error {
+	return fabric.NewSDkAdapter(ctx, encodeEvent, func(contractName string) (bool, error) {
+		//TODO
+		return false, nil
+	}).InitCreator()
+}
+
 // Mint creates new tokens and adds them to minter's account balance
 // This function triggers a Transfer event
 func (s *SmartContract) Mint(ctx contractapi.TransactionContextInterface, recipient string, amount int) error {
@@ -231,6 +240,50 @@ func (s *SmartContract) Approve(ctx contractapi.TransactionContextInterface, spe
 	return errors.New("approve fail")
 }
 
+// IncreaseAllowance increases the allowance granted to the spender by the calling client
+// This function triggers an Approval event
+func (s *SmartContract) IncreaseAllowance(ctx contractapi.TransactionContextInterface, spender string, value int) error {
+	s.erc20Contract.SetSDK(fabric.NewSDkAdapter(ctx, encodeEvent, func(contractName string) (bool, error) {
+		//TODO
+		return false, nil
+	}))
+	account := fabric.NewMspUser(spender)
+	if value <= 0 {
+		return fmt.Errorf("increase allowance amount must be a positive integer")
+	}
+	amount256 := common.NewSafeUint256(uint64(value))
+	success, err := s.erc20Contract.IncreaseAllowance(account, amount256)
+	if err != nil {
+		return err
+	}
+	if success {
+		return nil
+	}
+	return errors.New("increase allowance fail")
+}
+
+// DecreaseAllowance decreases the allowance granted to the spender by the calling client
+// This function triggers an Approval event
+func (s *SmartContract) DecreaseAllowance(ctx contractapi.TransactionContextInterface, spender string, value int) error {
+	s.erc20Contract.SetSDK(fabric.NewSDkAdapter(ctx, encodeEvent, func(contractName string) (bool, error) {
+		//TODO
+		return false, nil
+	}))
+	account := fabric.NewMspUser(spender)
+	if value <= 0 {
+		return fmt.Errorf("decrease allowance amount must be a positive integer")
+	}
+	amount256 := common.NewSafeUint256(uint64(value))
+	success, err := s.erc20Contract.DecreaseAllowance(account, amount256)
+	if err != nil {
+		return err
+	}
+	if success {
+		return nil
+	}
+	return errors.New("decrease allowance fail")
+}
+
 // Allowance returns the amount still available for the spender to withdraw from the owner
 func (s *SmartContract) Allowance(ctx contractapi.TransactionContextInterface, owner string, spender string) (int, error) {
 	s.erc20Contract.SetSDK(fabric.NewSDkAdapter(ctx, encodeEvent, func(contractName string) (bool, error) {
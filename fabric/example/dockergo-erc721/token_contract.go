@@ -16,7 +16,6 @@ package main
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	"strconv"
 
@@ -73,6 +72,15 @@ func encodeEvent(topic string, data ...string) ([]byte, error) {
 	return payload, err
 }
 
+// InitCreator records the submitting client as the creator of the chaincode
+// It must be called once when the chaincode is instantiated, methods restricted to the creator fail until then
+func (s *SmartContract) InitCreator(ctx contractapi.TransactionContextInterface) error {
+	return fabric.NewSDkAdapter(ctx, encodeEvent, func(contractName string) (bool, error) {
+		//TODO
+		return false, nil
+	}).InitCreator()
+}
+
 // Mint creates new tokens and adds them to minter's account balance
 // This function triggers a Transfer event
 func (s *SmartContract) Mint(ctx contractapi.TransactionContextInterface, recipient string, tokenId int) error {
@@ -86,14 +94,7 @@ func (s *SmartContract) Mint(ctx contractapi.TransactionContextInterface, recipi
 		return fmt.Errorf("mint tokenId must be a positive integer")
 	}
 	tokenId256 := common.NewSafeUint256(uint64(tokenId))
-	success, err := s.erc721Contract.Mint(account, tokenId256)
-	if err != nil {
-		return err
-	}
-	if success {
-		return nil
-	}
-	return errors.New("mint fail")
+	return s.erc721Contract.Mint(account, tokenId256)
 }
 
 // Burn redeems tokens the minter's account balance
@@ -4,6 +4,9 @@ go 1.16
 
 require github.com/hyperledger/fabric-contract-api-go v1.2.0
 
-require github.com/studyzy/openzeppelin-go v0.0.0-20230104062650-ce776691aa7c
+require (
+	github.com/hyperledger/fabric-chaincode-go v0.0.0-20220720122508-9207360bbddd
+	github.com/studyzy/openzeppelin-go v0.0.0-20230104062650-ce776691aa7c
+)
 
 replace github.com/studyzy/openzeppelin-go => ../
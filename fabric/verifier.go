@@ -0,0 +1,141 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fabric
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/studyzy/openzeppelin-go/common"
+)
+
+var _ common.SignatureVerifier = (*X509Verifier)(nil)
+
+// X509Verifier 验证Fabric X.509身份的签名。签名为json编码的common.Signature，
+// 其中PublicKey是签名者PEM编码的证书，证书必须由opts中信任的CA签发，
+// 并且按照cid.GetID的规则得到的身份ID要与account相同
+type X509Verifier struct {
+	opts x509.VerifyOptions
+}
+
+// NewX509Verifier 创建X509Verifier，opts中至少需要指定Roots，一般为通道中各组织MSP的根证书
+func NewX509Verifier(opts x509.VerifyOptions) *X509Verifier {
+	if len(opts.KeyUsages) == 0 {
+		opts.KeyUsages = []x509.ExtKeyUsage{x509.ExtKeyUsageAny}
+	}
+	return &X509Verifier{opts: opts}
+}
+
+func (v *X509Verifier) VerifySignature(account common.Account, digest []byte, signature []byte) error {
+	sig, err := common.ParseSignature(signature)
+	if err != nil {
+		return err
+	}
+	block, _ := pem.Decode(sig.PublicKey)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return errors.New("fabric: invalid certificate pem")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return err
+	}
+	if _, err = cert.Verify(v.opts); err != nil {
+		return fmt.Errorf("fabric: untrusted certificate, err:%s", err)
+	}
+	if clientID(cert) != account.ToString() {
+		return errors.New("fabric: certificate does not belong to account")
+	}
+	pub, ok := cert.PublicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return errors.New("fabric: only ecdsa certificate is supported")
+	}
+	if !ecdsa.VerifyASN1(pub, digest, sig.Signature) {
+		return errors.New("fabric: invalid signature")
+	}
+	return nil
+}
+
+// clientID 与cid.ClientID.GetID的计算方式相同：base64("x509::" + subject + "::" + issuer)
+func clientID(cert *x509.Certificate) string {
+	id := fmt.Sprintf("x509::%s::%s", getDN(&cert.Subject), getDN(&cert.Issuer))
+	return base64.StdEncoding.EncodeToString([]byte(id))
+}
+
+var attributeTypeNames = map[string]string{
+	"2.5.4.6":  "C",
+	"2.5.4.10": "O",
+	"2.5.4.11": "OU",
+	"2.5.4.3":  "CN",
+	"2.5.4.5":  "SERIALNUMBER",
+	"2.5.4.7":  "L",
+	"2.5.4.8":  "ST",
+	"2.5.4.9":  "STREET",
+	"2.5.4.17": "POSTALCODE",
+}
+
+// getDN 拷贝自fabric-chaincode-go的cid包，保证计算出的身份ID与GetTxSender返回的一致
+func getDN(name *pkix.Name) string {
+	r := name.ToRDNSequence()
+	s := ""
+	for i := 0; i < len(r); i++ {
+		rdn := r[len(r)-1-i]
+		if i > 0 {
+			s += ","
+		}
+		for j, tv := range rdn {
+			if j > 0 {
+				s += "+"
+			}
+			typeString := tv.Type.String()
+			typeName, ok := attributeTypeNames[typeString]
+			if !ok {
+				derBytes, err := asn1.Marshal(tv.Value)
+				if err == nil {
+					s += typeString + "=#" + hex.EncodeToString(derBytes)
+					continue
+				}
+				typeName = typeString
+			}
+			valueString := fmt.Sprint(tv.Value)
+			escaped := ""
+			begin := 0
+			for idx, c := range valueString {
+				if (idx == 0 && (c == ' ' || c == '#')) ||
+					(idx == len(valueString)-1 && c == ' ') {
+					escaped += valueString[begin:idx]
+					escaped += "\\" + string(c)
+					begin = idx + 1
+					continue
+				}
+				switch c {
+				case ',', '+', '"', '\\', '<', '>', ';':
+					escaped += valueString[begin:idx]
+					escaped += "\\" + string(c)
+					begin = idx + 1
+				}
+			}
+			escaped += valueString[begin:]
+			s += typeName + "=" + escaped
+		}
+	}
+	return s
+}
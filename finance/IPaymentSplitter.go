@@ -0,0 +1,61 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package finance
+
+import "github.com/studyzy/openzeppelin-go/common"
+
+/**
+ * @dev Interface of a contract that splits the ERC20 tokens it receives among
+ * a fixed group of payees, in proportion to their shares.
+ */
+type IPaymentSplitter interface {
+	/**
+	 * @dev Getter for the total shares held by payees.
+	 */
+	TotalShares() (*common.SafeUint256, error)
+
+	/**
+	 * @dev Getter for the amount of shares held by an account.
+	 */
+	Shares(account common.Account) (*common.SafeUint256, error)
+
+	/**
+	 * @dev Getter for the addresses of the payees.
+	 */
+	Payees() ([]common.Account, error)
+
+	/**
+	 * @dev Getter for the total amount of `token` already released.
+	 */
+	TotalReleased(token common.Account) (*common.SafeUint256, error)
+
+	/**
+	 * @dev Getter for the amount of `token` already released to a payee.
+	 */
+	Released(token, account common.Account) (*common.SafeUint256, error)
+
+	/**
+	 * @dev Getter for the amount of `token` that `account` can release now.
+	 */
+	Releasable(token, account common.Account) (*common.SafeUint256, error)
+
+	/**
+	 * @dev Triggers a transfer to `account` of the amount of `token` they are owed,
+	 * according to their percentage of the total shares and their previous withdrawals.
+	 *
+	 * Emits a {PaymentReleased} event.
+	 */
+	Release(token, account common.Account) (*common.SafeUint256, error)
+}
@@ -0,0 +1,55 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package finance
+
+import "github.com/studyzy/openzeppelin-go/common"
+
+/**
+ * @dev Interface of a vesting wallet that holds ERC20 tokens on behalf of
+ * beneficiaries and releases them following cliff and linear schedules.
+ */
+type IVestingWallet interface {
+	/**
+	 * @dev Returns the vesting schedule identified by `id`.
+	 */
+	Schedule(id uint64) (*VestingSchedule, error)
+
+	/**
+	 * @dev Returns all the vesting schedules of `beneficiary`.
+	 */
+	SchedulesOf(beneficiary common.Account) ([]*VestingSchedule, error)
+
+	/**
+	 * @dev Amount of `token` vested for `beneficiary` at `timestamp`, summed over all its schedules.
+	 */
+	VestedAmount(beneficiary, token common.Account, timestamp int64) (*common.SafeUint256, error)
+
+	/**
+	 * @dev Amount of `token` already released to `beneficiary`.
+	 */
+	Released(beneficiary, token common.Account) (*common.SafeUint256, error)
+
+	/**
+	 * @dev Amount of `token` that `beneficiary` can release now.
+	 */
+	Releasable(beneficiary, token common.Account) (*common.SafeUint256, error)
+
+	/**
+	 * @dev Releases the `token` that have already vested to the caller.
+	 *
+	 * Emits an {ERC20Released} event.
+	 */
+	Release(token common.Account) (*common.SafeUint256, error)
+}
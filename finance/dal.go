@@ -0,0 +1,309 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package finance
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+
+	"github.com/studyzy/openzeppelin-go/common"
+)
+
+const (
+	selfKey          = "self"
+	scheduleCountKey = "scheduleCount"
+	scheduleKey      = "schedule"
+	beneficiaryKey   = "beneficiary"
+	payeesKey        = "payees"
+	sharesKey        = "shares"
+	totalSharesKey   = "totalShares"
+	releasedKey      = "released"
+	totalReleasedKey = "totalReleased"
+)
+
+type VestingWalletDAL struct {
+	sdk common.StateOperator
+}
+
+func NewVestingWalletDAL(sdk common.StateOperator) *VestingWalletDAL {
+	return &VestingWalletDAL{sdk: sdk}
+}
+
+// scheduleRecord 释放计划在状态数据库中的存储格式，账户都保存为字符串
+type scheduleRecord struct {
+	Beneficiary string
+	Token       string
+	Amount      string
+	Released    string
+	Start       int64
+	Cliff       int64
+	Duration    int64
+	Revocable   bool
+	Revoked     bool
+}
+
+// GetSelf 获得初始化时记录的本合约地址，没有设置时返回零地址
+func (c *VestingWalletDAL) GetSelf() (common.Account, error) {
+	b, err := c.sdk.GetState(selfKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return c.sdk.NewZeroAccount(), nil
+	}
+	return c.sdk.NewAccountFromString(string(b))
+}
+func (c *VestingWalletDAL) SetSelf(self common.Account) error {
+	return c.sdk.PutState(selfKey, []byte(self.ToString()))
+}
+
+// GetScheduleCount 获得已经创建的释放计划数量，计划的id从1开始
+func (c *VestingWalletDAL) GetScheduleCount() (uint64, error) {
+	b, err := c.sdk.GetState(scheduleCountKey)
+	if err != nil || len(b) == 0 {
+		return 0, err
+	}
+	return strconv.ParseUint(string(b), 10, 64)
+}
+func (c *VestingWalletDAL) SetScheduleCount(count uint64) error {
+	return c.sdk.PutState(scheduleCountKey, []byte(strconv.FormatUint(count, 10)))
+}
+
+// GetSchedule 获得id对应的释放计划，计划不存在时返回nil
+func (c *VestingWalletDAL) GetSchedule(id uint64) (*VestingSchedule, error) {
+	key, err := c.sdk.CreateCompositeKey(scheduleKey, strconv.FormatUint(id, 10))
+	if err != nil {
+		return nil, err
+	}
+	b, err := c.sdk.GetState(key)
+	if err != nil || len(b) == 0 {
+		return nil, err
+	}
+	var record scheduleRecord
+	if err = json.Unmarshal(b, &record); err != nil {
+		return nil, err
+	}
+	schedule := &VestingSchedule{
+		Id:        id,
+		Start:     record.Start,
+		Cliff:     record.Cliff,
+		Duration:  record.Duration,
+		Revocable: record.Revocable,
+		Revoked:   record.Revoked,
+	}
+	if schedule.Beneficiary, err = c.sdk.NewAccountFromString(record.Beneficiary); err != nil {
+		return nil, err
+	}
+	if schedule.Token, err = c.sdk.NewAccountFromString(record.Token); err != nil {
+		return nil, err
+	}
+	var ok bool
+	if schedule.Amount, ok = common.ParseSafeUint256(record.Amount); !ok {
+		return nil, errors.New("invalid uint256 data")
+	}
+	if schedule.Released, ok = common.ParseSafeUint256(record.Released); !ok {
+		return nil, errors.New("invalid uint256 data")
+	}
+	return schedule, nil
+}
+
+// SetSchedule 保存释放计划，同时在受益人下建立索引，方便按受益人和代币查找
+func (c *VestingWalletDAL) SetSchedule(schedule *VestingSchedule) error {
+	id := strconv.FormatUint(schedule.Id, 10)
+	key, err := c.sdk.CreateCompositeKey(scheduleKey, id)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(scheduleRecord{
+		Beneficiary: schedule.Beneficiary.ToString(),
+		Token:       schedule.Token.ToString(),
+		Amount:      schedule.Amount.ToString(),
+		Released:    schedule.Released.ToString(),
+		Start:       schedule.Start,
+		Cliff:       schedule.Cliff,
+		Duration:    schedule.Duration,
+		Revocable:   schedule.Revocable,
+		Revoked:     schedule.Revoked,
+	})
+	if err != nil {
+		return err
+	}
+	if err = c.sdk.PutState(key, b); err != nil {
+		return err
+	}
+	indexKey, err := c.sdk.CreateCompositeKey(beneficiaryKey, schedule.Beneficiary.ToString(),
+		schedule.Token.ToString(), id)
+	if err != nil {
+		return err
+	}
+	return c.sdk.PutState(indexKey, []byte("true"))
+}
+
+// GetScheduleIds 获得beneficiary的释放计划id，data可以再指定代币地址只查询该代币的计划
+func (c *VestingWalletDAL) GetScheduleIds(beneficiary common.Account, data ...string) ([]uint64, error) {
+	it, err := c.sdk.NewIteratorWithCompositeKey(beneficiaryKey, append([]string{beneficiary.ToString()}, data...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	ids := make([]uint64, 0)
+	for it.HasNext() {
+		key, _, err := it.Next()
+		if err != nil {
+			return nil, err
+		}
+		_, parts, err := c.sdk.SplitCompositeKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if len(parts) != 3 {
+			return nil, errors.New("invalid schedule key")
+		}
+		id, err := strconv.ParseUint(parts[2], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	//组合键按字典序遍历，这里按创建顺序排序
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+type PaymentSplitterDAL struct {
+	sdk common.StateOperator
+}
+
+func NewPaymentSplitterDAL(sdk common.StateOperator) *PaymentSplitterDAL {
+	return &PaymentSplitterDAL{sdk: sdk}
+}
+
+// GetSelf 获得初始化时记录的本合约地址，没有设置时返回零地址
+func (c *PaymentSplitterDAL) GetSelf() (common.Account, error) {
+	b, err := c.sdk.GetState(selfKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return c.sdk.NewZeroAccount(), nil
+	}
+	return c.sdk.NewAccountFromString(string(b))
+}
+func (c *PaymentSplitterDAL) SetSelf(self common.Account) error {
+	return c.sdk.PutState(selfKey, []byte(self.ToString()))
+}
+
+// GetPayees 按添加顺序获得所有收款人
+func (c *PaymentSplitterDAL) GetPayees() ([]common.Account, error) {
+	b, err := c.sdk.GetState(payeesKey)
+	if err != nil {
+		return nil, err
+	}
+	payees := make([]common.Account, 0)
+	if len(b) == 0 {
+		return payees, nil
+	}
+	var addresses []string
+	if err = json.Unmarshal(b, &addresses); err != nil {
+		return nil, err
+	}
+	for _, address := range addresses {
+		payee, err := c.sdk.NewAccountFromString(address)
+		if err != nil {
+			return nil, err
+		}
+		payees = append(payees, payee)
+	}
+	return payees, nil
+}
+func (c *PaymentSplitterDAL) SetPayees(payees []common.Account) error {
+	addresses := make([]string, len(payees))
+	for i, payee := range payees {
+		addresses[i] = payee.ToString()
+	}
+	b, err := json.Marshal(addresses)
+	if err != nil {
+		return err
+	}
+	return c.sdk.PutState(payeesKey, b)
+}
+
+func (c *PaymentSplitterDAL) GetShares(account common.Account) (*common.SafeUint256, error) {
+	key, err := c.sdk.CreateCompositeKey(sharesKey, account.ToString())
+	if err != nil {
+		return nil, err
+	}
+	return c.getUint256(key)
+}
+func (c *PaymentSplitterDAL) SetShares(account common.Account, shares *common.SafeUint256) error {
+	key, err := c.sdk.CreateCompositeKey(sharesKey, account.ToString())
+	if err != nil {
+		return err
+	}
+	return c.sdk.PutState(key, []byte(shares.ToString()))
+}
+
+func (c *PaymentSplitterDAL) GetTotalShares() (*common.SafeUint256, error) {
+	return c.getUint256(totalSharesKey)
+}
+func (c *PaymentSplitterDAL) SetTotalShares(totalShares *common.SafeUint256) error {
+	return c.sdk.PutState(totalSharesKey, []byte(totalShares.ToString()))
+}
+
+// GetReleased 获得token已经支付给account的数量
+func (c *PaymentSplitterDAL) GetReleased(token, account common.Account) (*common.SafeUint256, error) {
+	key, err := c.sdk.CreateCompositeKey(releasedKey, token.ToString(), account.ToString())
+	if err != nil {
+		return nil, err
+	}
+	return c.getUint256(key)
+}
+func (c *PaymentSplitterDAL) SetReleased(token, account common.Account, released *common.SafeUint256) error {
+	key, err := c.sdk.CreateCompositeKey(releasedKey, token.ToString(), account.ToString())
+	if err != nil {
+		return err
+	}
+	return c.sdk.PutState(key, []byte(released.ToString()))
+}
+
+// GetTotalReleased 获得token已经支付给所有收款人的数量
+func (c *PaymentSplitterDAL) GetTotalReleased(token common.Account) (*common.SafeUint256, error) {
+	key, err := c.sdk.CreateCompositeKey(totalReleasedKey, token.ToString())
+	if err != nil {
+		return nil, err
+	}
+	return c.getUint256(key)
+}
+func (c *PaymentSplitterDAL) SetTotalReleased(token common.Account, released *common.SafeUint256) error {
+	key, err := c.sdk.CreateCompositeKey(totalReleasedKey, token.ToString())
+	if err != nil {
+		return err
+	}
+	return c.sdk.PutState(key, []byte(released.ToString()))
+}
+
+func (c *PaymentSplitterDAL) getUint256(key string) (*common.SafeUint256, error) {
+	b, err := c.sdk.GetState(key)
+	if err != nil {
+		return nil, err
+	}
+	num, ok := common.ParseSafeUint256(string(b))
+	if !ok {
+		return nil, errors.New("invalid uint256 data")
+	}
+	return num, nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package finance
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/studyzy/openzeppelin-go/common"
+	"github.com/studyzy/openzeppelin-go/erc20"
+	"github.com/studyzy/openzeppelin-go/erc20/erc20mock"
+	"github.com/studyzy/openzeppelin-go/mock"
+)
+
+var (
+	admin     = mock.NewAccount("admin")
+	alice     = mock.NewAccount("alice")
+	bob       = mock.NewAccount("bob")
+	tokenAddr = mock.NewAccount("token")
+)
+
+func amount(v uint64) *common.SafeUint256 {
+	return common.NewSafeUint256(v)
+}
+
+func mustInvoke(t *testing.T, chain *mock.Chain, sender mock.Account, fn func() error) {
+	t.Helper()
+	if err := chain.Invoke(sender, fn); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mustFail(t *testing.T, chain *mock.Chain, sender mock.Account, fn func() error, msg string) {
+	t.Helper()
+	err := chain.Invoke(sender, fn)
+	if err == nil {
+		t.Fatalf("expected error containing %q", msg)
+	}
+	if !strings.Contains(err.Error(), msg) {
+		t.Fatalf("got error %q, want %q", err, msg)
+	}
+}
+
+// expectAmount 返回检查查询结果等于want的函数，可以直接把查询方法的两个返回值传给它
+func expectAmount(t *testing.T, want uint64) func(got *common.SafeUint256, err error) {
+	return func(got *common.SafeUint256, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(amount(want)) {
+			t.Fatalf("got %s, want %d", got.ToString(), want)
+		}
+	}
+}
+
+// newToken 部署一个代币，给admin铸造supply个并授权给spender
+func newToken(t *testing.T, chain *mock.Chain, spender mock.Account, supply uint64) *erc20.ERC20Contract {
+	t.Helper()
+	token, err := erc20mock.Deploy(chain, tokenAddr.ToString(), erc20.Option{}, admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mustInvoke(t, chain, admin, func() error {
+		if _, err := token.Mint(admin, amount(supply)); err != nil {
+			return err
+		}
+		_, err := token.Approve(spender, amount(supply))
+		return err
+	})
+	return token
+}
@@ -0,0 +1,258 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package finance
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/studyzy/openzeppelin-go/access"
+	"github.com/studyzy/openzeppelin-go/common"
+	"github.com/studyzy/openzeppelin-go/erc20"
+)
+
+var _ IPaymentSplitter = (*PaymentSplitter)(nil)
+
+// PaymentSplitter 按固定份额在收款人之间分配收到的ERC20代币。
+// 代币直接转给本合约，收款人随时可以领取自己应得的部分，代币余额通过CallContract调用balanceOf查询
+type PaymentSplitter struct {
+	dal *PaymentSplitterDAL
+	sdk common.ContractSDK
+}
+
+// NewPaymentSplitter PaymentSplitter
+// @param sdk
+// @return *PaymentSplitter
+func NewPaymentSplitter(sdk common.ContractSDK) *PaymentSplitter {
+	return &PaymentSplitter{
+		dal: NewPaymentSplitterDAL(sdk),
+		sdk: sdk,
+	}
+}
+
+/**
+ * @dev Sets `payees[i]` to receive `shares[i]` of every token sent to this contract.
+ * Self is the address of this contract, which holds the tokens to split, because
+ * a contract cannot get its own address from the SDK.
+ *
+ * Emits a {PayeeAdded} event for every payee.
+ *
+ * Requirements:
+ *
+ * - the caller must be the account that deployed this contract.
+ * - the splitter can only be initialized once.
+ * - `payees` and `shares` must have the same non-zero length.
+ * - payees must be unique and not the zero address, shares must be greater than zero.
+ */
+func (c *PaymentSplitter) InitPaymentSplitter(self common.Account, payees []common.Account,
+	shares []*common.SafeUint256) error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		//self由调用者传入，只有部署者才能初始化，避免被抢先初始化成其他地址
+		if err := access.OnlyCreator(c.sdk); err != nil {
+			return err
+		}
+		totalShares, err := c.dal.GetTotalShares()
+		if err != nil {
+			return err
+		}
+		if err = common.Require(totalShares.Equal(common.SafeUintZero),
+			"PaymentSplitter: already initialized"); err != nil {
+			return err
+		}
+		if err = common.Require(len(payees) == len(shares), "PaymentSplitter: payees and shares length mismatch"); err != nil {
+			return err
+		}
+		if err = common.Require(len(payees) > 0, "PaymentSplitter: no payees"); err != nil {
+			return err
+		}
+		if err = common.Require(!self.IsZero(), "PaymentSplitter: the zero address"); err != nil {
+			return err
+		}
+		if err = c.dal.SetSelf(self); err != nil {
+			return err
+		}
+		for i, payee := range payees {
+			if err = c.addPayee(payee, shares[i]); err != nil {
+				return err
+			}
+			var ok bool
+			if totalShares, ok = common.SafeAdd(totalShares, shares[i]); !ok {
+				return errors.New("PaymentSplitter: total shares overflow")
+			}
+		}
+		if err = c.dal.SetPayees(payees); err != nil {
+			return err
+		}
+		return c.dal.SetTotalShares(totalShares)
+	})
+}
+
+func (c *PaymentSplitter) SetSDK(sdk common.ContractSDK) {
+	c.sdk = sdk
+	c.dal = NewPaymentSplitterDAL(sdk)
+}
+
+func (c *PaymentSplitter) TotalShares() (*common.SafeUint256, error) {
+	return c.dal.GetTotalShares()
+}
+
+func (c *PaymentSplitter) Shares(account common.Account) (*common.SafeUint256, error) {
+	return c.dal.GetShares(account)
+}
+
+func (c *PaymentSplitter) Payees() ([]common.Account, error) {
+	return c.dal.GetPayees()
+}
+
+func (c *PaymentSplitter) TotalReleased(token common.Account) (*common.SafeUint256, error) {
+	return c.dal.GetTotalReleased(token)
+}
+
+func (c *PaymentSplitter) Released(token, account common.Account) (*common.SafeUint256, error) {
+	return c.dal.GetReleased(token, account)
+}
+
+func (c *PaymentSplitter) Releasable(token, account common.Account) (*common.SafeUint256, error) {
+	totalReceived, err := c.totalReceived(token)
+	if err != nil {
+		return nil, err
+	}
+	return c.pendingPayment(token, account, totalReceived)
+}
+
+/**
+ * @dev Triggers a transfer to `account` of the amount of `token` they are owed,
+ * according to their percentage of the total shares and their previous withdrawals.
+ * Anyone can trigger the release, the tokens always go to `account`.
+ *
+ * Emits a {PaymentReleased} event.
+ */
+func (c *PaymentSplitter) Release(token, account common.Account) (*common.SafeUint256, error) {
+	var payment *common.SafeUint256
+	err := common.Atomic(c.sdk, c.SetSDK, func() error {
+		shares, err := c.dal.GetShares(account)
+		if err != nil {
+			return err
+		}
+		if err = common.Require(!shares.Equal(common.SafeUintZero), "PaymentSplitter: account has no shares"); err != nil {
+			return err
+		}
+		totalReceived, err := c.totalReceived(token)
+		if err != nil {
+			return err
+		}
+		payment, err = c.pendingPayment(token, account, totalReceived)
+		if err != nil {
+			return err
+		}
+		if err = common.Require(!payment.Equal(common.SafeUintZero), "PaymentSplitter: account is not due payment"); err != nil {
+			return err
+		}
+		//先记录支付的数量再转账，避免被调合约重入时重复支付
+		released, err := c.dal.GetReleased(token, account)
+		if err != nil {
+			return err
+		}
+		released, _ = common.SafeAdd(released, payment)
+		if err = c.dal.SetReleased(token, account, released); err != nil {
+			return err
+		}
+		totalReleased, err := c.dal.GetTotalReleased(token)
+		if err != nil {
+			return err
+		}
+		totalReleased, _ = common.SafeAdd(totalReleased, payment)
+		if err = c.dal.SetTotalReleased(token, totalReleased); err != nil {
+			return err
+		}
+		if err = erc20.NewTokenCaller(c.sdk, token).Transfer(account, payment); err != nil {
+			return err
+		}
+		return c.sdk.EmitEvent("paymentReleased", token.ToString(), account.ToString(), payment.ToString())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+func (c *PaymentSplitter) addPayee(account common.Account, shares *common.SafeUint256) error {
+	if err := common.Require(!account.IsZero(), "PaymentSplitter: account is the zero address"); err != nil {
+		return err
+	}
+	if err := common.Require(shares != nil && !shares.Equal(common.SafeUintZero), "PaymentSplitter: shares are 0"); err != nil {
+		return err
+	}
+	current, err := c.dal.GetShares(account)
+	if err != nil {
+		return err
+	}
+	if err = common.Require(current.Equal(common.SafeUintZero), "PaymentSplitter: account already has shares"); err != nil {
+		return err
+	}
+	if err = c.dal.SetShares(account, shares); err != nil {
+		return err
+	}
+	return c.sdk.EmitEvent("payeeAdded", account.ToString(), shares.ToString())
+}
+
+// totalReceived 本合约累计收到的token数量，等于当前余额加上已经支付出去的数量
+func (c *PaymentSplitter) totalReceived(token common.Account) (*common.SafeUint256, error) {
+	self, err := c.dal.GetSelf()
+	if err != nil {
+		return nil, err
+	}
+	balance, err := erc20.NewTokenCaller(c.sdk, token).BalanceOf(self)
+	if err != nil {
+		return nil, err
+	}
+	totalReleased, err := c.dal.GetTotalReleased(token)
+	if err != nil {
+		return nil, err
+	}
+	total, ok := common.SafeAdd(balance, totalReleased)
+	if !ok {
+		return nil, fmt.Errorf("PaymentSplitter: total received of %s overflow", token.ToString())
+	}
+	return total, nil
+}
+
+// pendingPayment 按份额计算account应得的数量，减去已经支付的部分
+func (c *PaymentSplitter) pendingPayment(token, account common.Account,
+	totalReceived *common.SafeUint256) (*common.SafeUint256, error) {
+	shares, err := c.dal.GetShares(account)
+	if err != nil {
+		return nil, err
+	}
+	totalShares, err := c.dal.GetTotalShares()
+	if err != nil {
+		return nil, err
+	}
+	if totalShares.Equal(common.SafeUintZero) {
+		return common.NewSafeUint256(0), nil
+	}
+	due, ok := common.MulDiv(totalReceived, shares, totalShares, common.RoundDown)
+	if !ok {
+		return nil, errors.New("PaymentSplitter: payment overflow")
+	}
+	released, err := c.dal.GetReleased(token, account)
+	if err != nil {
+		return nil, err
+	}
+	if payment, ok := common.SafeSub(due, released); ok {
+		return payment, nil
+	}
+	return common.NewSafeUint256(0), nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package finance
+
+import (
+	"testing"
+
+	"github.com/studyzy/openzeppelin-go/common"
+	"github.com/studyzy/openzeppelin-go/mock"
+)
+
+func TestPaymentSplitter(t *testing.T) {
+	chain := mock.NewChain()
+	splitterAddr := mock.NewAccount("splitter")
+	chain.SetSender(admin)
+	splitter := NewPaymentSplitter(chain.Deploy(splitterAddr.ToString(), nil))
+	token := newToken(t, chain, splitterAddr, 1000)
+	payees := []common.Account{alice, bob}
+	shares := []*common.SafeUint256{amount(1), amount(3)}
+	//只有部署者可以初始化
+	mustFail(t, chain, bob, func() error {
+		return splitter.InitPaymentSplitter(splitterAddr, payees, shares)
+	}, "Access: caller is not the contract creator")
+	mustFail(t, chain, admin, func() error {
+		return splitter.InitPaymentSplitter(splitterAddr, payees, []*common.SafeUint256{amount(1), nil})
+	}, "PaymentSplitter: shares are 0")
+	mustInvoke(t, chain, admin, func() error {
+		return splitter.InitPaymentSplitter(splitterAddr, payees, shares)
+	})
+	mustFail(t, chain, admin, func() error {
+		return splitter.InitPaymentSplitter(splitterAddr, payees, shares)
+	}, "PaymentSplitter: already initialized")
+	mustInvoke(t, chain, admin, func() error {
+		_, err := token.Transfer(splitterAddr, amount(400))
+		return err
+	})
+	expectAmount(t, 100)(splitter.Releasable(tokenAddr, alice))
+	//任何人都可以触发支付，代币总是转给收款人
+	mustInvoke(t, chain, alice, func() error {
+		_, err := splitter.Release(tokenAddr, bob)
+		return err
+	})
+	expectAmount(t, 300)(token.BalanceOf(bob))
+	mustFail(t, chain, bob, func() error {
+		_, err := splitter.Release(tokenAddr, bob)
+		return err
+	}, "PaymentSplitter: account is not due payment")
+	mustInvoke(t, chain, admin, func() error {
+		_, err := token.Transfer(splitterAddr, amount(400))
+		return err
+	})
+	mustInvoke(t, chain, alice, func() error {
+		_, err := splitter.Release(tokenAddr, alice)
+		return err
+	})
+	expectAmount(t, 200)(token.BalanceOf(alice))
+	expectAmount(t, 500)(splitter.TotalReleased(tokenAddr))
+	mustFail(t, chain, admin, func() error {
+		_, err := splitter.Release(tokenAddr, admin)
+		return err
+	}, "PaymentSplitter: account has no shares")
+}
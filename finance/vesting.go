@@ -0,0 +1,400 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package finance
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/studyzy/openzeppelin-go/access"
+	"github.com/studyzy/openzeppelin-go/common"
+	"github.com/studyzy/openzeppelin-go/erc20"
+)
+
+var _ IVestingWallet = (*VestingWallet)(nil)
+
+// VestingSchedule 一个受益人在一种代币上的释放计划
+type VestingSchedule struct {
+	Id          uint64
+	Beneficiary common.Account
+	// Token 被释放的ERC20代币合约地址
+	Token common.Account
+	// Amount 计划释放的总量，撤销后变为撤销时已经解锁的数量
+	Amount *common.SafeUint256
+	// Released 已经释放给受益人的数量
+	Released *common.SafeUint256
+	// Start 开始解锁的时间戳，单位为秒
+	Start int64
+	// Cliff 从Start开始计算的锁定期，锁定期内解锁数量为0
+	Cliff int64
+	// Duration 从Start开始计算的总解锁时长，之后全部解锁
+	Duration int64
+	// Revocable 管理员是否可以撤销未解锁的部分
+	Revocable bool
+	Revoked   bool
+}
+
+// VestingWallet 代币释放合约，管理员把代币转入合约并创建释放计划，
+// 受益人随着时间推移领取已经解锁的代币，代币通过CallContract调用ERC20合约转出
+type VestingWallet struct {
+	*access.AccessControl
+	dal *VestingWalletDAL
+	sdk common.ContractSDK
+}
+
+// NewVestingWallet VestingWallet
+// @param sdk
+// @return *VestingWallet
+func NewVestingWallet(sdk common.ContractSDK) *VestingWallet {
+	return &VestingWallet{
+		AccessControl: access.NewAccessControl(sdk),
+		dal:           NewVestingWalletDAL(sdk),
+		sdk:           sdk,
+	}
+}
+
+// InitVestingWallet 初始化合约。合约无法通过SDK获得自己的地址，
+// 所以需要传入本合约的地址self，用来接收和持有待释放的代币。只有部署者可以初始化，并且只能初始化一次
+func (c *VestingWallet) InitVestingWallet(self, admin common.Account) error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		if err := access.OnlyCreator(c.sdk); err != nil {
+			return err
+		}
+		current, err := c.dal.GetSelf()
+		if err != nil {
+			return err
+		}
+		if err = common.Require(current.IsZero(), "VestingWallet: already initialized"); err != nil {
+			return err
+		}
+		if self.IsZero() || admin.IsZero() {
+			return errors.New("VestingWallet: the zero address")
+		}
+		if err := c.dal.SetSelf(self); err != nil {
+			return err
+		}
+		if err := c.SetupRole(access.AdminRole, admin); err != nil {
+			return fmt.Errorf("set admin failed, err:%s", err)
+		}
+		return nil
+	})
+}
+
+func (c *VestingWallet) SetSDK(sdk common.ContractSDK) {
+	c.sdk = sdk
+	c.dal = NewVestingWalletDAL(sdk)
+	c.AccessControl = access.NewAccessControl(sdk)
+}
+
+/**
+ * @dev Creates a schedule that vests `amount` of `token` to `beneficiary`: nothing
+ * before `start + cliff`, linearly from `start` until `start + duration`, everything after.
+ * The tokens are pulled from the caller, who must have approved this contract.
+ *
+ * Emits a {VestingScheduleCreated} event.
+ *
+ * Requirements:
+ *
+ * - the caller must have the `AdminRole`.
+ * - `beneficiary` and `token` cannot be the zero address.
+ * - `amount` and `duration` must be greater than zero, `cliff` cannot exceed `duration`.
+ * - `start` cannot be negative and `start + duration` must fit in an int64.
+ */
+func (c *VestingWallet) CreateVestingSchedule(beneficiary, token common.Account, amount *common.SafeUint256,
+	start, cliff, duration int64, revocable bool) (uint64, error) {
+	var id uint64
+	err := common.Atomic(c.sdk, c.SetSDK, func() error {
+		if err := c.OnlyRole(access.AdminRole); err != nil {
+			return err
+		}
+		sender, err := c.sdk.GetTxSender()
+		if err != nil {
+			return fmt.Errorf("Get sender address failed, err:%s", err)
+		}
+		if beneficiary.IsZero() || token.IsZero() {
+			return errors.New("VestingWallet: the zero address")
+		}
+		if err = common.Require(!amount.Equal(common.SafeUintZero), "VestingWallet: amount is 0"); err != nil {
+			return err
+		}
+		if err = common.Require(duration > 0 && cliff >= 0 && cliff <= duration,
+			"VestingWallet: invalid cliff or duration"); err != nil {
+			return err
+		}
+		//cliff不超过duration，所以start+duration不溢出时start+cliff也不会溢出
+		if err = common.Require(start >= 0 && start <= math.MaxInt64-duration,
+			"VestingWallet: start and duration overflow"); err != nil {
+			return err
+		}
+		self, err := c.dal.GetSelf()
+		if err != nil {
+			return err
+		}
+		count, err := c.dal.GetScheduleCount()
+		if err != nil {
+			return err
+		}
+		id = count + 1
+		schedule := &VestingSchedule{
+			Id:          id,
+			Beneficiary: beneficiary,
+			Token:       token,
+			Amount:      amount,
+			Released:    common.NewSafeUint256(0),
+			Start:       start,
+			Cliff:       cliff,
+			Duration:    duration,
+			Revocable:   revocable,
+		}
+		if err = c.dal.SetSchedule(schedule); err != nil {
+			return err
+		}
+		if err = c.dal.SetScheduleCount(id); err != nil {
+			return err
+		}
+		if err = erc20.NewTokenCaller(c.sdk, token).TransferFrom(sender, self, amount); err != nil {
+			return err
+		}
+		return c.sdk.EmitEvent("vestingScheduleCreated", strconv.FormatUint(id, 10), beneficiary.ToString(),
+			token.ToString(), amount.ToString(), strconv.FormatInt(start, 10), strconv.FormatInt(cliff, 10),
+			strconv.FormatInt(duration, 10))
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (c *VestingWallet) Schedule(id uint64) (*VestingSchedule, error) {
+	schedule, err := c.dal.GetSchedule(id)
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil {
+		return nil, errors.New("VestingWallet: nonexistent schedule")
+	}
+	return schedule, nil
+}
+
+func (c *VestingWallet) SchedulesOf(beneficiary common.Account) ([]*VestingSchedule, error) {
+	return c.schedules(beneficiary)
+}
+
+func (c *VestingWallet) VestedAmount(beneficiary, token common.Account, timestamp int64) (*common.SafeUint256, error) {
+	return c.sum(beneficiary, token, func(schedule *VestingSchedule) (*common.SafeUint256, error) {
+		return vestedAmount(schedule, timestamp)
+	})
+}
+
+func (c *VestingWallet) Released(beneficiary, token common.Account) (*common.SafeUint256, error) {
+	return c.sum(beneficiary, token, func(schedule *VestingSchedule) (*common.SafeUint256, error) {
+		return schedule.Released, nil
+	})
+}
+
+func (c *VestingWallet) Releasable(beneficiary, token common.Account) (*common.SafeUint256, error) {
+	now, err := c.sdk.GetTxTimestamp()
+	if err != nil {
+		return nil, err
+	}
+	return c.sum(beneficiary, token, func(schedule *VestingSchedule) (*common.SafeUint256, error) {
+		return releasableAmount(schedule, now)
+	})
+}
+
+/**
+ * @dev Releases to the caller all of its `token` that have already vested,
+ * over all of its schedules for `token`.
+ *
+ * Emits an {ERC20Released} event.
+ */
+func (c *VestingWallet) Release(token common.Account) (*common.SafeUint256, error) {
+	var total *common.SafeUint256
+	err := common.Atomic(c.sdk, c.SetSDK, func() error {
+		beneficiary, err := c.sdk.GetTxSender()
+		if err != nil {
+			return fmt.Errorf("Get sender address failed, err:%s", err)
+		}
+		now, err := c.sdk.GetTxTimestamp()
+		if err != nil {
+			return err
+		}
+		schedules, err := c.schedules(beneficiary, token.ToString())
+		if err != nil {
+			return err
+		}
+		total = common.NewSafeUint256(0)
+		for _, schedule := range schedules {
+			amount, err := releasableAmount(schedule, now)
+			if err != nil {
+				return err
+			}
+			if amount.Equal(common.SafeUintZero) {
+				continue
+			}
+			//先记录已释放的数量再转账，避免被调合约重入时重复释放
+			var ok bool
+			if schedule.Released, ok = common.SafeAdd(schedule.Released, amount); !ok {
+				return errors.New("VestingWallet: released amount overflow")
+			}
+			if err = c.dal.SetSchedule(schedule); err != nil {
+				return err
+			}
+			total, _ = common.SafeAdd(total, amount)
+		}
+		if err = common.Require(!total.Equal(common.SafeUintZero), "VestingWallet: no tokens are due"); err != nil {
+			return err
+		}
+		if err = erc20.NewTokenCaller(c.sdk, token).Transfer(beneficiary, total); err != nil {
+			return err
+		}
+		return c.sdk.EmitEvent("erc20Released", token.ToString(), beneficiary.ToString(), total.ToString())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return total, nil
+}
+
+/**
+ * @dev Revokes the schedule `id`. The amount vested so far stays releasable by the
+ * beneficiary, the unvested rest is returned to the caller.
+ *
+ * Emits a {VestingScheduleRevoked} event.
+ *
+ * Requirements:
+ *
+ * - the caller must have the `AdminRole`.
+ * - the schedule must be revocable and not revoked yet.
+ */
+func (c *VestingWallet) Revoke(id uint64) (*common.SafeUint256, error) {
+	var unvested *common.SafeUint256
+	err := common.Atomic(c.sdk, c.SetSDK, func() error {
+		if err := c.OnlyRole(access.AdminRole); err != nil {
+			return err
+		}
+		sender, err := c.sdk.GetTxSender()
+		if err != nil {
+			return fmt.Errorf("Get sender address failed, err:%s", err)
+		}
+		schedule, err := c.Schedule(id)
+		if err != nil {
+			return err
+		}
+		if err = common.Require(schedule.Revocable, "VestingWallet: schedule is not revocable"); err != nil {
+			return err
+		}
+		if err = common.Require(!schedule.Revoked, "VestingWallet: schedule already revoked"); err != nil {
+			return err
+		}
+		now, err := c.sdk.GetTxTimestamp()
+		if err != nil {
+			return err
+		}
+		vested, err := vestedAmount(schedule, now)
+		if err != nil {
+			return err
+		}
+		//撤销后计划总量变为已解锁的数量，受益人之后仍然可以领取这部分
+		//vested可能就是schedule.Amount，先复制一份再相减
+		unvested, _ = common.SafeAdd(schedule.Amount, common.SafeUintZero)
+		unvested, _ = common.SafeSub(unvested, vested)
+		schedule.Amount = vested
+		schedule.Revoked = true
+		if err = c.dal.SetSchedule(schedule); err != nil {
+			return err
+		}
+		if !unvested.Equal(common.SafeUintZero) {
+			if err = erc20.NewTokenCaller(c.sdk, schedule.Token).Transfer(sender, unvested); err != nil {
+				return err
+			}
+		}
+		return c.sdk.EmitEvent("vestingScheduleRevoked", strconv.FormatUint(id, 10), unvested.ToString())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return unvested, nil
+}
+
+// schedules 获得beneficiary的释放计划，data可以再指定代币地址
+func (c *VestingWallet) schedules(beneficiary common.Account, data ...string) ([]*VestingSchedule, error) {
+	ids, err := c.dal.GetScheduleIds(beneficiary, data...)
+	if err != nil {
+		return nil, err
+	}
+	schedules := make([]*VestingSchedule, 0, len(ids))
+	for _, id := range ids {
+		schedule, err := c.Schedule(id)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, nil
+}
+
+// sum 对beneficiary在token上的所有计划求和
+func (c *VestingWallet) sum(beneficiary, token common.Account,
+	amountOf func(schedule *VestingSchedule) (*common.SafeUint256, error)) (*common.SafeUint256, error) {
+	schedules, err := c.schedules(beneficiary, token.ToString())
+	if err != nil {
+		return nil, err
+	}
+	total := common.NewSafeUint256(0)
+	for _, schedule := range schedules {
+		amount, err := amountOf(schedule)
+		if err != nil {
+			return nil, err
+		}
+		var ok bool
+		if total, ok = common.SafeAdd(total, amount); !ok {
+			return nil, errors.New("VestingWallet: amount overflow")
+		}
+	}
+	return total, nil
+}
+
+// vestedAmount 计算schedule在timestamp时已经解锁的数量：锁定期内为0，之后按时间线性解锁
+func vestedAmount(schedule *VestingSchedule, timestamp int64) (*common.SafeUint256, error) {
+	if schedule.Revoked || timestamp >= schedule.Start+schedule.Duration {
+		return schedule.Amount, nil
+	}
+	if timestamp < schedule.Start+schedule.Cliff {
+		return common.NewSafeUint256(0), nil
+	}
+	vested, ok := common.MulDiv(schedule.Amount, common.NewSafeUint256(uint64(timestamp-schedule.Start)),
+		common.NewSafeUint256(uint64(schedule.Duration)), common.RoundDown)
+	if !ok {
+		return nil, errors.New("VestingWallet: vested amount overflow")
+	}
+	return vested, nil
+}
+
+// releasableAmount 计算schedule在timestamp时可以领取的数量
+func releasableAmount(schedule *VestingSchedule, timestamp int64) (*common.SafeUint256, error) {
+	vested, err := vestedAmount(schedule, timestamp)
+	if err != nil {
+		return nil, err
+	}
+	//vested可能就是schedule.Amount，用SafeAdd复制一份，避免SafeSub修改计划中的数据
+	releasable, _ := common.SafeAdd(vested, common.SafeUintZero)
+	if releasable, ok := common.SafeSub(releasable, schedule.Released); ok {
+		return releasable, nil
+	}
+	return common.NewSafeUint256(0), nil
+}
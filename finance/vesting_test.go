@@ -0,0 +1,121 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package finance
+
+import (
+	"math"
+	"testing"
+
+	"github.com/studyzy/openzeppelin-go/mock"
+)
+
+const genesis = 1672531200
+
+func newVestingWallet(t *testing.T) (*mock.Chain, *VestingWallet) {
+	t.Helper()
+	chain := mock.NewChain()
+	chain.SetSender(admin)
+	wallet := NewVestingWallet(chain.Deploy("vesting", nil))
+	//只有部署者可以初始化，并且只能初始化一次
+	mustFail(t, chain, alice, func() error {
+		return wallet.InitVestingWallet(mock.NewAccount("vesting"), alice)
+	}, "Access: caller is not the contract creator")
+	mustInvoke(t, chain, admin, func() error {
+		return wallet.InitVestingWallet(mock.NewAccount("vesting"), admin)
+	})
+	mustFail(t, chain, admin, func() error {
+		return wallet.InitVestingWallet(mock.NewAccount("vesting"), alice)
+	}, "VestingWallet: already initialized")
+	return chain, wallet
+}
+
+func TestVestingRelease(t *testing.T) {
+	chain, wallet := newVestingWallet(t)
+	token := newToken(t, chain, mock.NewAccount("vesting"), 1000)
+	mustInvoke(t, chain, admin, func() error {
+		_, err := wallet.CreateVestingSchedule(alice, tokenAddr, amount(1000), genesis, 100, 1000, true)
+		return err
+	})
+	expectAmount(t, 1000)(token.BalanceOf(mock.NewAccount("vesting")))
+	//锁定期内没有可以领取的代币
+	chain.Mine(1, 99)
+	mustFail(t, chain, alice, func() error {
+		_, err := wallet.Release(tokenAddr)
+		return err
+	}, "VestingWallet: no tokens are due")
+	chain.Mine(1, 401)
+	mustInvoke(t, chain, alice, func() error {
+		_, err := wallet.Release(tokenAddr)
+		return err
+	})
+	expectAmount(t, 500)(token.BalanceOf(alice))
+	//转账失败时已释放的数量一起回滚
+	mustInvoke(t, chain, admin, func() error {
+		return token.Freeze(alice)
+	})
+	chain.Mine(1, 100)
+	mustFail(t, chain, alice, func() error {
+		_, err := wallet.Release(tokenAddr)
+		return err
+	}, "is frozen")
+	expectAmount(t, 500)(wallet.Released(alice, tokenAddr))
+	expectAmount(t, 100)(wallet.Releasable(alice, tokenAddr))
+	mustInvoke(t, chain, admin, func() error {
+		return token.Unfreeze(alice)
+	})
+	//撤销后未解锁的部分退回给管理员，已解锁的部分仍然可以领取
+	mustFail(t, chain, bob, func() error {
+		_, err := wallet.Revoke(1)
+		return err
+	}, "is missing role")
+	chain.Mine(1, 150)
+	mustInvoke(t, chain, admin, func() error {
+		_, err := wallet.Revoke(1)
+		return err
+	})
+	expectAmount(t, 250)(token.BalanceOf(admin))
+	chain.Mine(1, 1000)
+	mustInvoke(t, chain, alice, func() error {
+		_, err := wallet.Release(tokenAddr)
+		return err
+	})
+	expectAmount(t, 750)(token.BalanceOf(alice))
+	mustFail(t, chain, admin, func() error {
+		_, err := wallet.Revoke(1)
+		return err
+	}, "VestingWallet: schedule already revoked")
+}
+
+func TestVestingScheduleValidation(t *testing.T) {
+	chain, wallet := newVestingWallet(t)
+	newToken(t, chain, mock.NewAccount("vesting"), 1000)
+	mustFail(t, chain, admin, func() error {
+		_, err := wallet.CreateVestingSchedule(alice, tokenAddr, amount(100), genesis, 200, 100, false)
+		return err
+	}, "VestingWallet: invalid cliff or duration")
+	mustFail(t, chain, admin, func() error {
+		_, err := wallet.CreateVestingSchedule(alice, tokenAddr, amount(100), math.MaxInt64-10, 0, 100, false)
+		return err
+	}, "VestingWallet: start and duration overflow")
+	mustFail(t, chain, admin, func() error {
+		_, err := wallet.CreateVestingSchedule(alice, tokenAddr, amount(100), -1, 0, 100, false)
+		return err
+	}, "VestingWallet: start and duration overflow")
+	mustInvoke(t, chain, admin, func() error {
+		_, err := wallet.CreateVestingSchedule(alice, tokenAddr, amount(100), math.MaxInt64-100, 0, 100, false)
+		return err
+	})
+	expectAmount(t, 100)(wallet.VestedAmount(alice, tokenAddr, math.MaxInt64))
+}
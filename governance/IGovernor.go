@@ -0,0 +1,108 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package governance
+
+import "github.com/studyzy/openzeppelin-go/common"
+
+/**
+ * @dev Interface of the {Governor} core.
+ */
+type IGovernor interface {
+	/**
+	 * @dev Name of the governor instance (used in building the ERC712 domain separator).
+	 */
+	Name() (string, error)
+
+	/**
+	 * @dev Hashing function used to (re)build the proposal id from the proposal details.
+	 */
+	HashProposal(calls []Call, description string) (string, error)
+
+	/**
+	 * @dev Current state of a proposal, following Compound's convention.
+	 */
+	State(proposalId string) (ProposalState, error)
+
+	/**
+	 * @dev Clock used for flagging checkpoints, as specified in ERC-6372.
+	 */
+	Clock() (uint64, error)
+
+	/**
+	 * @dev Description of the clock, as specified in ERC-6372.
+	 */
+	ClockMode() (string, error)
+
+	/**
+	 * @dev Timepoint used to retrieve user's votes and quorum. If using block number (as per Compound's Comp),
+	 * the snapshot is performed at the end of this block. Hence, voting for this proposal starts at the beginning
+	 * of the following block.
+	 */
+	ProposalSnapshot(proposalId string) (uint64, error)
+
+	/**
+	 * @dev Timepoint at which votes close. If using block number, votes close at the end of this block, so it is
+	 * possible to cast a vote during this block.
+	 */
+	ProposalDeadline(proposalId string) (uint64, error)
+
+	/**
+	 * @dev Delay, in units of the token's clock, between the proposal is created and the vote starts.
+	 */
+	VotingDelay() (uint64, error)
+
+	/**
+	 * @dev Delay, in units of the token's clock, between the vote start and vote ends.
+	 */
+	VotingPeriod() (uint64, error)
+
+	/**
+	 * @dev Minimum number of cast voted required for a proposal to be successful.
+	 */
+	Quorum(blockNumber uint64) (*common.SafeUint256, error)
+
+	/**
+	 * @dev Voting power of an `account` at a specific `blockNumber`.
+	 */
+	GetVotes(account common.Account, blockNumber uint64) (*common.SafeUint256, error)
+
+	/**
+	 * @dev Returns whether `account` has cast a vote on `proposalId`.
+	 */
+	HasVoted(proposalId string, account common.Account) (bool, error)
+
+	/**
+	 * @dev Create a new proposal. Vote start after a delay specified by {VotingDelay} and lasts for a
+	 * duration specified by {VotingPeriod}.
+	 *
+	 * Emits a {ProposalCreated} event.
+	 */
+	Propose(calls []Call, description string) (string, error)
+
+	/**
+	 * @dev Execute a successful proposal. This requires the quorum to be reached, the vote to be successful, and the
+	 * deadline to be reached.
+	 *
+	 * Emits a {ProposalExecuted} event.
+	 */
+	Execute(proposalId string) error
+
+	/**
+	 * @dev Cast a vote with a reason.
+	 *
+	 * Emits a {VoteCast} event.
+	 */
+	CastVote(proposalId string, support VoteType, reason string) (*common.SafeUint256, error)
+}
@@ -0,0 +1,245 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package governance
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/studyzy/openzeppelin-go/common"
+)
+
+const (
+	nameKey              = "name"
+	selfKey              = "self"
+	tokenKey             = "token"
+	votingDelayKey       = "votingDelay"
+	votingPeriodKey      = "votingPeriod"
+	proposalThresholdKey = "proposalThreshold"
+	quorumNumeratorKey   = "quorumNumerator"
+	proposalKey          = "proposal"
+	proposalVoteKey      = "proposalVote"
+	hasVotedKey          = "hasVoted"
+)
+
+type GovernorDAL struct {
+	sdk common.StateOperator
+}
+
+func NewGovernorDAL(sdk common.StateOperator) *GovernorDAL {
+	return &GovernorDAL{sdk: sdk}
+}
+
+// proposalRecord 提案在状态数据库中的存储格式
+type proposalRecord struct {
+	Proposer    string
+	Calls       []callRecord
+	Description string
+	VoteStart   uint64
+	VoteEnd     uint64
+	Executed    bool
+	Canceled    bool
+}
+
+// callRecord 提案中一次合约调用的存储格式，也用于计算提案id
+type callRecord struct {
+	Target string
+	Method string
+	Args   []common.KeyValue
+}
+
+// ProposalVote 提案的计票结果
+type ProposalVote struct {
+	AgainstVotes *common.SafeUint256
+	ForVotes     *common.SafeUint256
+	AbstainVotes *common.SafeUint256
+}
+
+type proposalVoteRecord struct {
+	AgainstVotes string
+	ForVotes     string
+	AbstainVotes string
+}
+
+func (c *GovernorDAL) GetName() (string, error) {
+	b, err := c.sdk.GetState(nameKey)
+	return string(b), err
+}
+func (c *GovernorDAL) SetName(name string) error {
+	return c.sdk.PutState(nameKey, []byte(name))
+}
+
+// GetSelf 获得初始化时记录的本合约地址，没有设置时返回零地址
+func (c *GovernorDAL) GetSelf() (common.Account, error) {
+	return c.getAccount(selfKey)
+}
+func (c *GovernorDAL) SetSelf(self common.Account) error {
+	return c.sdk.PutState(selfKey, []byte(self.ToString()))
+}
+
+// GetToken 获得提供投票权的代币合约地址
+func (c *GovernorDAL) GetToken() (common.Account, error) {
+	return c.getAccount(tokenKey)
+}
+func (c *GovernorDAL) SetToken(token common.Account) error {
+	return c.sdk.PutState(tokenKey, []byte(token.ToString()))
+}
+
+func (c *GovernorDAL) GetVotingDelay() (uint64, error) {
+	return c.getUint64(votingDelayKey)
+}
+func (c *GovernorDAL) SetVotingDelay(delay uint64) error {
+	return c.sdk.PutState(votingDelayKey, []byte(strconv.FormatUint(delay, 10)))
+}
+
+func (c *GovernorDAL) GetVotingPeriod() (uint64, error) {
+	return c.getUint64(votingPeriodKey)
+}
+func (c *GovernorDAL) SetVotingPeriod(period uint64) error {
+	return c.sdk.PutState(votingPeriodKey, []byte(strconv.FormatUint(period, 10)))
+}
+
+func (c *GovernorDAL) GetProposalThreshold() (*common.SafeUint256, error) {
+	return c.getUint256(proposalThresholdKey)
+}
+func (c *GovernorDAL) SetProposalThreshold(threshold *common.SafeUint256) error {
+	return c.sdk.PutState(proposalThresholdKey, []byte(threshold.ToString()))
+}
+
+func (c *GovernorDAL) GetQuorumNumerator() (uint64, error) {
+	return c.getUint64(quorumNumeratorKey)
+}
+func (c *GovernorDAL) SetQuorumNumerator(numerator uint64) error {
+	return c.sdk.PutState(quorumNumeratorKey, []byte(strconv.FormatUint(numerator, 10)))
+}
+
+// GetProposal 获得提案，提案不存在时返回nil
+func (c *GovernorDAL) GetProposal(proposalId string) (*proposalRecord, error) {
+	key, err := c.sdk.CreateCompositeKey(proposalKey, proposalId)
+	if err != nil {
+		return nil, err
+	}
+	b, err := c.sdk.GetState(key)
+	if err != nil || len(b) == 0 {
+		return nil, err
+	}
+	proposal := &proposalRecord{}
+	if err = json.Unmarshal(b, proposal); err != nil {
+		return nil, err
+	}
+	return proposal, nil
+}
+func (c *GovernorDAL) SetProposal(proposalId string, proposal *proposalRecord) error {
+	key, err := c.sdk.CreateCompositeKey(proposalKey, proposalId)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(proposal)
+	if err != nil {
+		return err
+	}
+	return c.sdk.PutState(key, b)
+}
+
+// GetProposalVote 获得提案的计票结果，还没有投票时各项都为0
+func (c *GovernorDAL) GetProposalVote(proposalId string) (*ProposalVote, error) {
+	key, err := c.sdk.CreateCompositeKey(proposalVoteKey, proposalId)
+	if err != nil {
+		return nil, err
+	}
+	b, err := c.sdk.GetState(key)
+	if err != nil {
+		return nil, err
+	}
+	record := proposalVoteRecord{}
+	if len(b) > 0 {
+		if err = json.Unmarshal(b, &record); err != nil {
+			return nil, err
+		}
+	}
+	against, ok1 := common.ParseSafeUint256(record.AgainstVotes)
+	forVotes, ok2 := common.ParseSafeUint256(record.ForVotes)
+	abstain, ok3 := common.ParseSafeUint256(record.AbstainVotes)
+	if !ok1 || !ok2 || !ok3 {
+		return nil, errors.New("invalid uint256 data")
+	}
+	return &ProposalVote{AgainstVotes: against, ForVotes: forVotes, AbstainVotes: abstain}, nil
+}
+func (c *GovernorDAL) SetProposalVote(proposalId string, vote *ProposalVote) error {
+	key, err := c.sdk.CreateCompositeKey(proposalVoteKey, proposalId)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(proposalVoteRecord{
+		AgainstVotes: vote.AgainstVotes.ToString(),
+		ForVotes:     vote.ForVotes.ToString(),
+		AbstainVotes: vote.AbstainVotes.ToString(),
+	})
+	if err != nil {
+		return err
+	}
+	return c.sdk.PutState(key, b)
+}
+
+func (c *GovernorDAL) HasVoted(proposalId string, account common.Account) (bool, error) {
+	key, err := c.sdk.CreateCompositeKey(hasVotedKey, proposalId, account.ToString())
+	if err != nil {
+		return false, err
+	}
+	b, err := c.sdk.GetState(key)
+	if err != nil {
+		return false, err
+	}
+	return len(b) > 0, nil
+}
+func (c *GovernorDAL) SetVoted(proposalId string, account common.Account) error {
+	key, err := c.sdk.CreateCompositeKey(hasVotedKey, proposalId, account.ToString())
+	if err != nil {
+		return err
+	}
+	return c.sdk.PutState(key, []byte("true"))
+}
+
+func (c *GovernorDAL) getAccount(key string) (common.Account, error) {
+	b, err := c.sdk.GetState(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return c.sdk.NewZeroAccount(), nil
+	}
+	return c.sdk.NewAccountFromString(string(b))
+}
+
+func (c *GovernorDAL) getUint64(key string) (uint64, error) {
+	b, err := c.sdk.GetState(key)
+	if err != nil || len(b) == 0 {
+		return 0, err
+	}
+	return strconv.ParseUint(string(b), 10, 64)
+}
+
+func (c *GovernorDAL) getUint256(key string) (*common.SafeUint256, error) {
+	b, err := c.sdk.GetState(key)
+	if err != nil {
+		return nil, err
+	}
+	num, ok := common.ParseSafeUint256(string(b))
+	if !ok {
+		return nil, errors.New("invalid uint256 data")
+	}
+	return num, nil
+}
@@ -0,0 +1,756 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package governance
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/studyzy/openzeppelin-go/access"
+	"github.com/studyzy/openzeppelin-go/common"
+	"github.com/studyzy/openzeppelin-go/erc20"
+)
+
+var _ IGovernor = (*Governor)(nil)
+
+// quorumDenominator 法定人数按总发行量的百分比计算
+const quorumDenominator = 100
+
+const proposalType = "Proposal(bytes calls,bytes32 descriptionHash)"
+
+// ProposalState 提案的状态
+type ProposalState int
+
+const (
+	// Pending 已经创建，还没有开始投票
+	Pending ProposalState = iota
+	// Active 投票中
+	Active
+	// Canceled 已经被提案人取消
+	Canceled
+	// Defeated 投票结束，没有达到法定人数或者反对票不少于赞成票
+	Defeated
+	// Succeeded 投票结束并且通过，可以执行
+	Succeeded
+	// Executed 已经执行
+	Executed
+)
+
+// VoteType 投票选项，与GovernorCountingSimple相同
+type VoteType uint8
+
+const (
+	VoteAgainst VoteType = iota
+	VoteFor
+	VoteAbstain
+)
+
+// Call 提案通过后要执行的一次合约调用
+type Call struct {
+	Target common.Account
+	Method string
+	Args   []common.KeyValue
+}
+
+/**
+ * @dev Core of the governance system, with simple for/against/abstain vote counting,
+ * voting weight extracted from an {ERC20Votes} token and a quorum expressed as a
+ * fraction of the token's past total supply.
+ *
+ * 投票权和总发行量通过CallContract调用代币合约的getPastVotes和getPastTotalSupply获得，
+ * 时间点使用代币合约的clock，可能是区块高度也可能是时间戳。提案通过后由Governor合约逐个调用目标合约，目标合约看到的调用者就是本合约。
+ */
+type Governor struct {
+	dal *GovernorDAL
+	sdk common.ContractSDK
+}
+
+// NewGovernor Governor
+// @param sdk
+// @return *Governor
+func NewGovernor(sdk common.ContractSDK) *Governor {
+	return &Governor{
+		dal: NewGovernorDAL(sdk),
+		sdk: sdk,
+	}
+}
+
+/**
+ * @dev Initializes the governor. `self` is the address of this contract, the only caller
+ * allowed to change the settings, through a successful proposal. `token` is the votes-capable
+ * token, `votingDelay` and `votingPeriod` are in units of the token's clock and `quorumNumerator`
+ * is a percentage of the past total supply.
+ *
+ * Requirements:
+ *
+ * - the caller must be the account that deployed this contract.
+ * - the governor can only be initialized once.
+ */
+func (c *Governor) InitGovernor(name string, self, token common.Account, votingDelay, votingPeriod uint64,
+	proposalThreshold *common.SafeUint256, quorumNumerator uint64) error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		if err := access.OnlyCreator(c.sdk); err != nil {
+			return err
+		}
+		current, err := c.dal.GetSelf()
+		if err != nil {
+			return err
+		}
+		if err = common.Require(current.IsZero(), "Governor: already initialized"); err != nil {
+			return err
+		}
+		if self.IsZero() || token.IsZero() {
+			return errors.New("Governor: the zero address")
+		}
+		if err = c.dal.SetName(name); err != nil {
+			return err
+		}
+		if err = c.dal.SetSelf(self); err != nil {
+			return err
+		}
+		if err = c.dal.SetToken(token); err != nil {
+			return err
+		}
+		if err = c.setVotingDelay(votingDelay); err != nil {
+			return err
+		}
+		if err = c.setVotingPeriod(votingPeriod); err != nil {
+			return err
+		}
+		if err = c.setProposalThreshold(proposalThreshold); err != nil {
+			return err
+		}
+		return c.updateQuorumNumerator(quorumNumerator)
+	})
+}
+
+func (c *Governor) SetSDK(sdk common.ContractSDK) {
+	c.sdk = sdk
+	c.dal = NewGovernorDAL(sdk)
+}
+
+func (c *Governor) Name() (string, error) {
+	return c.dal.GetName()
+}
+
+// Token 返回提供投票权的代币合约地址
+func (c *Governor) Token() (common.Account, error) {
+	return c.dal.GetToken()
+}
+
+/**
+ * @dev Clock used for flagging checkpoints, read from the token so that proposal snapshots
+ * and deadlines use the same timepoints as its votes.
+ */
+func (c *Governor) Clock() (uint64, error) {
+	token, err := c.token()
+	if err != nil {
+		return 0, err
+	}
+	return token.Clock()
+}
+
+/**
+ * @dev Machine-readable description of the clock as specified in ERC-6372, read from the token.
+ */
+func (c *Governor) ClockMode() (string, error) {
+	token, err := c.token()
+	if err != nil {
+		return "", err
+	}
+	return token.ClockMode()
+}
+
+func (c *Governor) VotingDelay() (uint64, error) {
+	return c.dal.GetVotingDelay()
+}
+
+func (c *Governor) VotingPeriod() (uint64, error) {
+	return c.dal.GetVotingPeriod()
+}
+
+/**
+ * @dev Part of the Governor Bravo's interface: _"The number of votes required in order for a voter to become a proposer"_.
+ */
+func (c *Governor) ProposalThreshold() (*common.SafeUint256, error) {
+	return c.dal.GetProposalThreshold()
+}
+
+/**
+ * @dev Returns the current quorum numerator. See {QuorumDenominator}.
+ */
+func (c *Governor) QuorumNumerator() (uint64, error) {
+	return c.dal.GetQuorumNumerator()
+}
+
+/**
+ * @dev Returns the quorum denominator. Defaults to 100.
+ */
+func (c *Governor) QuorumDenominator() uint64 {
+	return quorumDenominator
+}
+
+/**
+ * @dev Returns the quorum for a block number, in terms of number of votes: `supply * numerator / denominator`.
+ */
+func (c *Governor) Quorum(blockNumber uint64) (*common.SafeUint256, error) {
+	token, err := c.token()
+	if err != nil {
+		return nil, err
+	}
+	supply, err := token.GetPastTotalSupply(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	numerator, err := c.dal.GetQuorumNumerator()
+	if err != nil {
+		return nil, err
+	}
+	quorum, ok := common.MulDiv(supply, common.NewSafeUint256(numerator), common.NewSafeUint256(quorumDenominator),
+		common.RoundDown)
+	if !ok {
+		return nil, errors.New("Governor: quorum overflow")
+	}
+	return quorum, nil
+}
+
+func (c *Governor) GetVotes(account common.Account, blockNumber uint64) (*common.SafeUint256, error) {
+	token, err := c.token()
+	if err != nil {
+		return nil, err
+	}
+	return token.GetPastVotes(account, blockNumber)
+}
+
+func (c *Governor) HasVoted(proposalId string, account common.Account) (bool, error) {
+	return c.dal.HasVoted(proposalId, account)
+}
+
+/**
+ * @dev Accessor to the internal vote counts.
+ */
+func (c *Governor) ProposalVotes(proposalId string) (*ProposalVote, error) {
+	return c.dal.GetProposalVote(proposalId)
+}
+
+func (c *Governor) HashProposal(calls []Call, description string) (string, error) {
+	encodedCalls, err := encodeCalls(calls)
+	if err != nil {
+		return "", err
+	}
+	descriptionHash := common.HashStruct("string", description)
+	return hex.EncodeToString(common.HashStruct(proposalType, string(encodedCalls), string(descriptionHash))), nil
+}
+
+func (c *Governor) State(proposalId string) (ProposalState, error) {
+	proposal, err := c.proposal(proposalId)
+	if err != nil {
+		return Pending, err
+	}
+	if proposal.Executed {
+		return Executed, nil
+	}
+	if proposal.Canceled {
+		return Canceled, nil
+	}
+	current, err := c.Clock()
+	if err != nil {
+		return Pending, err
+	}
+	if proposal.VoteStart >= current {
+		return Pending, nil
+	}
+	if proposal.VoteEnd >= current {
+		return Active, nil
+	}
+	quorumReached, err := c.quorumReached(proposalId, proposal.VoteStart)
+	if err != nil {
+		return Pending, err
+	}
+	voteSucceeded, err := c.voteSucceeded(proposalId)
+	if err != nil {
+		return Pending, err
+	}
+	if quorumReached && voteSucceeded {
+		return Succeeded, nil
+	}
+	return Defeated, nil
+}
+
+func (c *Governor) ProposalSnapshot(proposalId string) (uint64, error) {
+	proposal, err := c.proposal(proposalId)
+	if err != nil {
+		return 0, err
+	}
+	return proposal.VoteStart, nil
+}
+
+func (c *Governor) ProposalDeadline(proposalId string) (uint64, error) {
+	proposal, err := c.proposal(proposalId)
+	if err != nil {
+		return 0, err
+	}
+	return proposal.VoteEnd, nil
+}
+
+/**
+ * @dev Returns the account that created a given proposal.
+ */
+func (c *Governor) ProposalProposer(proposalId string) (common.Account, error) {
+	proposal, err := c.proposal(proposalId)
+	if err != nil {
+		return nil, err
+	}
+	return c.sdk.NewAccountFromString(proposal.Proposer)
+}
+
+/**
+ * @dev Returns the calls of a given proposal.
+ */
+func (c *Governor) ProposalCalls(proposalId string) ([]Call, error) {
+	proposal, err := c.proposal(proposalId)
+	if err != nil {
+		return nil, err
+	}
+	return c.decodeCalls(proposal.Calls)
+}
+
+/**
+ * @dev See {IGovernor-propose}. The caller must have at least {ProposalThreshold} votes
+ * at the previous timepoint of the token's clock.
+ */
+func (c *Governor) Propose(calls []Call, description string) (string, error) {
+	var proposalId string
+	err := common.Atomic(c.sdk, c.SetSDK, func() error {
+		proposer, err := c.sdk.GetTxSender()
+		if err != nil {
+			return fmt.Errorf("Get sender address failed, err:%s", err)
+		}
+		current, err := c.Clock()
+		if err != nil {
+			return err
+		}
+		threshold, err := c.dal.GetProposalThreshold()
+		if err != nil {
+			return err
+		}
+		if !threshold.Equal(common.SafeUintZero) {
+			if err = common.Require(current > 0, "Governor: proposer votes below proposal threshold"); err != nil {
+				return err
+			}
+			votes, err := c.GetVotes(proposer, current-1)
+			if err != nil {
+				return err
+			}
+			if err = common.Require(votes.GTE(threshold),
+				"Governor: proposer votes below proposal threshold"); err != nil {
+				return err
+			}
+		}
+		if err = common.Require(len(calls) > 0, "Governor: empty proposal"); err != nil {
+			return err
+		}
+		if proposalId, err = c.HashProposal(calls, description); err != nil {
+			return err
+		}
+		existing, err := c.dal.GetProposal(proposalId)
+		if err != nil {
+			return err
+		}
+		if err = common.Require(existing == nil, "Governor: proposal already exists"); err != nil {
+			return err
+		}
+		votingDelay, err := c.dal.GetVotingDelay()
+		if err != nil {
+			return err
+		}
+		votingPeriod, err := c.dal.GetVotingPeriod()
+		if err != nil {
+			return err
+		}
+		proposal := &proposalRecord{
+			Proposer:    proposer.ToString(),
+			Calls:       encodeCallRecords(calls),
+			Description: description,
+			VoteStart:   current + votingDelay,
+			VoteEnd:     current + votingDelay + votingPeriod,
+		}
+		if err = c.dal.SetProposal(proposalId, proposal); err != nil {
+			return err
+		}
+		encodedCalls, err := encodeCalls(calls)
+		if err != nil {
+			return err
+		}
+		return c.sdk.EmitEvent("proposalCreated", proposalId, proposer.ToString(), string(encodedCalls),
+			strconv.FormatUint(proposal.VoteStart, 10), strconv.FormatUint(proposal.VoteEnd, 10), description)
+	})
+	if err != nil {
+		return "", err
+	}
+	return proposalId, nil
+}
+
+/**
+ * @dev See {IGovernor-execute}. Anyone can execute a successful proposal, its calls
+ * are made in order and the whole execution fails if any of them fails.
+ */
+func (c *Governor) Execute(proposalId string) error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		state, err := c.State(proposalId)
+		if err != nil {
+			return err
+		}
+		if err = common.Require(state == Succeeded, "Governor: proposal not successful"); err != nil {
+			return err
+		}
+		proposal, err := c.proposal(proposalId)
+		if err != nil {
+			return err
+		}
+		//先标记为已执行再调用目标合约，避免目标合约重入时重复执行
+		proposal.Executed = true
+		if err = c.dal.SetProposal(proposalId, proposal); err != nil {
+			return err
+		}
+		calls, err := c.decodeCalls(proposal.Calls)
+		if err != nil {
+			return err
+		}
+		for _, call := range calls {
+			response := c.sdk.CallContract(call.Target, call.Method, call.Args)
+			if response.Status != common.OK {
+				return fmt.Errorf("Governor: call %s.%s reverted, err:%s", call.Target.ToString(), call.Method,
+					response.Message)
+			}
+		}
+		return c.sdk.EmitEvent("proposalExecuted", proposalId)
+	})
+}
+
+/**
+ * @dev Cancel a proposal. A proposal is cancellable by the proposer, but only while it is
+ * Pending state, i.e. before the vote starts.
+ *
+ * Emits a {ProposalCanceled} event.
+ */
+func (c *Governor) Cancel(proposalId string) error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		sender, err := c.sdk.GetTxSender()
+		if err != nil {
+			return fmt.Errorf("Get sender address failed, err:%s", err)
+		}
+		proposal, err := c.proposal(proposalId)
+		if err != nil {
+			return err
+		}
+		if err = common.Require(proposal.Proposer == sender.ToString(), "Governor: only proposer can cancel"); err != nil {
+			return err
+		}
+		state, err := c.State(proposalId)
+		if err != nil {
+			return err
+		}
+		if err = common.Require(state == Pending, "Governor: too late to cancel"); err != nil {
+			return err
+		}
+		proposal.Canceled = true
+		if err = c.dal.SetProposal(proposalId, proposal); err != nil {
+			return err
+		}
+		return c.sdk.EmitEvent("proposalCanceled", proposalId)
+	})
+}
+
+/**
+ * @dev See {IGovernor-castVote}. The weight is the voter's votes at the proposal snapshot.
+ */
+func (c *Governor) CastVote(proposalId string, support VoteType, reason string) (*common.SafeUint256, error) {
+	var weight *common.SafeUint256
+	err := common.Atomic(c.sdk, c.SetSDK, func() error {
+		voter, err := c.sdk.GetTxSender()
+		if err != nil {
+			return fmt.Errorf("Get sender address failed, err:%s", err)
+		}
+		state, err := c.State(proposalId)
+		if err != nil {
+			return err
+		}
+		if err = common.Require(state == Active, "Governor: vote not currently active"); err != nil {
+			return err
+		}
+		proposal, err := c.proposal(proposalId)
+		if err != nil {
+			return err
+		}
+		if weight, err = c.GetVotes(voter, proposal.VoteStart); err != nil {
+			return err
+		}
+		if err = c.countVote(proposalId, voter, support, weight); err != nil {
+			return err
+		}
+		return c.sdk.EmitEvent("voteCast", voter.ToString(), proposalId, strconv.Itoa(int(support)),
+			weight.ToString(), reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return weight, nil
+}
+
+/**
+ * @dev Update the voting delay. This operation can only be performed through a governance proposal.
+ *
+ * Emits a {VotingDelaySet} event.
+ */
+func (c *Governor) SetVotingDelay(newVotingDelay uint64) error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		if err := c.onlyGovernance(); err != nil {
+			return err
+		}
+		return c.setVotingDelay(newVotingDelay)
+	})
+}
+
+/**
+ * @dev Update the voting period. This operation can only be performed through a governance proposal.
+ *
+ * Emits a {VotingPeriodSet} event.
+ */
+func (c *Governor) SetVotingPeriod(newVotingPeriod uint64) error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		if err := c.onlyGovernance(); err != nil {
+			return err
+		}
+		return c.setVotingPeriod(newVotingPeriod)
+	})
+}
+
+/**
+ * @dev Update the proposal threshold. This operation can only be performed through a governance proposal.
+ *
+ * Emits a {ProposalThresholdSet} event.
+ */
+func (c *Governor) SetProposalThreshold(newProposalThreshold *common.SafeUint256) error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		if err := c.onlyGovernance(); err != nil {
+			return err
+		}
+		return c.setProposalThreshold(newProposalThreshold)
+	})
+}
+
+/**
+ * @dev Changes the quorum numerator. This operation can only be performed through a governance proposal.
+ *
+ * Emits a {QuorumNumeratorUpdated} event.
+ *
+ * Requirements:
+ *
+ * - New numerator must be smaller or equal to the denominator.
+ */
+func (c *Governor) UpdateQuorumNumerator(newQuorumNumerator uint64) error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		if err := c.onlyGovernance(); err != nil {
+			return err
+		}
+		return c.updateQuorumNumerator(newQuorumNumerator)
+	})
+}
+
+// onlyGovernance 只有Governor合约自身（即通过提案执行）才能修改治理参数
+func (c *Governor) onlyGovernance() error {
+	sender, err := c.sdk.GetTxSender()
+	if err != nil {
+		return fmt.Errorf("Get sender address failed, err:%s", err)
+	}
+	self, err := c.dal.GetSelf()
+	if err != nil {
+		return err
+	}
+	return common.Require(sender.Equal(self), "Governor: onlyGovernance")
+}
+
+func (c *Governor) setVotingDelay(newVotingDelay uint64) error {
+	oldVotingDelay, err := c.dal.GetVotingDelay()
+	if err != nil {
+		return err
+	}
+	if err = c.dal.SetVotingDelay(newVotingDelay); err != nil {
+		return err
+	}
+	return c.sdk.EmitEvent("votingDelaySet", strconv.FormatUint(oldVotingDelay, 10),
+		strconv.FormatUint(newVotingDelay, 10))
+}
+
+func (c *Governor) setVotingPeriod(newVotingPeriod uint64) error {
+	// voting period must be at least one block long
+	if err := common.Require(newVotingPeriod > 0, "GovernorSettings: voting period too low"); err != nil {
+		return err
+	}
+	oldVotingPeriod, err := c.dal.GetVotingPeriod()
+	if err != nil {
+		return err
+	}
+	if err = c.dal.SetVotingPeriod(newVotingPeriod); err != nil {
+		return err
+	}
+	return c.sdk.EmitEvent("votingPeriodSet", strconv.FormatUint(oldVotingPeriod, 10),
+		strconv.FormatUint(newVotingPeriod, 10))
+}
+
+func (c *Governor) setProposalThreshold(newProposalThreshold *common.SafeUint256) error {
+	if newProposalThreshold == nil {
+		newProposalThreshold = common.NewSafeUint256(0)
+	}
+	oldProposalThreshold, err := c.dal.GetProposalThreshold()
+	if err != nil {
+		return err
+	}
+	if err = c.dal.SetProposalThreshold(newProposalThreshold); err != nil {
+		return err
+	}
+	return c.sdk.EmitEvent("proposalThresholdSet", oldProposalThreshold.ToString(), newProposalThreshold.ToString())
+}
+
+func (c *Governor) updateQuorumNumerator(newQuorumNumerator uint64) error {
+	if err := common.Require(newQuorumNumerator <= quorumDenominator,
+		"GovernorVotesQuorumFraction: quorumNumerator over quorumDenominator"); err != nil {
+		return err
+	}
+	oldQuorumNumerator, err := c.dal.GetQuorumNumerator()
+	if err != nil {
+		return err
+	}
+	if err = c.dal.SetQuorumNumerator(newQuorumNumerator); err != nil {
+		return err
+	}
+	return c.sdk.EmitEvent("quorumNumeratorUpdated", strconv.FormatUint(oldQuorumNumerator, 10),
+		strconv.FormatUint(newQuorumNumerator, 10))
+}
+
+/**
+ * @dev Register a vote for `proposalId` by `account` with a given `support` and voting `weight`.
+ */
+func (c *Governor) countVote(proposalId string, account common.Account, support VoteType,
+	weight *common.SafeUint256) error {
+	voted, err := c.dal.HasVoted(proposalId, account)
+	if err != nil {
+		return err
+	}
+	if err = common.Require(!voted, "GovernorVotingSimple: vote already cast"); err != nil {
+		return err
+	}
+	vote, err := c.dal.GetProposalVote(proposalId)
+	if err != nil {
+		return err
+	}
+	var ok bool
+	switch support {
+	case VoteAgainst:
+		vote.AgainstVotes, ok = common.SafeAdd(vote.AgainstVotes, weight)
+	case VoteFor:
+		vote.ForVotes, ok = common.SafeAdd(vote.ForVotes, weight)
+	case VoteAbstain:
+		vote.AbstainVotes, ok = common.SafeAdd(vote.AbstainVotes, weight)
+	default:
+		return errors.New("GovernorVotingSimple: invalid value for enum VoteType")
+	}
+	if !ok {
+		return errors.New("GovernorVotingSimple: votes overflow")
+	}
+	if err = c.dal.SetVoted(proposalId, account); err != nil {
+		return err
+	}
+	return c.dal.SetProposalVote(proposalId, vote)
+}
+
+/**
+ * @dev Amount of votes already cast passes the threshold limit. In this module,
+ * both for and abstain votes count towards the quorum.
+ */
+func (c *Governor) quorumReached(proposalId string, snapshot uint64) (bool, error) {
+	vote, err := c.dal.GetProposalVote(proposalId)
+	if err != nil {
+		return false, err
+	}
+	quorum, err := c.Quorum(snapshot)
+	if err != nil {
+		return false, err
+	}
+	votes, ok := common.SafeAdd(vote.ForVotes, vote.AbstainVotes)
+	if !ok {
+		return false, errors.New("Governor: votes overflow")
+	}
+	return votes.GTE(quorum), nil
+}
+
+/**
+ * @dev Is the proposal successful or not. In this module, the forVotes must be strictly over the againstVotes.
+ */
+func (c *Governor) voteSucceeded(proposalId string) (bool, error) {
+	vote, err := c.dal.GetProposalVote(proposalId)
+	if err != nil {
+		return false, err
+	}
+	return !vote.AgainstVotes.GTE(vote.ForVotes), nil
+}
+
+func (c *Governor) proposal(proposalId string) (*proposalRecord, error) {
+	proposal, err := c.dal.GetProposal(proposalId)
+	if err != nil {
+		return nil, err
+	}
+	if proposal == nil {
+		return nil, errors.New("Governor: unknown proposal id")
+	}
+	return proposal, nil
+}
+
+func (c *Governor) token() (*erc20.TokenCaller, error) {
+	token, err := c.dal.GetToken()
+	if err != nil {
+		return nil, err
+	}
+	return erc20.NewTokenCaller(c.sdk, token), nil
+}
+
+func (c *Governor) decodeCalls(records []callRecord) ([]Call, error) {
+	calls := make([]Call, len(records))
+	for i, record := range records {
+		target, err := c.sdk.NewAccountFromString(record.Target)
+		if err != nil {
+			return nil, err
+		}
+		calls[i] = Call{Target: target, Method: record.Method, Args: record.Args}
+	}
+	return calls, nil
+}
+
+func encodeCallRecords(calls []Call) []callRecord {
+	records := make([]callRecord, len(calls))
+	for i, call := range calls {
+		records[i] = callRecord{Target: call.Target.ToString(), Method: call.Method, Args: call.Args}
+	}
+	return records
+}
+
+// encodeCalls 提案中调用的json编码，用于计算提案id和事件
+func encodeCalls(calls []Call) ([]byte, error) {
+	return json.Marshal(encodeCallRecords(calls))
+}
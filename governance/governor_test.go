@@ -0,0 +1,242 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package governance
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/studyzy/openzeppelin-go/common"
+	"github.com/studyzy/openzeppelin-go/erc20"
+	"github.com/studyzy/openzeppelin-go/erc20/erc20mock"
+	"github.com/studyzy/openzeppelin-go/mock"
+)
+
+var (
+	deployer = mock.NewAccount("deployer")
+	alice    = mock.NewAccount("alice")
+	bob      = mock.NewAccount("bob")
+	carol    = mock.NewAccount("carol")
+	self     = mock.NewAccount("governor")
+)
+
+func mustInvoke(t *testing.T, chain *mock.Chain, sender mock.Account, fn func() error) {
+	t.Helper()
+	if err := chain.Invoke(sender, fn); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mustFail(t *testing.T, chain *mock.Chain, sender mock.Account, fn func() error, msg string) {
+	t.Helper()
+	err := chain.Invoke(sender, fn)
+	if err == nil {
+		t.Fatalf("expected error containing %q", msg)
+	}
+	if !strings.Contains(err.Error(), msg) {
+		t.Fatalf("got error %q, want %q", err, msg)
+	}
+}
+
+func requireState(t *testing.T, governor *Governor, proposalId string, want ProposalState) {
+	t.Helper()
+	state, err := governor.State(proposalId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state != want {
+		t.Fatalf("state = %d, want %d", state, want)
+	}
+}
+
+// newGovernor 部署投票代币和Governor，alice有60票，bob有40票，carol没有投票权。
+// 投票延迟1个区块，投票期5个区块，提案门槛10票，法定人数为总发行量的10%，
+// setVotingDelay可以通过CallContract调用
+func newGovernor(t *testing.T) (*mock.Chain, *Governor) {
+	t.Helper()
+	return newGovernorWithClock(t, common.ClockModeBlockNumber)
+}
+
+// newGovernorWithClock 与newGovernor相同，链和代币使用clockMode指定的时钟
+func newGovernorWithClock(t *testing.T, clockMode string) (*mock.Chain, *Governor) {
+	t.Helper()
+	chain := mock.NewChain()
+	chain.SetClockMode(clockMode)
+	token, err := erc20mock.Deploy(chain, "token", erc20.Option{Minable: true}, deployer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, holder := range []struct {
+		voter mock.Account
+		votes uint64
+	}{{alice, 60}, {bob, 40}} {
+		voter := holder.voter
+		mustInvoke(t, chain, deployer, func() error {
+			_, err := token.Mint(voter, common.NewSafeUint256(holder.votes))
+			return err
+		})
+		mustInvoke(t, chain, voter, func() error { return token.Delegate(voter) })
+	}
+	chain.SetSender(deployer)
+	governor := NewGovernor(chain.Deploy(self.ToString(),
+		func(sdk *mock.SDK, method string, args []common.KeyValue) common.Response {
+			if method != "setVotingDelay" || len(args) != 1 {
+				return mock.Error("Invalid method")
+			}
+			delay, err := strconv.ParseUint(string(args[0].Value), 10, 64)
+			if err != nil {
+				return mock.Error(err.Error())
+			}
+			if err = NewGovernor(sdk).SetVotingDelay(delay); err != nil {
+				return mock.Error(err.Error())
+			}
+			return mock.Success(nil)
+		}))
+	init := func() error {
+		return governor.InitGovernor("Governor", self, mock.NewAccount("token"), 1, 5, common.NewSafeUint256(10), 10)
+	}
+	mustFail(t, chain, alice, init, "Access: caller is not the contract creator")
+	mustInvoke(t, chain, deployer, init)
+	mustFail(t, chain, deployer, init, "Governor: already initialized")
+	//投票权的检查点要在过去的区块中才能查询
+	chain.Mine(1, 5)
+	return chain, governor
+}
+
+func setVotingDelayCall(delay string) []Call {
+	return []Call{{
+		Target: self,
+		Method: "setVotingDelay",
+		Args:   []common.KeyValue{{Key: "votingDelay", Value: []byte(delay)}},
+	}}
+}
+
+func propose(t *testing.T, chain *mock.Chain, governor *Governor, proposer mock.Account, calls []Call,
+	description string) string {
+	t.Helper()
+	var proposalId string
+	mustInvoke(t, chain, proposer, func() error {
+		var err error
+		proposalId, err = governor.Propose(calls, description)
+		return err
+	})
+	return proposalId
+}
+
+func castVote(governor *Governor, proposalId string, support VoteType) func() error {
+	return func() error {
+		_, err := governor.CastVote(proposalId, support, "")
+		return err
+	}
+}
+
+func TestGovernorProposalSucceeds(t *testing.T) {
+	chain, governor := newGovernor(t)
+	mustFail(t, chain, carol, func() error {
+		_, err := governor.Propose(setVotingDelayCall("3"), "too poor")
+		return err
+	}, "Governor: proposer votes below proposal threshold")
+	proposalId := propose(t, chain, governor, alice, setVotingDelayCall("3"), "raise voting delay")
+	requireState(t, governor, proposalId, Pending)
+	mustFail(t, chain, alice, castVote(governor, proposalId, VoteFor), "Governor: vote not currently active")
+
+	chain.Mine(2, 10)
+	requireState(t, governor, proposalId, Active)
+	mustInvoke(t, chain, alice, castVote(governor, proposalId, VoteFor))
+	mustInvoke(t, chain, bob, castVote(governor, proposalId, VoteAgainst))
+	mustFail(t, chain, alice, castVote(governor, proposalId, VoteFor),
+		"GovernorVotingSimple: vote already cast")
+	votes, err := governor.ProposalVotes(proposalId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !votes.ForVotes.Equal(common.NewSafeUint256(60)) || !votes.AgainstVotes.Equal(common.NewSafeUint256(40)) {
+		t.Fatalf("votes = %s/%s, want 60/40", votes.ForVotes.ToString(), votes.AgainstVotes.ToString())
+	}
+	mustFail(t, chain, carol, func() error { return governor.Execute(proposalId) }, "Governor: proposal not successful")
+
+	chain.Mine(5, 25)
+	requireState(t, governor, proposalId, Succeeded)
+	mustInvoke(t, chain, carol, func() error { return governor.Execute(proposalId) })
+	requireState(t, governor, proposalId, Executed)
+	if delay, _ := governor.VotingDelay(); delay != 3 {
+		t.Fatalf("voting delay = %d, want 3", delay)
+	}
+	mustFail(t, chain, carol, func() error { return governor.Execute(proposalId) }, "Governor: proposal not successful")
+}
+
+func TestGovernorProposalDefeated(t *testing.T) {
+	chain, governor := newGovernor(t)
+	proposalId := propose(t, chain, governor, bob, setVotingDelayCall("3"), "raise voting delay")
+	chain.Mine(2, 10)
+	mustInvoke(t, chain, alice, castVote(governor, proposalId, VoteAgainst))
+	mustInvoke(t, chain, bob, castVote(governor, proposalId, VoteFor))
+	chain.Mine(5, 25)
+	requireState(t, governor, proposalId, Defeated)
+	mustFail(t, chain, bob, func() error { return governor.Execute(proposalId) }, "Governor: proposal not successful")
+
+	//没有人投票时达不到法定人数
+	proposalId = propose(t, chain, governor, bob, setVotingDelayCall("4"), "nobody votes")
+	chain.Mine(7, 35)
+	requireState(t, governor, proposalId, Defeated)
+}
+
+func TestGovernorCancel(t *testing.T) {
+	chain, governor := newGovernor(t)
+	proposalId := propose(t, chain, governor, alice, setVotingDelayCall("3"), "cancel me")
+	mustFail(t, chain, bob, func() error { return governor.Cancel(proposalId) }, "Governor: only proposer can cancel")
+	mustInvoke(t, chain, alice, func() error { return governor.Cancel(proposalId) })
+	requireState(t, governor, proposalId, Canceled)
+	mustFail(t, chain, alice, func() error { return governor.Cancel(proposalId) }, "Governor: too late to cancel")
+
+	proposalId = propose(t, chain, governor, alice, setVotingDelayCall("4"), "too late")
+	chain.Mine(2, 10)
+	mustFail(t, chain, alice, func() error { return governor.Cancel(proposalId) }, "Governor: too late to cancel")
+}
+
+func TestGovernorOnlyGovernance(t *testing.T) {
+	chain, governor := newGovernor(t)
+	mustFail(t, chain, alice, func() error { return governor.SetVotingDelay(3) }, "Governor: onlyGovernance")
+	mustFail(t, chain, deployer, func() error { return governor.UpdateQuorumNumerator(50) },
+		"Governor: onlyGovernance")
+}
+
+func TestGovernorUsesTokenClock(t *testing.T) {
+	chain, governor := newGovernorWithClock(t, common.ClockModeTimestamp)
+	mode, err := governor.ClockMode()
+	if err != nil || mode != common.ClockModeTimestamp {
+		t.Fatalf("clock mode = %q, %v, want %q", mode, err, common.ClockModeTimestamp)
+	}
+	now, err := governor.Clock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	proposalId := propose(t, chain, governor, alice, setVotingDelayCall("3"), "timestamp clock")
+	if snapshot, _ := governor.ProposalSnapshot(proposalId); snapshot != now+1 {
+		t.Fatalf("snapshot = %d, want %d", snapshot, now+1)
+	}
+	if deadline, _ := governor.ProposalDeadline(proposalId); deadline != now+6 {
+		t.Fatalf("deadline = %d, want %d", deadline, now+6)
+	}
+	//投票延迟按秒计算，只出块不推进时间时提案仍未开始
+	chain.Mine(10, 0)
+	requireState(t, governor, proposalId, Pending)
+	chain.Mine(1, 2)
+	requireState(t, governor, proposalId, Active)
+	mustInvoke(t, chain, alice, castVote(governor, proposalId, VoteFor))
+	chain.Mine(1, 5)
+	requireState(t, governor, proposalId, Succeeded)
+}
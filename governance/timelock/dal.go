@@ -0,0 +1,86 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package timelock
+
+import (
+	"strconv"
+
+	"github.com/studyzy/openzeppelin-go/common"
+)
+
+const (
+	selfKey      = "self"
+	minDelayKey  = "minDelay"
+	timestampKey = "timestamp"
+)
+
+type TimelockDAL struct {
+	sdk common.StateOperator
+}
+
+func NewTimelockDAL(sdk common.StateOperator) *TimelockDAL {
+	return &TimelockDAL{sdk: sdk}
+}
+
+// GetSelf 获得初始化时记录的本合约地址，没有设置时返回零地址
+func (c *TimelockDAL) GetSelf() (common.Account, error) {
+	b, err := c.sdk.GetState(selfKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return c.sdk.NewZeroAccount(), nil
+	}
+	return c.sdk.NewAccountFromString(string(b))
+}
+func (c *TimelockDAL) SetSelf(self common.Account) error {
+	return c.sdk.PutState(selfKey, []byte(self.ToString()))
+}
+
+func (c *TimelockDAL) GetMinDelay() (int64, error) {
+	return c.getInt64(minDelayKey)
+}
+func (c *TimelockDAL) SetMinDelay(delay int64) error {
+	return c.sdk.PutState(minDelayKey, []byte(strconv.FormatInt(delay, 10)))
+}
+
+// GetTimestamp 获得操作可以执行的时间戳，0表示操作不存在，doneTimestamp表示已经执行
+func (c *TimelockDAL) GetTimestamp(id string) (int64, error) {
+	key, err := c.sdk.CreateCompositeKey(timestampKey, id)
+	if err != nil {
+		return 0, err
+	}
+	return c.getInt64(key)
+}
+
+// SetTimestamp timestamp为0时删除记录
+func (c *TimelockDAL) SetTimestamp(id string, timestamp int64) error {
+	key, err := c.sdk.CreateCompositeKey(timestampKey, id)
+	if err != nil {
+		return err
+	}
+	if timestamp == 0 {
+		return c.sdk.DelState(key)
+	}
+	return c.sdk.PutState(key, []byte(strconv.FormatInt(timestamp, 10)))
+}
+
+func (c *TimelockDAL) getInt64(key string) (int64, error) {
+	b, err := c.sdk.GetState(key)
+	if err != nil || len(b) == 0 {
+		return 0, err
+	}
+	return strconv.ParseInt(string(b), 10, 64)
+}
@@ -0,0 +1,451 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package timelock
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/studyzy/openzeppelin-go/access"
+	"github.com/studyzy/openzeppelin-go/common"
+)
+
+// doneTimestamp 已经执行的操作的时间戳，与OpenZeppelin的_DONE_TIMESTAMP相同
+const doneTimestamp = int64(1)
+
+const operationType = "Operation(address target,string method,bytes args,bytes32 predecessor,bytes32 salt)"
+
+// OperationState 时间锁中操作的状态
+type OperationState int
+
+const (
+	// Unset 操作不存在，或者已经被取消
+	Unset OperationState = iota
+	// Waiting 已经安排，但还没有到可以执行的时间
+	Waiting
+	// Ready 已经到期，可以执行
+	Ready
+	// Done 已经执行
+	Done
+)
+
+/**
+ * @dev Contract module which acts as a timelocked controller. When set as the
+ * owner or admin of a contract, it enforces a timelock on all privileged
+ * operations: an operation is scheduled by a proposer, becomes publicly visible
+ * through the {CallScheduled} event, and can only be executed by an executor
+ * after the delay has passed. Cancellers can drop a pending operation.
+ *
+ * 被管理的合约需要把相应的角色（例如MinterRole）授予本合约，
+ * 操作执行时通过CallContract调用目标合约，目标合约看到的调用者就是本合约。
+ */
+type TimelockController struct {
+	*access.AccessControl
+	dal *TimelockDAL
+	sdk common.ContractSDK
+}
+
+// NewTimelockController TimelockController
+// @param sdk
+// @return *TimelockController
+func NewTimelockController(sdk common.ContractSDK) *TimelockController {
+	return &TimelockController{
+		AccessControl: access.NewAccessControl(sdk),
+		dal:           NewTimelockDAL(sdk),
+		sdk:           sdk,
+	}
+}
+
+/**
+ * @dev Initializes the contract with the following parameters:
+ *
+ * - `self`: the address of this contract, which can always update the delay through a timelocked operation.
+ * - `minDelay`: initial minimum delay in seconds for operations.
+ * - `proposers`: accounts to be granted proposer and canceller roles.
+ * - `executors`: accounts to be granted executor role, the zero address opens the role to anyone.
+ * - `admin`: optional account to be granted admin role; disable with the zero address.
+ *
+ * IMPORTANT: The optional admin can aid with initial configuration of roles after deployment
+ * without being subject to delay, but this role should be subsequently renounced in favor of
+ * administration through timelocked proposals.
+ *
+ * Requirements:
+ *
+ * - the caller must be the account that deployed this contract.
+ * - the timelock can only be initialized once.
+ */
+func (c *TimelockController) InitTimelock(self common.Account, minDelay int64, proposers, executors []common.Account,
+	admin common.Account) error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		if err := access.OnlyCreator(c.sdk); err != nil {
+			return err
+		}
+		current, err := c.dal.GetSelf()
+		if err != nil {
+			return err
+		}
+		if err = common.Require(current.IsZero(), "TimelockController: already initialized"); err != nil {
+			return err
+		}
+		if err := common.Require(!self.IsZero(), "TimelockController: the zero address"); err != nil {
+			return err
+		}
+		if err := common.Require(minDelay >= 0, "TimelockController: negative delay"); err != nil {
+			return err
+		}
+		if err := c.dal.SetSelf(self); err != nil {
+			return err
+		}
+		// self administration
+		if err := c.SetupRole(access.AdminRole, self); err != nil {
+			return fmt.Errorf("set admin failed, err:%s", err)
+		}
+		// optional admin
+		if !admin.IsZero() {
+			if err := c.SetupRole(access.AdminRole, admin); err != nil {
+				return fmt.Errorf("set admin failed, err:%s", err)
+			}
+		}
+		// register proposers and cancellers
+		for _, proposer := range proposers {
+			if err := c.SetupRole(access.ProposerRole, proposer); err != nil {
+				return fmt.Errorf("set proposer failed, err:%s", err)
+			}
+			if err := c.SetupRole(access.CancellerRole, proposer); err != nil {
+				return fmt.Errorf("set canceller failed, err:%s", err)
+			}
+		}
+		// register executors
+		for _, executor := range executors {
+			if err := c.SetupRole(access.ExecutorRole, executor); err != nil {
+				return fmt.Errorf("set executor failed, err:%s", err)
+			}
+		}
+		if err := c.dal.SetMinDelay(minDelay); err != nil {
+			return err
+		}
+		return c.sdk.EmitEvent("minDelayChange", "0", strconv.FormatInt(minDelay, 10))
+	})
+}
+
+func (c *TimelockController) SetSDK(sdk common.ContractSDK) {
+	c.sdk = sdk
+	c.dal = NewTimelockDAL(sdk)
+	c.AccessControl = access.NewAccessControl(sdk)
+}
+
+/**
+ * @dev Returns whether an id correspond to a registered operation. This
+ * includes both Pending, Ready and Done operations.
+ */
+func (c *TimelockController) IsOperation(id string) (bool, error) {
+	state, err := c.GetOperationState(id)
+	return state != Unset, err
+}
+
+/**
+ * @dev Returns whether an operation is pending or not. Note that a "pending" operation may also be "ready".
+ */
+func (c *TimelockController) IsOperationPending(id string) (bool, error) {
+	state, err := c.GetOperationState(id)
+	return state == Waiting || state == Ready, err
+}
+
+/**
+ * @dev Returns whether an operation is ready for execution. Note that a "ready" operation is also "pending".
+ */
+func (c *TimelockController) IsOperationReady(id string) (bool, error) {
+	state, err := c.GetOperationState(id)
+	return state == Ready, err
+}
+
+/**
+ * @dev Returns whether an operation is done or not.
+ */
+func (c *TimelockController) IsOperationDone(id string) (bool, error) {
+	state, err := c.GetOperationState(id)
+	return state == Done, err
+}
+
+/**
+ * @dev Returns the timestamp at which an operation becomes ready (0 for
+ * unset operations, 1 for done operations).
+ */
+func (c *TimelockController) GetTimestamp(id string) (int64, error) {
+	return c.dal.GetTimestamp(id)
+}
+
+/**
+ * @dev Returns operation state.
+ */
+func (c *TimelockController) GetOperationState(id string) (OperationState, error) {
+	timestamp, err := c.dal.GetTimestamp(id)
+	if err != nil {
+		return Unset, err
+	}
+	switch timestamp {
+	case 0:
+		return Unset, nil
+	case doneTimestamp:
+		return Done, nil
+	}
+	now, err := c.sdk.GetTxTimestamp()
+	if err != nil {
+		return Unset, err
+	}
+	if timestamp > now {
+		return Waiting, nil
+	}
+	return Ready, nil
+}
+
+/**
+ * @dev Returns the minimum delay in seconds for an operation to become valid.
+ *
+ * This value can be changed by executing an operation that calls `updateDelay`.
+ */
+func (c *TimelockController) GetMinDelay() (int64, error) {
+	return c.dal.GetMinDelay()
+}
+
+/**
+ * @dev Returns the identifier of an operation containing a single transaction.
+ */
+func (c *TimelockController) HashOperation(target common.Account, method string, args []common.KeyValue,
+	predecessor, salt string) (string, error) {
+	encodedArgs, err := json.Marshal(args)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(common.HashStruct(operationType, target.ToString(), method, string(encodedArgs),
+		predecessor, salt)), nil
+}
+
+/**
+ * @dev Schedule an operation containing a single transaction.
+ *
+ * Emits {CallSalt} if salt is nonzero, and {CallScheduled}.
+ *
+ * Requirements:
+ *
+ * - the caller must have the 'proposer' role.
+ * - `delay` must be at least the minimum delay.
+ */
+func (c *TimelockController) Schedule(target common.Account, method string, args []common.KeyValue,
+	predecessor, salt string, delay int64) (string, error) {
+	var id string
+	err := common.Atomic(c.sdk, c.SetSDK, func() error {
+		if err := c.OnlyRole(access.ProposerRole); err != nil {
+			return err
+		}
+		var err error
+		if id, err = c.HashOperation(target, method, args, predecessor, salt); err != nil {
+			return err
+		}
+		if err = c.schedule(id, delay); err != nil {
+			return err
+		}
+		encodedArgs, err := json.Marshal(args)
+		if err != nil {
+			return err
+		}
+		if err = c.sdk.EmitEvent("callScheduled", id, target.ToString(), method, string(encodedArgs), predecessor,
+			strconv.FormatInt(delay, 10)); err != nil {
+			return err
+		}
+		if len(salt) > 0 {
+			return c.sdk.EmitEvent("callSalt", id, salt)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+/**
+ * @dev Cancel an operation.
+ *
+ * Requirements:
+ *
+ * - the caller must have the 'canceller' role.
+ */
+func (c *TimelockController) Cancel(id string) error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		if err := c.OnlyRole(access.CancellerRole); err != nil {
+			return err
+		}
+		pending, err := c.IsOperationPending(id)
+		if err != nil {
+			return err
+		}
+		if err = common.Require(pending, "TimelockController: operation cannot be cancelled"); err != nil {
+			return err
+		}
+		if err = c.dal.SetTimestamp(id, 0); err != nil {
+			return err
+		}
+		return c.sdk.EmitEvent("cancelled", id)
+	})
+}
+
+/**
+ * @dev Execute an (ready) operation containing a single transaction.
+ * The target contract is called through `CallContract` and its payload is returned.
+ *
+ * Emits a {CallExecuted} event.
+ *
+ * Requirements:
+ *
+ * - the caller must have the 'executor' role, unless the role is open to the zero address.
+ */
+func (c *TimelockController) Execute(target common.Account, method string, args []common.KeyValue,
+	predecessor, salt string) ([]byte, error) {
+	var payload []byte
+	err := common.Atomic(c.sdk, c.SetSDK, func() error {
+		if err := c.onlyRoleOrOpenRole(access.ExecutorRole); err != nil {
+			return err
+		}
+		id, err := c.HashOperation(target, method, args, predecessor, salt)
+		if err != nil {
+			return err
+		}
+		ready, err := c.IsOperationReady(id)
+		if err != nil {
+			return err
+		}
+		if err = common.Require(ready, "TimelockController: operation is not ready"); err != nil {
+			return err
+		}
+		if len(predecessor) > 0 {
+			done, err := c.IsOperationDone(predecessor)
+			if err != nil {
+				return err
+			}
+			if err = common.Require(done, "TimelockController: missing dependency"); err != nil {
+				return err
+			}
+		}
+		//先标记为已执行再调用目标合约，避免目标合约重入时重复执行
+		if err = c.dal.SetTimestamp(id, doneTimestamp); err != nil {
+			return err
+		}
+		response := c.sdk.CallContract(target, method, args)
+		if response.Status != common.OK {
+			return fmt.Errorf("TimelockController: underlying transaction reverted, err:%s", response.Message)
+		}
+		payload = response.Payload
+		encodedArgs, err := json.Marshal(args)
+		if err != nil {
+			return err
+		}
+		return c.sdk.EmitEvent("callExecuted", id, target.ToString(), method, string(encodedArgs))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+/**
+ * @dev Changes the minimum timelock duration for future operations.
+ *
+ * Emits a {MinDelayChange} event.
+ *
+ * Requirements:
+ *
+ * - the caller must be the timelock itself. This can only be achieved by scheduling and later executing
+ * an operation where the timelock is the target and the method is `updateDelay`.
+ */
+func (c *TimelockController) UpdateDelay(newDelay int64) error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		sender, err := c.sdk.GetTxSender()
+		if err != nil {
+			return fmt.Errorf("Get sender address failed, err:%s", err)
+		}
+		self, err := c.dal.GetSelf()
+		if err != nil {
+			return err
+		}
+		if err = common.Require(sender.Equal(self), "TimelockController: caller must be timelock"); err != nil {
+			return err
+		}
+		if err = common.Require(newDelay >= 0, "TimelockController: negative delay"); err != nil {
+			return err
+		}
+		oldDelay, err := c.dal.GetMinDelay()
+		if err != nil {
+			return err
+		}
+		if err = c.dal.SetMinDelay(newDelay); err != nil {
+			return err
+		}
+		return c.sdk.EmitEvent("minDelayChange", strconv.FormatInt(oldDelay, 10), strconv.FormatInt(newDelay, 10))
+	})
+}
+
+/**
+ * @dev Schedule an operation that is to become valid after a given delay.
+ */
+func (c *TimelockController) schedule(id string, delay int64) error {
+	exists, err := c.IsOperation(id)
+	if err != nil {
+		return err
+	}
+	if err = common.Require(!exists, "TimelockController: operation already scheduled"); err != nil {
+		return err
+	}
+	minDelay, err := c.dal.GetMinDelay()
+	if err != nil {
+		return err
+	}
+	if err = common.Require(delay >= minDelay, "TimelockController: insufficient delay"); err != nil {
+		return err
+	}
+	now, err := c.sdk.GetTxTimestamp()
+	if err != nil {
+		return err
+	}
+	//溢出后可执行时间会变成过去的时间，操作立即就可以执行
+	if err = common.Require(delay <= math.MaxInt64-now, "TimelockController: delay overflow"); err != nil {
+		return err
+	}
+	//时间戳为1表示已执行，所以可执行时间至少要大于1
+	if err = common.Require(now+delay > doneTimestamp, "TimelockController: invalid timestamp"); err != nil {
+		return err
+	}
+	return c.dal.SetTimestamp(id, now+delay)
+}
+
+/**
+ * @dev Modifier to make a function callable only by a certain role. In
+ * addition to checking the sender's role, the zero address is also
+ * considered. Granting a role to the zero address is equivalent to
+ * enabling this role for everyone.
+ */
+func (c *TimelockController) onlyRoleOrOpenRole(role string) error {
+	open, err := c.HasRole(role, c.sdk.NewZeroAccount())
+	if err != nil {
+		return err
+	}
+	if open {
+		return nil
+	}
+	return c.OnlyRole(role)
+}
@@ -0,0 +1,178 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package timelock
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/studyzy/openzeppelin-go/access"
+	"github.com/studyzy/openzeppelin-go/common"
+	"github.com/studyzy/openzeppelin-go/mock"
+)
+
+var (
+	deployer = mock.NewAccount("deployer")
+	proposer = mock.NewAccount("proposer")
+	executor = mock.NewAccount("executor")
+	alice    = mock.NewAccount("alice")
+	self     = mock.NewAccount("timelock")
+)
+
+func mustInvoke(t *testing.T, chain *mock.Chain, sender mock.Account, fn func() error) {
+	t.Helper()
+	if err := chain.Invoke(sender, fn); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mustFail(t *testing.T, chain *mock.Chain, sender mock.Account, fn func() error, msg string) {
+	t.Helper()
+	err := chain.Invoke(sender, fn)
+	if err == nil {
+		t.Fatalf("expected error containing %q", msg)
+	}
+	if !strings.Contains(err.Error(), msg) {
+		t.Fatalf("got error %q, want %q", err, msg)
+	}
+}
+
+// newTimelock 部署一个最小延迟为100秒、deployer为管理员的时间锁，updateDelay可以通过CallContract调用
+func newTimelock(t *testing.T) (*mock.Chain, *TimelockController) {
+	t.Helper()
+	chain := mock.NewChain()
+	chain.SetSender(deployer)
+	timelock := NewTimelockController(chain.Deploy(self.ToString(),
+		func(sdk *mock.SDK, method string, args []common.KeyValue) common.Response {
+			if method != "updateDelay" || len(args) != 1 {
+				return mock.Error("Invalid method")
+			}
+			delay, err := strconv.ParseInt(string(args[0].Value), 10, 64)
+			if err != nil {
+				return mock.Error(err.Error())
+			}
+			if err = NewTimelockController(sdk).UpdateDelay(delay); err != nil {
+				return mock.Error(err.Error())
+			}
+			return mock.Success(nil)
+		}))
+	init := func() error {
+		return timelock.InitTimelock(self, 100, []common.Account{proposer}, []common.Account{executor}, deployer)
+	}
+	mustFail(t, chain, alice, init, "Access: caller is not the contract creator")
+	mustInvoke(t, chain, deployer, init)
+	mustFail(t, chain, deployer, init, "TimelockController: already initialized")
+	return chain, timelock
+}
+
+func TestTimelockSchedule(t *testing.T) {
+	chain, timelock := newTimelock(t)
+	executed := 0
+	chain.Deploy("target", func(sdk *mock.SDK, method string, args []common.KeyValue) common.Response {
+		sender, _ := sdk.GetTxSender()
+		if !self.Equal(sender) {
+			return mock.Error("caller is not timelock")
+		}
+		if method == "fail" {
+			return mock.Error("target failed")
+		}
+		executed++
+		return mock.Success([]byte("ok"))
+	})
+	target := mock.NewAccount("target")
+	schedule := func(method string, delay int64) func() error {
+		return func() error {
+			_, err := timelock.Schedule(target, method, nil, "", "", delay)
+			return err
+		}
+	}
+	execute := func(method string) func() error {
+		return func() error {
+			_, err := timelock.Execute(target, method, nil, "", "")
+			return err
+		}
+	}
+	mustFail(t, chain, alice, schedule("run", 100), "is missing role")
+	mustFail(t, chain, proposer, schedule("run", 99), "TimelockController: insufficient delay")
+	mustFail(t, chain, proposer, schedule("run", math.MaxInt64), "TimelockController: delay overflow")
+	mustInvoke(t, chain, proposer, schedule("run", 100))
+	mustFail(t, chain, proposer, schedule("run", 100), "TimelockController: operation already scheduled")
+	mustFail(t, chain, executor, execute("run"), "TimelockController: operation is not ready")
+	chain.Mine(1, 100)
+	mustFail(t, chain, alice, execute("run"), "is missing role")
+	mustInvoke(t, chain, executor, execute("run"))
+	mustFail(t, chain, executor, execute("run"), "TimelockController: operation is not ready")
+	if executed != 1 {
+		t.Fatalf("target executed %d times, want 1", executed)
+	}
+	id, err := timelock.HashOperation(target, "run", nil, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state, _ := timelock.GetOperationState(id); state != Done {
+		t.Fatalf("state = %d, want Done", state)
+	}
+	//目标合约失败时操作保持Ready，可以重试
+	mustInvoke(t, chain, proposer, schedule("fail", 100))
+	chain.Mine(1, 100)
+	mustFail(t, chain, executor, execute("fail"), "target failed")
+	id, err = timelock.HashOperation(target, "fail", nil, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state, _ := timelock.GetOperationState(id); state != Ready {
+		t.Fatalf("state = %d, want Ready", state)
+	}
+	mustInvoke(t, chain, proposer, func() error {
+		return timelock.Cancel(id)
+	})
+	if state, _ := timelock.GetOperationState(id); state != Unset {
+		t.Fatalf("state = %d, want Unset", state)
+	}
+}
+
+func TestTimelockUpdateDelay(t *testing.T) {
+	chain, timelock := newTimelock(t)
+	mustFail(t, chain, deployer, func() error {
+		return timelock.UpdateDelay(0)
+	}, "TimelockController: caller must be timelock")
+	args := []common.KeyValue{{Key: "newDelay", Value: []byte("200")}}
+	mustInvoke(t, chain, proposer, func() error {
+		_, err := timelock.Schedule(self, "updateDelay", args, "", "", 100)
+		return err
+	})
+	chain.Mine(1, 100)
+	//执行者角色开放给零地址后任何人都可以执行
+	mustFail(t, chain, alice, func() error {
+		_, err := timelock.Execute(self, "updateDelay", args, "", "")
+		return err
+	}, "is missing role")
+	mustInvoke(t, chain, deployer, func() error {
+		return timelock.GrantRole(access.ExecutorRole, mock.ZeroAccount)
+	})
+	mustInvoke(t, chain, alice, func() error {
+		_, err := timelock.Execute(self, "updateDelay", args, "", "")
+		return err
+	})
+	delay, err := timelock.GetMinDelay()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delay != 200 {
+		t.Fatalf("min delay = %d, want 200", delay)
+	}
+}
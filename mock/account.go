@@ -0,0 +1,50 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mock
+
+import "github.com/studyzy/openzeppelin-go/common"
+
+var _ common.Account = Account("")
+
+// ZeroAccount 模拟链上的零地址
+const ZeroAccount = Account("0x0")
+
+// Account 模拟链上的账户地址，直接使用字符串作为地址
+type Account string
+
+// NewAccount 根据名字创建一个模拟账户
+func NewAccount(name string) Account {
+	return Account(name)
+}
+
+func (a Account) ToString() string {
+	return string(a)
+}
+
+func (a Account) Bytes() []byte {
+	return []byte(a)
+}
+
+func (a Account) Equal(account common.Account) bool {
+	if account == nil {
+		return false
+	}
+	return string(a) == account.ToString()
+}
+
+// IsZero 空地址和零地址都视为零地址，这样读取不存在的状态时得到的账户也是零地址
+func (a Account) IsZero() bool {
+	return a == "" || a == ZeroAccount
+}
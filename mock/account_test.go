@@ -0,0 +1,33 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mock
+
+import "testing"
+
+func TestAccount(t *testing.T) {
+	alice := NewAccount("alice")
+	if alice.IsZero() {
+		t.Fatal("alice should not be zero")
+	}
+	if !Account("").IsZero() || !ZeroAccount.IsZero() {
+		t.Fatal("empty and zero account should be zero")
+	}
+	if !alice.Equal(NewAccount("alice")) || alice.Equal(NewAccount("bob")) || alice.Equal(nil) {
+		t.Fatal("unexpected Equal result")
+	}
+	if alice.ToString() != "alice" || string(alice.Bytes()) != "alice" {
+		t.Fatal("unexpected string or bytes")
+	}
+}
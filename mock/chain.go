@@ -0,0 +1,309 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package mock 提供一个完全运行在内存中的common.ContractSDK实现，
+方便在不启动ChainMaker或Fabric节点的情况下用go test测试合约逻辑。
+*/
+package mock
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/studyzy/openzeppelin-go/common"
+)
+
+// Handler 模拟合约的入口函数，CallContract会把调用分发到被调合约的Handler
+type Handler func(sdk *SDK, method string, args []common.KeyValue) common.Response
+
+// Event 记录合约发出的事件
+type Event struct {
+	Contract string
+	Topic    string
+	Data     []string
+}
+
+type stateEntry struct {
+	value   []byte
+	deleted bool
+}
+
+// txLayer 一个事务中暂存的写集和事件
+type txLayer struct {
+	writes map[string]stateEntry
+	events []Event
+}
+
+// Chain 模拟的链，保存所有合约的世界状态、事件日志和已部署的合约
+type Chain struct {
+	state     map[string][]byte
+	events    []Event
+	txs       []*txLayer
+	contracts map[string]Handler
+	creators  map[string]Account
+	sender    Account
+	callers   []Account
+	txCount   uint64
+	txId      string
+	height    uint64
+	timestamp int64
+	clockMode string
+}
+
+// genesisTimestamp 模拟链的初始时间 2023-01-01 00:00:00 UTC
+const genesisTimestamp = 1672531200
+
+// NewChain 创建一条空的模拟链
+func NewChain() *Chain {
+	return &Chain{
+		state:     make(map[string][]byte),
+		contracts: make(map[string]Handler),
+		creators:  make(map[string]Account),
+		height:    1,
+		timestamp: genesisTimestamp,
+		clockMode: common.ClockModeBlockNumber,
+	}
+}
+
+// Deploy 在模拟链上部署一个合约并返回该合约使用的SDK，当前的交易发送者会被记录为合约的创建者。
+// handler可以为nil，此时IsContract返回true，但是对它的CallContract都会失败
+func (c *Chain) Deploy(name string, handler Handler) *SDK {
+	c.contracts[name] = handler
+	c.creators[name] = c.sender
+	return c.NewSDK(name)
+}
+
+// NewSDK 返回名为name的合约使用的SDK，不同合约之间的状态互相隔离
+func (c *Chain) NewSDK(name string) *SDK {
+	return &SDK{chain: c, name: name}
+}
+
+// SetSender 切换后续交易的发送者
+func (c *Chain) SetSender(sender Account) {
+	c.sender = sender
+}
+
+// Sender 返回当前的交易发送者，跨合约调用时是调用方合约
+func (c *Chain) Sender() (Account, error) {
+	if len(c.callers) > 0 {
+		return c.callers[len(c.callers)-1], nil
+	}
+	if len(c.sender) == 0 {
+		return "", errors.New("mock: tx sender not set")
+	}
+	return c.sender, nil
+}
+
+// isCreator 当前的交易发送者是否为合约的创建者
+func (c *Chain) isCreator(contract string) (bool, error) {
+	sender, err := c.Sender()
+	if err != nil {
+		return false, err
+	}
+	creator, ok := c.creators[contract]
+	if !ok || len(creator) == 0 {
+		return false, errors.New("mock: creator of contract " + contract + " not set")
+	}
+	return creator.Equal(sender), nil
+}
+
+// SetBlockHeight 设置当前的区块高度
+func (c *Chain) SetBlockHeight(height uint64) {
+	c.height = height
+}
+
+// SetTimestamp 设置当前交易的时间戳，单位为秒
+func (c *Chain) SetTimestamp(timestamp int64) {
+	c.timestamp = timestamp
+}
+
+// SetClockMode 设置SDK的ClockMode，默认为区块高度，设置为common.ClockModeTimestamp可以模拟Fabric等无法获得区块高度的链
+func (c *Chain) SetClockMode(mode string) {
+	c.clockMode = mode
+}
+
+// Mine 把区块高度增加blocks，同时把时间向后推进seconds秒
+func (c *Chain) Mine(blocks uint64, seconds int64) {
+	c.height += blocks
+	c.timestamp += seconds
+}
+
+// Begin 开启一个事务，之后的PutState/DelState/EmitEvent在Commit之前都只暂存在事务中，
+// 事务可以嵌套，最外层的事务会分配一个新的交易ID
+func (c *Chain) Begin() {
+	if len(c.txs) == 0 {
+		c.txCount++
+		c.txId = fmt.Sprintf("mocktx%d", c.txCount)
+	}
+	c.txs = append(c.txs, &txLayer{writes: make(map[string]stateEntry)})
+}
+
+// Commit 提交最内层的事务，写集合并到外层事务，没有外层事务时写入世界状态
+func (c *Chain) Commit() error {
+	if len(c.txs) == 0 {
+		return errors.New("mock: no transaction to commit")
+	}
+	top := c.txs[len(c.txs)-1]
+	c.txs = c.txs[:len(c.txs)-1]
+	if len(c.txs) > 0 {
+		parent := c.txs[len(c.txs)-1]
+		for k, v := range top.writes {
+			parent.writes[k] = v
+		}
+		parent.events = append(parent.events, top.events...)
+		return nil
+	}
+	for k, v := range top.writes {
+		if v.deleted {
+			delete(c.state, k)
+		} else {
+			c.state[k] = v.value
+		}
+	}
+	c.events = append(c.events, top.events...)
+	return nil
+}
+
+// Rollback 丢弃最内层事务中暂存的所有写集和事件
+func (c *Chain) Rollback() error {
+	if len(c.txs) == 0 {
+		return errors.New("mock: no transaction to rollback")
+	}
+	c.txs = c.txs[:len(c.txs)-1]
+	return nil
+}
+
+// Invoke 以sender的身份在一个事务中执行fn，fn返回error则回滚，否则提交
+func (c *Chain) Invoke(sender Account, fn func() error) error {
+	c.sender = sender
+	c.Begin()
+	if err := fn(); err != nil {
+		c.Rollback()
+		return err
+	}
+	return c.Commit()
+}
+
+// Events 返回已经提交的所有事件
+func (c *Chain) Events() []Event {
+	return c.events
+}
+
+// EventsByTopic 返回已经提交的指定主题的事件
+func (c *Chain) EventsByTopic(topic string) []Event {
+	var result []Event
+	for _, e := range c.events {
+		if e.Topic == topic {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// ClearEvents 清空事件日志
+func (c *Chain) ClearEvents() {
+	c.events = nil
+}
+
+func (c *Chain) getState(key string) []byte {
+	for i := len(c.txs) - 1; i >= 0; i-- {
+		if v, ok := c.txs[i].writes[key]; ok {
+			if v.deleted {
+				return nil
+			}
+			return v.value
+		}
+	}
+	return c.state[key]
+}
+
+// scan 按key排序返回当前可见的、以prefix开头的所有状态
+func (c *Chain) scan(prefix string) []common.KeyValue {
+	keys := make(map[string]struct{})
+	for k := range c.state {
+		if strings.HasPrefix(k, prefix) {
+			keys[k] = struct{}{}
+		}
+	}
+	for _, tx := range c.txs {
+		for k := range tx.writes {
+			if strings.HasPrefix(k, prefix) {
+				keys[k] = struct{}{}
+			}
+		}
+	}
+	var kvs []common.KeyValue
+	for k := range keys {
+		if v := c.getState(k); v != nil {
+			kvs = append(kvs, common.KeyValue{Key: k, Value: v})
+		}
+	}
+	sort.Slice(kvs, func(i, j int) bool {
+		return kvs[i].Key < kvs[j].Key
+	})
+	return kvs
+}
+
+func (c *Chain) putState(key string, value []byte) {
+	v := make([]byte, len(value))
+	copy(v, value)
+	if len(c.txs) == 0 {
+		c.state[key] = v
+		return
+	}
+	c.txs[len(c.txs)-1].writes[key] = stateEntry{value: v}
+}
+
+func (c *Chain) delState(key string) {
+	if len(c.txs) == 0 {
+		delete(c.state, key)
+		return
+	}
+	c.txs[len(c.txs)-1].writes[key] = stateEntry{deleted: true}
+}
+
+func (c *Chain) emitEvent(e Event) {
+	if len(c.txs) == 0 {
+		c.events = append(c.events, e)
+		return
+	}
+	top := c.txs[len(c.txs)-1]
+	top.events = append(top.events, e)
+}
+
+// callContract 以caller的身份调用合约，被调合约返回错误时它的写集和事件会被回滚
+func (c *Chain) callContract(caller Account, contract string, method string, args []common.KeyValue) common.Response {
+	handler, ok := c.contracts[contract]
+	if !ok {
+		return common.Response{Status: common.ERROR, Message: "mock: contract " + contract + " not found"}
+	}
+	if handler == nil {
+		return common.Response{Status: common.ERROR, Message: "mock: contract " + contract + " has no handler"}
+	}
+	c.callers = append(c.callers, caller)
+	defer func() {
+		c.callers = c.callers[:len(c.callers)-1]
+	}()
+	c.Begin()
+	response := handler(c.NewSDK(contract), method, args)
+	if response.Status != common.OK {
+		c.Rollback()
+		return response
+	}
+	c.Commit()
+	return response
+}
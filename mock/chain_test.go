@@ -0,0 +1,215 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mock
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/studyzy/openzeppelin-go/common"
+)
+
+func TestInvokeCommitAndRollback(t *testing.T) {
+	chain := NewChain()
+	sdk := chain.Deploy("token", nil)
+	alice := NewAccount("alice")
+	err := chain.Invoke(alice, func() error {
+		sender, err := sdk.GetTxSender()
+		if err != nil {
+			return err
+		}
+		if !sender.Equal(alice) {
+			t.Fatalf("sender = %s, want alice", sender.ToString())
+		}
+		sdk.PutState("k", []byte("v1"))
+		return sdk.EmitEvent("set", "v1")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	failure := errors.New("failed")
+	err = chain.Invoke(alice, func() error {
+		sdk.PutState("k", []byte("v2"))
+		sdk.DelState("k")
+		sdk.EmitEvent("set", "v2")
+		return failure
+	})
+	if err != failure {
+		t.Fatalf("got %v, want %v", err, failure)
+	}
+	if v, _ := sdk.GetState("k"); string(v) != "v1" {
+		t.Fatalf("k = %q, want v1", v)
+	}
+	events := chain.EventsByTopic("set")
+	if len(events) != 1 || events[0].Contract != "token" || events[0].Data[0] != "v1" {
+		t.Fatalf("unexpected events %v", events)
+	}
+	chain.ClearEvents()
+	if len(chain.Events()) != 0 {
+		t.Fatal("events not cleared")
+	}
+}
+
+func TestNestedTransaction(t *testing.T) {
+	chain := NewChain()
+	sdk := chain.Deploy("token", nil)
+	chain.Begin()
+	txId, _ := sdk.GetTxId()
+	sdk.PutState("outer", []byte("1"))
+	chain.Begin()
+	sdk.PutState("inner", []byte("1"))
+	if v, _ := sdk.GetState("outer"); string(v) != "1" {
+		t.Fatal("inner transaction should see outer writes")
+	}
+	if err := chain.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	chain.Begin()
+	if id, _ := sdk.GetTxId(); id != txId {
+		t.Fatalf("nested transaction changed tx id from %s to %s", txId, id)
+	}
+	sdk.PutState("kept", []byte("1"))
+	chain.Commit()
+	if err := chain.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]string{"outer": "1", "inner": "", "kept": "1"} {
+		if v, _ := sdk.GetState(key); string(v) != want {
+			t.Fatalf("%s = %q, want %q", key, v, want)
+		}
+	}
+	if err := chain.Commit(); err == nil {
+		t.Fatal("commit without transaction should fail")
+	}
+	if err := chain.Rollback(); err == nil {
+		t.Fatal("rollback without transaction should fail")
+	}
+}
+
+func TestSenderAndClock(t *testing.T) {
+	chain := NewChain()
+	sdk := chain.Deploy("token", nil)
+	if _, err := sdk.GetTxSender(); err == nil {
+		t.Fatal("sender should not be set")
+	}
+	chain.SetSender(NewAccount("alice"))
+	if sender, _ := sdk.GetTxSender(); sender.ToString() != "alice" {
+		t.Fatalf("sender = %s, want alice", sender.ToString())
+	}
+	if height, _ := sdk.GetBlockHeight(); height != 1 {
+		t.Fatalf("height = %d, want 1", height)
+	}
+	chain.Mine(10, 60)
+	height, _ := sdk.GetBlockHeight()
+	timestamp, _ := sdk.GetTxTimestamp()
+	if height != 11 || timestamp != genesisTimestamp+60 {
+		t.Fatalf("height = %d, timestamp = %d", height, timestamp)
+	}
+	chain.SetBlockHeight(100)
+	chain.SetTimestamp(5)
+	height, _ = sdk.GetBlockHeight()
+	timestamp, _ = sdk.GetTxTimestamp()
+	if height != 100 || timestamp != 5 {
+		t.Fatalf("height = %d, timestamp = %d", height, timestamp)
+	}
+	if now, _ := common.Clock(sdk); sdk.ClockMode() != common.ClockModeBlockNumber || now != 100 {
+		t.Fatalf("block number clock = %d", now)
+	}
+	chain.SetClockMode(common.ClockModeTimestamp)
+	if now, _ := common.Clock(sdk); now != 5 {
+		t.Fatalf("timestamp clock = %d", now)
+	}
+}
+
+func TestCallContract(t *testing.T) {
+	chain := NewChain()
+	token := chain.Deploy("token", nil)
+	chain.Deploy("receiver", func(sdk *SDK, method string, args []common.KeyValue) common.Response {
+		sender, err := sdk.GetTxSender()
+		if err != nil {
+			return Error(err.Error())
+		}
+		sdk.PutState("method", []byte(method))
+		sdk.EmitEvent("called", sender.ToString())
+		if method == "reject" {
+			return Error("rejected")
+		}
+		return Success([]byte(sender.ToString()))
+	})
+	receiver := NewAccount("receiver")
+	if !token.IsContract(receiver) || token.IsContract(NewAccount("alice")) {
+		t.Fatal("unexpected IsContract result")
+	}
+	err := chain.Invoke(NewAccount("alice"), func() error {
+		response := token.CallContract(receiver, "accept", nil)
+		if response.Status != common.OK {
+			return errors.New(response.Message)
+		}
+		//被调合约看到的发送者是调用方合约
+		if string(response.Payload) != "token" {
+			t.Fatalf("callee sender = %s, want token", response.Payload)
+		}
+		//调用返回后发送者恢复为交易发送者
+		if sender, _ := token.GetTxSender(); sender.ToString() != "alice" {
+			t.Fatalf("sender = %s, want alice", sender.ToString())
+		}
+		response = token.CallContract(receiver, "reject", nil)
+		if response.Status == common.OK || response.Message != "rejected" {
+			t.Fatalf("unexpected response %v", response)
+		}
+		response = token.CallContract(NewAccount("missing"), "accept", nil)
+		if response.Status == common.OK {
+			t.Fatal("call to missing contract should fail")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	//失败的调用写入的状态和事件被回滚
+	if v, _ := chain.NewSDK("receiver").GetState("method"); string(v) != "accept" {
+		t.Fatalf("method = %q, want accept", v)
+	}
+	if events := chain.EventsByTopic("called"); len(events) != 1 {
+		t.Fatalf("got %d called events, want 1", len(events))
+	}
+	chain.Deploy("nohandler", nil)
+	if response := token.CallContract(NewAccount("nohandler"), "m", nil); response.Status == common.OK {
+		t.Fatal("call to contract without handler should fail")
+	}
+}
+
+func TestCreator(t *testing.T) {
+	chain := NewChain()
+	unknown := chain.Deploy("unknown", nil)
+	if _, err := unknown.IsTxSenderCreator(); err == nil {
+		t.Fatal("expected error when sender is not set")
+	}
+	alice := NewAccount("alice")
+	chain.SetSender(alice)
+	sdk := chain.Deploy("token", nil)
+	isCreator, err := sdk.IsTxSenderCreator()
+	if err != nil || !isCreator {
+		t.Fatalf("alice should be the creator, err:%v", err)
+	}
+	chain.SetSender(NewAccount("bob"))
+	if isCreator, err = sdk.IsTxSenderCreator(); err != nil || isCreator {
+		t.Fatalf("bob should not be the creator, err:%v", err)
+	}
+	//合约部署时没有发送者，无法判断创建者
+	if _, err = unknown.IsTxSenderCreator(); err == nil {
+		t.Fatal("expected error for contract without creator")
+	}
+}
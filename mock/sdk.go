@@ -0,0 +1,186 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mock
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"strings"
+
+	"github.com/studyzy/openzeppelin-go/common"
+)
+
+var _ common.ContractSDK = (*SDK)(nil)
+
+const compositeKeySeparator = "\x00"
+
+// SDK 某个合约在模拟链上使用的common.ContractSDK实现
+type SDK struct {
+	chain *Chain
+	name  string
+}
+
+// Chain 返回SDK所在的模拟链
+func (s *SDK) Chain() *Chain {
+	return s.chain
+}
+
+// Account 返回合约自身的地址
+func (s *SDK) Account() Account {
+	return Account(s.name)
+}
+
+func (s *SDK) NewAccountFromBytes(b []byte) (common.Account, error) {
+	return Account(b), nil
+}
+
+func (s *SDK) NewAccountFromString(str string) (common.Account, error) {
+	return Account(str), nil
+}
+
+func (s *SDK) NewZeroAccount() common.Account {
+	return ZeroAccount
+}
+
+func (s *SDK) stateKey(key string) string {
+	return s.name + "/" + key
+}
+
+func (s *SDK) GetState(key string) (value []byte, err error) {
+	return s.chain.getState(s.stateKey(key)), nil
+}
+
+func (s *SDK) PutState(key string, value []byte) error {
+	if len(key) == 0 {
+		return errors.New("mock: empty state key")
+	}
+	s.chain.putState(s.stateKey(key), value)
+	return nil
+}
+
+func (s *SDK) DelState(key string) error {
+	s.chain.delState(s.stateKey(key))
+	return nil
+}
+
+// CreateCompositeKey 与Fabric相同，使用\x00分隔各个部分
+func (s *SDK) CreateCompositeKey(prefix string, data ...string) (string, error) {
+	parts := append([]string{prefix}, data...)
+	for _, p := range parts {
+		if strings.Contains(p, compositeKeySeparator) {
+			return "", errors.New("mock: composite key part contains separator")
+		}
+	}
+	return compositeKeySeparator + strings.Join(parts, compositeKeySeparator) + compositeKeySeparator, nil
+}
+
+func (s *SDK) SplitCompositeKey(compositeKey string) (string, []string, error) {
+	if !strings.HasPrefix(compositeKey, compositeKeySeparator) || !strings.HasSuffix(compositeKey, compositeKeySeparator) ||
+		len(compositeKey) < 2 {
+		return "", nil, errors.New("mock: invalid composite key")
+	}
+	parts := strings.Split(compositeKey[1:len(compositeKey)-1], compositeKeySeparator)
+	return parts[0], parts[1:], nil
+}
+
+// NewIteratorWithPrefix 与Fabric一致，普通键的遍历不会返回组合键
+func (s *SDK) NewIteratorWithPrefix(prefix string) (common.StateIterator, error) {
+	var kvs []common.KeyValue
+	for _, kv := range s.chain.scan(s.stateKey(prefix)) {
+		key := strings.TrimPrefix(kv.Key, s.stateKey(""))
+		if strings.HasPrefix(key, compositeKeySeparator) {
+			continue
+		}
+		kvs = append(kvs, common.KeyValue{Key: key, Value: kv.Value})
+	}
+	return common.NewKVIterator(kvs), nil
+}
+
+func (s *SDK) NewIteratorWithCompositeKey(prefix string, data ...string) (common.StateIterator, error) {
+	partial, err := s.CreateCompositeKey(prefix, data...)
+	if err != nil {
+		return nil, err
+	}
+	var kvs []common.KeyValue
+	for _, kv := range s.chain.scan(s.stateKey(partial)) {
+		kvs = append(kvs, common.KeyValue{Key: strings.TrimPrefix(kv.Key, s.stateKey("")), Value: kv.Value})
+	}
+	return common.NewKVIterator(kvs), nil
+}
+
+func (s *SDK) GetTxSender() (common.Account, error) {
+	return s.chain.Sender()
+}
+
+func (s *SDK) IsTxSenderCreator() (bool, error) {
+	return s.chain.isCreator(s.name)
+}
+
+func (s *SDK) GetTxId() (string, error) {
+	return s.chain.txId, nil
+}
+
+func (s *SDK) GetTxTimestamp() (int64, error) {
+	return s.chain.timestamp, nil
+}
+
+func (s *SDK) GetBlockHeight() (uint64, error) {
+	return s.chain.height, nil
+}
+
+func (s *SDK) ClockMode() string {
+	return s.chain.clockMode
+}
+
+func (s *SDK) EmitEvent(topic string, data ...string) error {
+	s.chain.emitEvent(Event{Contract: s.name, Topic: topic, Data: data})
+	return nil
+}
+
+func (s *SDK) IsContract(account common.Account) bool {
+	_, ok := s.chain.contracts[account.ToString()]
+	return ok
+}
+
+func (s *SDK) CallContract(account common.Account, method string, args []common.KeyValue) common.Response {
+	return s.chain.callContract(s.Account(), account.ToString(), method, args)
+}
+
+// VerifySignature 模拟链上的签名就是Sign生成的sha256(account || digest)
+func (s *SDK) VerifySignature(account common.Account, digest []byte, signature []byte) error {
+	if !bytes.Equal(Sign(Account(account.ToString()), digest), signature) {
+		return errors.New("mock: invalid signature")
+	}
+	return nil
+}
+
+// Sign 以account的身份对digest签名，配合VerifySignature在测试中使用，不具备任何安全性
+func Sign(account Account, digest []byte) []byte {
+	h := sha256.New()
+	h.Write(account.Bytes())
+	h.Write(digest)
+	return h.Sum(nil)
+}
+
+// Success 构造一个调用成功的Response，方便编写模拟合约的Handler
+func Success(payload []byte) common.Response {
+	return common.Response{Status: common.OK, Payload: payload}
+}
+
+// Error 构造一个调用失败的Response，方便编写模拟合约的Handler
+func Error(msg string) common.Response {
+	return common.Response{Status: common.ERROR, Message: msg}
+}
@@ -0,0 +1,120 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mock
+
+import (
+	"testing"
+
+	"github.com/studyzy/openzeppelin-go/common"
+)
+
+func collect(t *testing.T, it common.StateIterator) []string {
+	t.Helper()
+	defer it.Close()
+	var keys []string
+	for it.HasNext() {
+		key, _, err := it.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func TestStateIsolation(t *testing.T) {
+	chain := NewChain()
+	a := chain.Deploy("a", nil)
+	b := chain.Deploy("b", nil)
+	a.PutState("k", []byte("a"))
+	if v, _ := b.GetState("k"); v != nil {
+		t.Fatalf("contract b sees %q", v)
+	}
+	if err := a.PutState("", []byte("x")); err == nil {
+		t.Fatal("empty key should be rejected")
+	}
+	a.DelState("k")
+	if v, _ := a.GetState("k"); v != nil {
+		t.Fatalf("deleted key still has %q", v)
+	}
+}
+
+func TestCompositeKey(t *testing.T) {
+	sdk := NewChain().Deploy("token", nil)
+	key, err := sdk.CreateCompositeKey("a", "owner_1", "spender")
+	if err != nil {
+		t.Fatal(err)
+	}
+	prefix, parts, err := sdk.SplitCompositeKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prefix != "a" || len(parts) != 2 || parts[0] != "owner_1" || parts[1] != "spender" {
+		t.Fatalf("got %s %v", prefix, parts)
+	}
+	if _, err = sdk.CreateCompositeKey("a", "bad\x00part"); err == nil {
+		t.Fatal("separator in part should be rejected")
+	}
+	if _, _, err = sdk.SplitCompositeKey("plain"); err == nil {
+		t.Fatal("plain key should not split")
+	}
+}
+
+func TestIterators(t *testing.T) {
+	sdk := NewChain().Deploy("token", nil)
+	sdk.PutState("b2", []byte("1"))
+	sdk.PutState("b1", []byte("1"))
+	sdk.PutState("c1", []byte("1"))
+	for _, parts := range [][]string{{"x", "1"}, {"x", "2"}, {"y", "1"}} {
+		key, _ := sdk.CreateCompositeKey("a", parts...)
+		sdk.PutState(key, []byte("1"))
+	}
+	it, _ := sdk.NewIteratorWithPrefix("b")
+	if keys := collect(t, it); len(keys) != 2 || keys[0] != "b1" || keys[1] != "b2" {
+		t.Fatalf("prefix scan got %v", keys)
+	}
+	//普通键的遍历不返回组合键
+	it, _ = sdk.NewIteratorWithPrefix("")
+	if keys := collect(t, it); len(keys) != 3 {
+		t.Fatalf("full scan got %v", keys)
+	}
+	it, _ = sdk.NewIteratorWithCompositeKey("a", "x")
+	keys := collect(t, it)
+	if len(keys) != 2 {
+		t.Fatalf("composite scan got %v", keys)
+	}
+	if _, parts, _ := sdk.SplitCompositeKey(keys[1]); parts[1] != "2" {
+		t.Fatalf("unexpected order %v", keys)
+	}
+	it, _ = sdk.NewIteratorWithCompositeKey("a")
+	if keys = collect(t, it); len(keys) != 3 {
+		t.Fatalf("composite scan got %v", keys)
+	}
+}
+
+func TestSignature(t *testing.T) {
+	sdk := NewChain().Deploy("token", nil)
+	alice := NewAccount("alice")
+	digest := []byte("digest")
+	if err := sdk.VerifySignature(alice, digest, Sign(alice, digest)); err != nil {
+		t.Fatal(err)
+	}
+	if err := sdk.VerifySignature(alice, digest, Sign(NewAccount("bob"), digest)); err == nil {
+		t.Fatal("signature of another account should be rejected")
+	}
+	if err := sdk.VerifySignature(alice, []byte("other"), Sign(alice, digest)); err == nil {
+		t.Fatal("signature of another digest should be rejected")
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multisig
+
+import "github.com/studyzy/openzeppelin-go/common"
+
+/**
+ * @dev Interface of a multisignature wallet: any owner can submit a contract call,
+ * which is forwarded once at least `threshold` owners have confirmed it.
+ */
+type IMultiSigWallet interface {
+	/**
+	 * @dev Returns the list of owners.
+	 */
+	GetOwners() ([]common.Account, error)
+
+	/**
+	 * @dev Returns whether `account` is an owner.
+	 */
+	IsOwner(account common.Account) (bool, error)
+
+	/**
+	 * @dev Returns the number of confirmations required to execute a transaction.
+	 */
+	Threshold() (uint64, error)
+
+	/**
+	 * @dev Allows an owner to submit and confirm a transaction.
+	 *
+	 * Emits a {Submission} and a {Confirmation} event.
+	 */
+	SubmitTransaction(target common.Account, method string, args []common.KeyValue) (uint64, error)
+
+	/**
+	 * @dev Allows an owner to confirm a transaction.
+	 *
+	 * Emits a {Confirmation} event.
+	 */
+	ConfirmTransaction(txId uint64) error
+
+	/**
+	 * @dev Allows an owner to revoke a confirmation for a transaction.
+	 *
+	 * Emits a {Revocation} event.
+	 */
+	RevokeConfirmation(txId uint64) error
+
+	/**
+	 * @dev Allows an owner to execute a confirmed transaction.
+	 *
+	 * Emits an {Execution} event.
+	 */
+	ExecuteTransaction(txId uint64) ([]byte, error)
+}
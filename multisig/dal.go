@@ -0,0 +1,156 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multisig
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/studyzy/openzeppelin-go/common"
+)
+
+const (
+	ownersKey           = "owners"
+	thresholdKey        = "threshold"
+	transactionCountKey = "transactionCount"
+	transactionKey      = "transaction"
+	confirmationKey     = "confirmation"
+)
+
+type MultiSigWalletDAL struct {
+	sdk common.StateOperator
+}
+
+func NewMultiSigWalletDAL(sdk common.StateOperator) *MultiSigWalletDAL {
+	return &MultiSigWalletDAL{sdk: sdk}
+}
+
+// transactionRecord 交易在状态数据库中的存储格式
+type transactionRecord struct {
+	Target   string
+	Method   string
+	Args     []common.KeyValue
+	Executed bool
+}
+
+// GetOwners 按添加顺序获得所有owner
+func (c *MultiSigWalletDAL) GetOwners() ([]common.Account, error) {
+	b, err := c.sdk.GetState(ownersKey)
+	if err != nil {
+		return nil, err
+	}
+	owners := make([]common.Account, 0)
+	if len(b) == 0 {
+		return owners, nil
+	}
+	var addresses []string
+	if err = json.Unmarshal(b, &addresses); err != nil {
+		return nil, err
+	}
+	for _, address := range addresses {
+		owner, err := c.sdk.NewAccountFromString(address)
+		if err != nil {
+			return nil, err
+		}
+		owners = append(owners, owner)
+	}
+	return owners, nil
+}
+func (c *MultiSigWalletDAL) SetOwners(owners []common.Account) error {
+	addresses := make([]string, len(owners))
+	for i, owner := range owners {
+		addresses[i] = owner.ToString()
+	}
+	b, err := json.Marshal(addresses)
+	if err != nil {
+		return err
+	}
+	return c.sdk.PutState(ownersKey, b)
+}
+
+func (c *MultiSigWalletDAL) GetThreshold() (uint64, error) {
+	return c.getUint64(thresholdKey)
+}
+func (c *MultiSigWalletDAL) SetThreshold(threshold uint64) error {
+	return c.sdk.PutState(thresholdKey, []byte(strconv.FormatUint(threshold, 10)))
+}
+
+// GetTransactionCount 获得已经提交的交易数量，交易id从0开始
+func (c *MultiSigWalletDAL) GetTransactionCount() (uint64, error) {
+	return c.getUint64(transactionCountKey)
+}
+func (c *MultiSigWalletDAL) SetTransactionCount(count uint64) error {
+	return c.sdk.PutState(transactionCountKey, []byte(strconv.FormatUint(count, 10)))
+}
+
+// GetTransaction 获得交易，交易不存在时返回nil
+func (c *MultiSigWalletDAL) GetTransaction(txId uint64) (*transactionRecord, error) {
+	key, err := c.sdk.CreateCompositeKey(transactionKey, strconv.FormatUint(txId, 10))
+	if err != nil {
+		return nil, err
+	}
+	b, err := c.sdk.GetState(key)
+	if err != nil || len(b) == 0 {
+		return nil, err
+	}
+	transaction := &transactionRecord{}
+	if err = json.Unmarshal(b, transaction); err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+func (c *MultiSigWalletDAL) SetTransaction(txId uint64, transaction *transactionRecord) error {
+	key, err := c.sdk.CreateCompositeKey(transactionKey, strconv.FormatUint(txId, 10))
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(transaction)
+	if err != nil {
+		return err
+	}
+	return c.sdk.PutState(key, b)
+}
+
+func (c *MultiSigWalletDAL) IsConfirmed(txId uint64, owner common.Account) (bool, error) {
+	key, err := c.sdk.CreateCompositeKey(confirmationKey, strconv.FormatUint(txId, 10), owner.ToString())
+	if err != nil {
+		return false, err
+	}
+	b, err := c.sdk.GetState(key)
+	if err != nil {
+		return false, err
+	}
+	return len(b) > 0, nil
+}
+
+// SetConfirmed confirmed为false时删除记录
+func (c *MultiSigWalletDAL) SetConfirmed(txId uint64, owner common.Account, confirmed bool) error {
+	key, err := c.sdk.CreateCompositeKey(confirmationKey, strconv.FormatUint(txId, 10), owner.ToString())
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return c.sdk.DelState(key)
+	}
+	return c.sdk.PutState(key, []byte("true"))
+}
+
+func (c *MultiSigWalletDAL) getUint64(key string) (uint64, error) {
+	b, err := c.sdk.GetState(key)
+	if err != nil || len(b) == 0 {
+		return 0, err
+	}
+	return strconv.ParseUint(string(b), 10, 64)
+}
@@ -0,0 +1,532 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multisig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/studyzy/openzeppelin-go/access"
+	"github.com/studyzy/openzeppelin-go/common"
+)
+
+var _ IMultiSigWallet = (*MultiSigWallet)(nil)
+
+// Transaction 多签钱包中等待确认或者已经执行的一次合约调用
+type Transaction struct {
+	Id       uint64
+	Target   common.Account
+	Method   string
+	Args     []common.KeyValue
+	Executed bool
+}
+
+/**
+ * @dev Multisignature wallet - Allows multiple parties to agree on transactions before execution.
+ *
+ * 把代币合约的管理员角色授予本合约，就可以让mint等特权操作需要M-of-N个owner确认。
+ * 执行时通过CallContract调用目标合约，目标合约看到的调用者就是本合约。
+ *
+ * owner和threshold的修改同样需要多签确认：提交目标为零地址的交易，执行时由钱包自己处理，支持的方法和参数为
+ * addOwner(owner)、removeOwner(owner)、replaceOwner(owner, newOwner)和changeThreshold(threshold)。
+ */
+type MultiSigWallet struct {
+	dal *MultiSigWalletDAL
+	sdk common.ContractSDK
+}
+
+// NewMultiSigWallet MultiSigWallet
+// @param sdk
+// @return *MultiSigWallet
+func NewMultiSigWallet(sdk common.ContractSDK) *MultiSigWallet {
+	return &MultiSigWallet{
+		dal: NewMultiSigWalletDAL(sdk),
+		sdk: sdk,
+	}
+}
+
+/**
+ * @dev Sets initial owners and required number of confirmations.
+ *
+ * Requirements:
+ *
+ * - the caller must be the account that deployed this contract.
+ * - the wallet can only be initialized once.
+ * - owners must be unique and not the zero address.
+ * - `threshold` must be between 1 and the number of owners.
+ */
+func (c *MultiSigWallet) InitMultiSigWallet(owners []common.Account, threshold uint64) error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		if err := access.OnlyCreator(c.sdk); err != nil {
+			return err
+		}
+		current, err := c.dal.GetOwners()
+		if err != nil {
+			return err
+		}
+		if err = common.Require(len(current) == 0, "MultiSigWallet: already initialized"); err != nil {
+			return err
+		}
+		for i, owner := range owners {
+			if err = common.Require(!owner.IsZero(), "MultiSigWallet: owner is the zero address"); err != nil {
+				return err
+			}
+			for _, other := range owners[:i] {
+				if err = common.Require(!owner.Equal(other), "MultiSigWallet: duplicate owner"); err != nil {
+					return err
+				}
+			}
+			if err = c.sdk.EmitEvent("ownerAddition", owner.ToString()); err != nil {
+				return err
+			}
+		}
+		if err = c.dal.SetOwners(owners); err != nil {
+			return err
+		}
+		return c.changeThreshold(threshold, uint64(len(owners)))
+	})
+}
+
+func (c *MultiSigWallet) SetSDK(sdk common.ContractSDK) {
+	c.sdk = sdk
+	c.dal = NewMultiSigWalletDAL(sdk)
+}
+
+func (c *MultiSigWallet) GetOwners() ([]common.Account, error) {
+	return c.dal.GetOwners()
+}
+
+func (c *MultiSigWallet) IsOwner(account common.Account) (bool, error) {
+	owners, err := c.dal.GetOwners()
+	if err != nil {
+		return false, err
+	}
+	return indexOf(owners, account) >= 0, nil
+}
+
+func (c *MultiSigWallet) Threshold() (uint64, error) {
+	return c.dal.GetThreshold()
+}
+
+/**
+ * @dev Returns total number of transactions submitted.
+ */
+func (c *MultiSigWallet) TransactionCount() (uint64, error) {
+	return c.dal.GetTransactionCount()
+}
+
+/**
+ * @dev Returns the transaction `txId`.
+ */
+func (c *MultiSigWallet) GetTransaction(txId uint64) (*Transaction, error) {
+	record, err := c.transaction(txId)
+	if err != nil {
+		return nil, err
+	}
+	target, err := c.sdk.NewAccountFromString(record.Target)
+	if err != nil {
+		return nil, err
+	}
+	return &Transaction{Id: txId, Target: target, Method: record.Method, Args: record.Args,
+		Executed: record.Executed}, nil
+}
+
+/**
+ * @dev Returns the current owners who confirmed transaction `txId`.
+ */
+func (c *MultiSigWallet) GetConfirmations(txId uint64) ([]common.Account, error) {
+	owners, err := c.dal.GetOwners()
+	if err != nil {
+		return nil, err
+	}
+	confirmations := make([]common.Account, 0)
+	for _, owner := range owners {
+		confirmed, err := c.dal.IsConfirmed(txId, owner)
+		if err != nil {
+			return nil, err
+		}
+		if confirmed {
+			confirmations = append(confirmations, owner)
+		}
+	}
+	return confirmations, nil
+}
+
+/**
+ * @dev Returns whether `owner` has confirmed transaction `txId`.
+ */
+func (c *MultiSigWallet) IsConfirmedBy(txId uint64, owner common.Account) (bool, error) {
+	return c.dal.IsConfirmed(txId, owner)
+}
+
+/**
+ * @dev Returns whether transaction `txId` has enough confirmations from current owners.
+ */
+func (c *MultiSigWallet) IsConfirmed(txId uint64) (bool, error) {
+	confirmations, err := c.GetConfirmations(txId)
+	if err != nil {
+		return false, err
+	}
+	threshold, err := c.dal.GetThreshold()
+	if err != nil {
+		return false, err
+	}
+	return uint64(len(confirmations)) >= threshold, nil
+}
+
+func (c *MultiSigWallet) SubmitTransaction(target common.Account, method string, args []common.KeyValue) (uint64, error) {
+	var txId uint64
+	err := common.Atomic(c.sdk, c.SetSDK, func() error {
+		owner, err := c.onlyOwner()
+		if err != nil {
+			return err
+		}
+		//目标为零地址的交易用来修改钱包自己的owner和threshold
+		if _, ok := walletMethods[method]; target.IsZero() && !ok {
+			return fmt.Errorf("MultiSigWallet: unknown wallet method %s", method)
+		}
+		if txId, err = c.dal.GetTransactionCount(); err != nil {
+			return err
+		}
+		if err = c.dal.SetTransaction(txId, &transactionRecord{Target: target.ToString(), Method: method,
+			Args: args}); err != nil {
+			return err
+		}
+		if err = c.dal.SetTransactionCount(txId + 1); err != nil {
+			return err
+		}
+		encodedArgs, err := json.Marshal(args)
+		if err != nil {
+			return err
+		}
+		if err = c.sdk.EmitEvent("submission", strconv.FormatUint(txId, 10), target.ToString(), method,
+			string(encodedArgs)); err != nil {
+			return err
+		}
+		//提交人自动确认
+		return c.confirm(txId, owner)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return txId, nil
+}
+
+func (c *MultiSigWallet) ConfirmTransaction(txId uint64) error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		owner, err := c.onlyOwner()
+		if err != nil {
+			return err
+		}
+		return c.confirm(txId, owner)
+	})
+}
+
+func (c *MultiSigWallet) RevokeConfirmation(txId uint64) error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		owner, err := c.onlyOwner()
+		if err != nil {
+			return err
+		}
+		if _, err = c.notExecuted(txId); err != nil {
+			return err
+		}
+		confirmed, err := c.dal.IsConfirmed(txId, owner)
+		if err != nil {
+			return err
+		}
+		if err = common.Require(confirmed, "MultiSigWallet: transaction not confirmed"); err != nil {
+			return err
+		}
+		if err = c.dal.SetConfirmed(txId, owner, false); err != nil {
+			return err
+		}
+		return c.sdk.EmitEvent("revocation", owner.ToString(), strconv.FormatUint(txId, 10))
+	})
+}
+
+/**
+ * @dev Allows an owner to execute a confirmed transaction. The target is called through
+ * `CallContract` and its payload is returned; the execution fails if the call fails.
+ * A transaction whose target is the zero address changes the owners or the threshold
+ * of this wallet instead.
+ */
+func (c *MultiSigWallet) ExecuteTransaction(txId uint64) ([]byte, error) {
+	var payload []byte
+	err := common.Atomic(c.sdk, c.SetSDK, func() error {
+		if _, err := c.onlyOwner(); err != nil {
+			return err
+		}
+		transaction, err := c.notExecuted(txId)
+		if err != nil {
+			return err
+		}
+		confirmed, err := c.IsConfirmed(txId)
+		if err != nil {
+			return err
+		}
+		if err = common.Require(confirmed, "MultiSigWallet: insufficient confirmations"); err != nil {
+			return err
+		}
+		//先标记为已执行再调用目标合约，避免目标合约重入时重复执行
+		transaction.Executed = true
+		if err = c.dal.SetTransaction(txId, transaction); err != nil {
+			return err
+		}
+		target, err := c.sdk.NewAccountFromString(transaction.Target)
+		if err != nil {
+			return err
+		}
+		if target.IsZero() {
+			if err = c.executeWalletMethod(transaction.Method, transaction.Args); err != nil {
+				return err
+			}
+			return c.sdk.EmitEvent("execution", strconv.FormatUint(txId, 10))
+		}
+		response := c.sdk.CallContract(target, transaction.Method, transaction.Args)
+		if response.Status != common.OK {
+			return fmt.Errorf("MultiSigWallet: call %s.%s failed, err:%s", transaction.Target, transaction.Method,
+				response.Message)
+		}
+		payload = response.Payload
+		return c.sdk.EmitEvent("execution", strconv.FormatUint(txId, 10))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// walletMethods 目标为零地址的交易可以调用的钱包方法
+var walletMethods = map[string]struct{}{
+	"addOwner":        {},
+	"removeOwner":     {},
+	"replaceOwner":    {},
+	"changeThreshold": {},
+}
+
+// executeWalletMethod 执行目标为零地址的交易，参数按名称从args中读取
+func (c *MultiSigWallet) executeWalletMethod(method string, args []common.KeyValue) error {
+	params := make(map[string][]byte, len(args))
+	for _, arg := range args {
+		params[arg.Key] = arg.Value
+	}
+	account := func(key string) (common.Account, error) {
+		b, ok := params[key]
+		if !ok {
+			return nil, errors.New("MultiSigWallet: require account " + key)
+		}
+		return c.sdk.NewAccountFromBytes(b)
+	}
+	switch method {
+	case "addOwner":
+		owner, err := account("owner")
+		if err != nil {
+			return err
+		}
+		return c.addOwner(owner)
+	case "removeOwner":
+		owner, err := account("owner")
+		if err != nil {
+			return err
+		}
+		return c.removeOwner(owner)
+	case "replaceOwner":
+		owner, err := account("owner")
+		if err != nil {
+			return err
+		}
+		newOwner, err := account("newOwner")
+		if err != nil {
+			return err
+		}
+		return c.replaceOwner(owner, newOwner)
+	case "changeThreshold":
+		threshold, err := strconv.ParseUint(string(params["threshold"]), 10, 64)
+		if err != nil {
+			return errors.New("MultiSigWallet: invalid threshold")
+		}
+		owners, err := c.dal.GetOwners()
+		if err != nil {
+			return err
+		}
+		return c.changeThreshold(threshold, uint64(len(owners)))
+	}
+	return fmt.Errorf("MultiSigWallet: unknown wallet method %s", method)
+}
+
+/**
+ * @dev Adds a new owner.
+ *
+ * Emits an {OwnerAddition} event.
+ */
+func (c *MultiSigWallet) addOwner(owner common.Account) error {
+	if err := common.Require(!owner.IsZero(), "MultiSigWallet: owner is the zero address"); err != nil {
+		return err
+	}
+	owners, err := c.dal.GetOwners()
+	if err != nil {
+		return err
+	}
+	if err = common.Require(indexOf(owners, owner) < 0, "MultiSigWallet: owner exists"); err != nil {
+		return err
+	}
+	if err = c.dal.SetOwners(append(owners, owner)); err != nil {
+		return err
+	}
+	return c.sdk.EmitEvent("ownerAddition", owner.ToString())
+}
+
+/**
+ * @dev Removes an owner. The threshold is lowered if it would exceed the number
+ * of remaining owners.
+ *
+ * Emits an {OwnerRemoval} event.
+ */
+func (c *MultiSigWallet) removeOwner(owner common.Account) error {
+	owners, err := c.dal.GetOwners()
+	if err != nil {
+		return err
+	}
+	index := indexOf(owners, owner)
+	if err = common.Require(index >= 0, "MultiSigWallet: owner does not exist"); err != nil {
+		return err
+	}
+	owners = append(owners[:index], owners[index+1:]...)
+	if err = common.Require(len(owners) > 0, "MultiSigWallet: cannot remove the last owner"); err != nil {
+		return err
+	}
+	if err = c.dal.SetOwners(owners); err != nil {
+		return err
+	}
+	if err = c.sdk.EmitEvent("ownerRemoval", owner.ToString()); err != nil {
+		return err
+	}
+	threshold, err := c.dal.GetThreshold()
+	if err != nil {
+		return err
+	}
+	if threshold > uint64(len(owners)) {
+		return c.changeThreshold(uint64(len(owners)), uint64(len(owners)))
+	}
+	return nil
+}
+
+/**
+ * @dev Replaces an owner with a new owner.
+ *
+ * Emits an {OwnerRemoval} and an {OwnerAddition} event.
+ */
+func (c *MultiSigWallet) replaceOwner(owner, newOwner common.Account) error {
+	if err := common.Require(!newOwner.IsZero(), "MultiSigWallet: owner is the zero address"); err != nil {
+		return err
+	}
+	owners, err := c.dal.GetOwners()
+	if err != nil {
+		return err
+	}
+	index := indexOf(owners, owner)
+	if err = common.Require(index >= 0, "MultiSigWallet: owner does not exist"); err != nil {
+		return err
+	}
+	if err = common.Require(indexOf(owners, newOwner) < 0, "MultiSigWallet: owner exists"); err != nil {
+		return err
+	}
+	owners[index] = newOwner
+	if err = c.dal.SetOwners(owners); err != nil {
+		return err
+	}
+	if err = c.sdk.EmitEvent("ownerRemoval", owner.ToString()); err != nil {
+		return err
+	}
+	return c.sdk.EmitEvent("ownerAddition", newOwner.ToString())
+}
+
+func (c *MultiSigWallet) changeThreshold(threshold, ownerCount uint64) error {
+	if err := common.Require(threshold > 0 && threshold <= ownerCount,
+		"MultiSigWallet: invalid threshold"); err != nil {
+		return err
+	}
+	if err := c.dal.SetThreshold(threshold); err != nil {
+		return err
+	}
+	return c.sdk.EmitEvent("requirementChange", strconv.FormatUint(threshold, 10))
+}
+
+func (c *MultiSigWallet) confirm(txId uint64, owner common.Account) error {
+	if _, err := c.notExecuted(txId); err != nil {
+		return err
+	}
+	confirmed, err := c.dal.IsConfirmed(txId, owner)
+	if err != nil {
+		return err
+	}
+	if err = common.Require(!confirmed, "MultiSigWallet: transaction already confirmed"); err != nil {
+		return err
+	}
+	if err = c.dal.SetConfirmed(txId, owner, true); err != nil {
+		return err
+	}
+	return c.sdk.EmitEvent("confirmation", owner.ToString(), strconv.FormatUint(txId, 10))
+}
+
+// onlyOwner 检查调用者是owner，并返回调用者
+func (c *MultiSigWallet) onlyOwner() (common.Account, error) {
+	sender, err := c.sdk.GetTxSender()
+	if err != nil {
+		return nil, fmt.Errorf("Get sender address failed, err:%s", err)
+	}
+	isOwner, err := c.IsOwner(sender)
+	if err != nil {
+		return nil, err
+	}
+	if !isOwner {
+		return nil, fmt.Errorf("MultiSigWallet: account %s is not an owner", sender.ToString())
+	}
+	return sender, nil
+}
+
+func (c *MultiSigWallet) transaction(txId uint64) (*transactionRecord, error) {
+	transaction, err := c.dal.GetTransaction(txId)
+	if err != nil {
+		return nil, err
+	}
+	if transaction == nil {
+		return nil, errors.New("MultiSigWallet: transaction does not exist")
+	}
+	return transaction, nil
+}
+
+func (c *MultiSigWallet) notExecuted(txId uint64) (*transactionRecord, error) {
+	transaction, err := c.transaction(txId)
+	if err != nil {
+		return nil, err
+	}
+	if transaction.Executed {
+		return nil, errors.New("MultiSigWallet: transaction already executed")
+	}
+	return transaction, nil
+}
+
+func indexOf(accounts []common.Account, account common.Account) int {
+	for i, a := range accounts {
+		if a.Equal(account) {
+			return i
+		}
+	}
+	return -1
+}
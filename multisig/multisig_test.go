@@ -0,0 +1,211 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multisig
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/studyzy/openzeppelin-go/common"
+	"github.com/studyzy/openzeppelin-go/erc20"
+	"github.com/studyzy/openzeppelin-go/erc20/erc20mock"
+	"github.com/studyzy/openzeppelin-go/mock"
+)
+
+var (
+	deployer = mock.NewAccount("deployer")
+	alice    = mock.NewAccount("alice")
+	bob      = mock.NewAccount("bob")
+	carol    = mock.NewAccount("carol")
+	dave     = mock.NewAccount("dave")
+)
+
+func mustInvoke(t *testing.T, chain *mock.Chain, sender mock.Account, fn func() error) {
+	t.Helper()
+	if err := chain.Invoke(sender, fn); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mustFail(t *testing.T, chain *mock.Chain, sender mock.Account, fn func() error, msg string) {
+	t.Helper()
+	err := chain.Invoke(sender, fn)
+	if err == nil {
+		t.Fatalf("expected error containing %q", msg)
+	}
+	if !strings.Contains(err.Error(), msg) {
+		t.Fatalf("got error %q, want %q", err, msg)
+	}
+}
+
+// newWallet 部署一个alice、bob、carol三人中两人确认即可执行的多签钱包
+func newWallet(t *testing.T) (*mock.Chain, *MultiSigWallet) {
+	t.Helper()
+	chain := mock.NewChain()
+	chain.SetSender(deployer)
+	wallet := NewMultiSigWallet(chain.Deploy("wallet", nil))
+	owners := []common.Account{alice, bob, carol}
+	mustFail(t, chain, alice, func() error {
+		return wallet.InitMultiSigWallet(owners, 2)
+	}, "Access: caller is not the contract creator")
+	mustFail(t, chain, deployer, func() error {
+		return wallet.InitMultiSigWallet([]common.Account{alice, bob, alice}, 2)
+	}, "MultiSigWallet: duplicate owner")
+	mustFail(t, chain, deployer, func() error {
+		return wallet.InitMultiSigWallet(owners, 4)
+	}, "MultiSigWallet: invalid threshold")
+	mustInvoke(t, chain, deployer, func() error {
+		return wallet.InitMultiSigWallet(owners, 2)
+	})
+	mustFail(t, chain, deployer, func() error {
+		return wallet.InitMultiSigWallet(owners, 2)
+	}, "MultiSigWallet: already initialized")
+	return chain, wallet
+}
+
+func submit(t *testing.T, chain *mock.Chain, wallet *MultiSigWallet, target common.Account, method string,
+	args ...common.KeyValue) uint64 {
+	t.Helper()
+	var txId uint64
+	mustInvoke(t, chain, alice, func() error {
+		var err error
+		txId, err = wallet.SubmitTransaction(target, method, args)
+		return err
+	})
+	return txId
+}
+
+func TestExecuteTransaction(t *testing.T) {
+	chain, wallet := newWallet(t)
+	token, err := erc20mock.Deploy(chain, "token", erc20.Option{}, deployer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mustInvoke(t, chain, deployer, func() error {
+		_, err := token.Mint(mock.NewAccount("wallet"), common.NewSafeUint256(100))
+		return err
+	})
+	txId := submit(t, chain, wallet, mock.NewAccount("token"), "transfer",
+		common.KeyValue{Key: "to", Value: dave.Bytes()}, common.KeyValue{Key: "amount", Value: []byte("40")})
+	mustFail(t, chain, dave, func() error {
+		return wallet.ConfirmTransaction(txId)
+	}, "is not an owner")
+	mustFail(t, chain, alice, func() error {
+		_, err := wallet.ExecuteTransaction(txId)
+		return err
+	}, "MultiSigWallet: insufficient confirmations")
+	mustInvoke(t, chain, bob, func() error {
+		return wallet.ConfirmTransaction(txId)
+	})
+	mustInvoke(t, chain, bob, func() error {
+		_, err := wallet.ExecuteTransaction(txId)
+		return err
+	})
+	balance, err := token.BalanceOf(dave)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !balance.Equal(common.NewSafeUint256(40)) {
+		t.Fatalf("balance = %s, want 40", balance.ToString())
+	}
+	mustFail(t, chain, bob, func() error {
+		_, err := wallet.ExecuteTransaction(txId)
+		return err
+	}, "MultiSigWallet: transaction already executed")
+	//目标合约调用失败时交易不会被标记为已执行
+	txId = submit(t, chain, wallet, mock.NewAccount("token"), "transfer",
+		common.KeyValue{Key: "to", Value: dave.Bytes()}, common.KeyValue{Key: "amount", Value: []byte("100")})
+	mustInvoke(t, chain, carol, func() error {
+		return wallet.ConfirmTransaction(txId)
+	})
+	mustFail(t, chain, carol, func() error {
+		_, err := wallet.ExecuteTransaction(txId)
+		return err
+	}, "exceeds balance")
+	transaction, err := wallet.GetTransaction(txId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transaction.Executed {
+		t.Fatal("failed transaction should not be marked executed")
+	}
+}
+
+func TestWalletMethods(t *testing.T) {
+	chain, wallet := newWallet(t)
+	zero := mock.ZeroAccount
+	mustFail(t, chain, alice, func() error {
+		_, err := wallet.SubmitTransaction(zero, "transfer", nil)
+		return err
+	}, "MultiSigWallet: unknown wallet method transfer")
+	execute := func(txId uint64) {
+		t.Helper()
+		mustInvoke(t, chain, bob, func() error {
+			return wallet.ConfirmTransaction(txId)
+		})
+		mustInvoke(t, chain, bob, func() error {
+			_, err := wallet.ExecuteTransaction(txId)
+			return err
+		})
+	}
+	execute(submit(t, chain, wallet, zero, "addOwner", common.KeyValue{Key: "owner", Value: dave.Bytes()}))
+	if ok, err := wallet.IsOwner(dave); err != nil || !ok {
+		t.Fatalf("dave should be an owner, err:%v", err)
+	}
+	execute(submit(t, chain, wallet, zero, "changeThreshold", common.KeyValue{Key: "threshold", Value: []byte("4")}))
+	threshold, err := wallet.Threshold()
+	if err != nil || threshold != 4 {
+		t.Fatalf("threshold = %d, want 4, err:%v", threshold, err)
+	}
+	//阈值为4时需要所有owner确认
+	txId := submit(t, chain, wallet, zero, "replaceOwner",
+		common.KeyValue{Key: "owner", Value: carol.Bytes()}, common.KeyValue{Key: "newOwner", Value: deployer.Bytes()})
+	for _, owner := range []mock.Account{bob, carol, dave} {
+		mustInvoke(t, chain, owner, func() error {
+			return wallet.ConfirmTransaction(txId)
+		})
+	}
+	mustInvoke(t, chain, dave, func() error {
+		_, err := wallet.ExecuteTransaction(txId)
+		return err
+	})
+	if ok, err := wallet.IsOwner(carol); err != nil || ok {
+		t.Fatalf("carol should no longer be an owner, err:%v", err)
+	}
+	//删除owner之后阈值自动降低
+	txId = submit(t, chain, wallet, zero, "removeOwner", common.KeyValue{Key: "owner", Value: dave.Bytes()})
+	for _, owner := range []mock.Account{bob, dave, deployer} {
+		mustInvoke(t, chain, owner, func() error {
+			return wallet.ConfirmTransaction(txId)
+		})
+	}
+	mustInvoke(t, chain, alice, func() error {
+		_, err := wallet.ExecuteTransaction(txId)
+		return err
+	})
+	threshold, err = wallet.Threshold()
+	if err != nil || threshold != 3 {
+		t.Fatalf("threshold = %d, want 3, err:%v", threshold, err)
+	}
+	//普通账户不能绕过多签直接修改owner
+	mustFail(t, chain, alice, func() error {
+		_, err := wallet.SubmitTransaction(zero, "changeThreshold", []common.KeyValue{{Key: "threshold", Value: []byte("0")}})
+		if err != nil {
+			return err
+		}
+		_, err = wallet.ExecuteTransaction(4)
+		return err
+	}, "MultiSigWallet: insufficient confirmations")
+}
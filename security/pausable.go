@@ -0,0 +1,146 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/studyzy/openzeppelin-go/common"
+)
+
+const pausedKey = "paused"
+
+type PausableDAL struct {
+	sdk common.StateOperator
+}
+
+func NewPausableDAL(sdk common.StateOperator) *PausableDAL {
+	return &PausableDAL{sdk: sdk}
+}
+
+func (c *PausableDAL) GetPaused() (bool, error) {
+	b, err := c.sdk.GetState(pausedKey)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(b, []byte("true")), nil
+}
+func (c *PausableDAL) SetPaused(paused bool) error {
+	if !paused {
+		return c.sdk.DelState(pausedKey)
+	}
+	return c.sdk.PutState(pausedKey, []byte("true"))
+}
+
+/**
+ * @dev Contract module which allows children to implement an emergency stop
+ * mechanism that can be triggered by an authorized account.
+ *
+ * This module is used through composition. It will make available
+ * {WhenNotPaused} and {WhenPaused}, which can be applied to the functions of
+ * your contract. Note that they will not be pausable by simply including
+ * this module, only once the checks are put in place.
+ *
+ * Pause和Unpause本身不做任何权限检查，使用方需要自己限制调用者，
+ * 例如只允许拥有access.PauserRole的账户调用。
+ */
+type Pausable struct {
+	dal *PausableDAL
+	sdk common.ContractSDK
+}
+
+func NewPausable(sdk common.ContractSDK) *Pausable {
+	return &Pausable{
+		sdk: sdk,
+		dal: NewPausableDAL(sdk),
+	}
+}
+
+func (c *Pausable) SetSDK(sdk common.ContractSDK) {
+	c.sdk = sdk
+	c.dal = NewPausableDAL(sdk)
+}
+
+/**
+ * @dev Returns true if the contract is paused, and false otherwise.
+ */
+func (c *Pausable) Paused() (bool, error) {
+	return c.dal.GetPaused()
+}
+
+/**
+ * @dev Throws if the contract is paused.
+ */
+func (c *Pausable) WhenNotPaused() error {
+	paused, err := c.dal.GetPaused()
+	if err != nil {
+		return err
+	}
+	return common.Require(!paused, "Pausable: paused")
+}
+
+/**
+ * @dev Throws if the contract is not paused.
+ */
+func (c *Pausable) WhenPaused() error {
+	paused, err := c.dal.GetPaused()
+	if err != nil {
+		return err
+	}
+	return common.Require(paused, "Pausable: not paused")
+}
+
+/**
+ * @dev Triggers stopped state.
+ *
+ * Requirements:
+ *
+ * - The contract must not be paused.
+ */
+func (c *Pausable) Pause() error {
+	if err := c.WhenNotPaused(); err != nil {
+		return err
+	}
+	if err := c.dal.SetPaused(true); err != nil {
+		return err
+	}
+	sender, err := c.sdk.GetTxSender()
+	if err != nil {
+		return fmt.Errorf("Get sender address failed, err:%s", err)
+	}
+	return c.sdk.EmitEvent("paused", sender.ToString())
+}
+
+/**
+ * @dev Returns to normal state.
+ *
+ * Requirements:
+ *
+ * - The contract must be paused.
+ */
+func (c *Pausable) Unpause() error {
+	if err := c.WhenPaused(); err != nil {
+		return err
+	}
+	if err := c.dal.SetPaused(false); err != nil {
+		return err
+	}
+	sender, err := c.sdk.GetTxSender()
+	if err != nil {
+		return fmt.Errorf("Get sender address failed, err:%s", err)
+	}
+	return c.sdk.EmitEvent("unpaused", sender.ToString())
+}
@@ -0,0 +1,84 @@
+// Copyright 2023 studyzy Author
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package security
+
+import (
+	"github.com/studyzy/openzeppelin-go/access"
+	"github.com/studyzy/openzeppelin-go/common"
+)
+
+/**
+ * @dev {Pausable} whose {Pause} and {Unpause} can only be called by accounts
+ * holding `role`. Token contracts embed it and call {RequireNotPaused} at the
+ * beginning of every state-changing path.
+ */
+type RolePausable struct {
+	*Pausable
+	roles *access.AccessControl
+	role  string
+	name  string
+}
+
+// NewRolePausable 创建一个只有roles中拥有role角色的账户才能暂停和恢复的Pausable，
+// name是合约的名称，例如ERC20，用在暂停期间返回的错误信息中
+func NewRolePausable(sdk common.ContractSDK, roles *access.AccessControl, role string, name string) *RolePausable {
+	return &RolePausable{
+		Pausable: NewPausable(sdk),
+		roles:    roles,
+		role:     role,
+		name:     name,
+	}
+}
+
+/**
+ * @dev Pauses all state-changing operations of the token.
+ *
+ * Requirements:
+ *
+ * - the caller must have the configured role.
+ */
+func (c *RolePausable) Pause() error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		if err := c.roles.OnlyRole(c.role); err != nil {
+			return err
+		}
+		return c.Pausable.Pause()
+	})
+}
+
+/**
+ * @dev Unpauses all state-changing operations of the token.
+ *
+ * Requirements:
+ *
+ * - the caller must have the configured role.
+ */
+func (c *RolePausable) Unpause() error {
+	return common.Atomic(c.sdk, c.SetSDK, func() error {
+		if err := c.roles.OnlyRole(c.role); err != nil {
+			return err
+		}
+		return c.Pausable.Unpause()
+	})
+}
+
+// RequireNotPaused 暂停期间禁止转账、授权、铸币和销毁等所有改变余额和授权的操作
+func (c *RolePausable) RequireNotPaused() error {
+	paused, err := c.Paused()
+	if err != nil {
+		return err
+	}
+	return common.Require(!paused, c.name+"Pausable: token operation while paused")
+}